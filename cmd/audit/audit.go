@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/domain/audit/usecase"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+)
+
+// Verify walks the audit chain and prints its report to out, as JSON when asJSON is set. An error is returned when
+// a link of the chain is broken so that the command exits with a non-zero code.
+func Verify(out io.Writer, asJSON bool) error {
+	config := config.GetConfig()
+
+	conn := db.InitDatabase(config)
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := usecase.NewVerifier(db.NewStore(conn), 0).Run(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot verify audit chain, %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(out, "verified %d events, head hash %s\n", report.Events, report.HeadHash)
+		if !report.Intact() {
+			fmt.Fprintf(out, "broken link at event %d: %s\n  expected %s\n  actual   %s\n",
+				report.Broken.EventID, report.Broken.Reason, report.Broken.Expected, report.Broken.Actual)
+		}
+	}
+
+	if !report.Intact() {
+		return fmt.Errorf("audit chain broken at event %d", report.Broken.EventID)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/domain/fx/usecase"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/currency"
+)
+
+// LoadRates stores the rates of a CSV or JSON file, the format is taken from the file extension
+func LoadRates(file, source string) error {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if source == "" {
+		source = filepath.Base(file)
+	}
+
+	config := config.GetConfig()
+	if err = currency.Configure(config.Currencies.Enabled); err != nil {
+		return err
+	}
+
+	rates, err := usecase.ParseRates(f, format, source)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s: %w", file, err)
+	}
+
+	conn := db.InitDatabase(config)
+	defer conn.Close()
+
+	loaded, err := db.NewStore(conn).LoadFxRatesTx(context.Background(), rates)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logger.Fields{"component": "command", "action": "load fx rates", "file": file}).
+		Infof("loaded %d fx rates", len(loaded))
+
+	return nil
+}
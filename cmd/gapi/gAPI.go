@@ -4,6 +4,7 @@ import (
 	"github.com/dhiemaz/bank-api/config"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
 	"github.com/dhiemaz/bank-api/infrastructure/gapi"
+	"github.com/dhiemaz/bank-api/utils/notify"
 	"log"
 )
 
@@ -14,7 +15,8 @@ func RunGRPCAPIServer() {
 	// Initialize the database
 	conn := db.InitDatabase(config)
 	store := db.NewStore(conn)
-	grpcServer, err := gapi.NewServer(config, store)
+	// account watchers are woken by the entry notifications of the database
+	grpcServer, err := gapi.NewServer(config, store, notify.Start(config.Database.URL))
 	if err != nil {
 		log.Fatalf("cannot create gRPC server, err: %s", err)
 	}
@@ -5,12 +5,15 @@ import (
 	"github.com/dhiemaz/bank-api/config"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
 	"github.com/dhiemaz/bank-api/infrastructure/gapi"
+	"github.com/dhiemaz/bank-api/utils/notify"
 	"log"
 	"net"
 	"net/http"
 
 	"github.com/dhiemaz/bank-api/grpc/pb"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -22,7 +25,8 @@ func RunGateway() {
 	conn := db.InitDatabase(config)
 	store := db.NewStore(conn)
 
-	grpcServer, err := gapi.NewServer(config, store)
+	// the gateway can't serve WatchAccount, its bus is never notified
+	grpcServer, err := gapi.NewServer(config, store, notify.NewBus())
 	if err != nil {
 		log.Fatalf("cannot create gRPC server, err: %s", err)
 	}
@@ -41,7 +45,21 @@ func RunGateway() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if err := pb.RegisterBankServiceHandlerServer(ctx, grpcMux, grpcServer); err != nil {
+	// the gateway calls the gRPC server through a loopback connection, so its calls go through the interceptors
+	// like any other
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("cannot listen for the gRPC server, err: %s", err)
+	}
+
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("cannot serve gRPC server, err: %s", err)
+		}
+	}()
+
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if err := pb.RegisterBankServiceHandlerFromEndpoint(ctx, grpcMux, grpcListener.Addr().String(), dialOpts); err != nil {
 		log.Fatalf("cannot register gRPC server, err %s", err)
 	}
 
@@ -0,0 +1,31 @@
+package holds
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/domain/hold/usecase"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+)
+
+// Run expires the holds past their expiry until the process is interrupted
+func Run() {
+	config := config.GetConfig()
+
+	conn := db.InitDatabase(config)
+	store := db.NewStore(conn)
+
+	expirer := usecase.NewExpirer(store, config.Holds.BatchSize)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.WithFields(logger.Fields{"component": "holds", "action": "run hold expirer"}).
+		Infof("hold expirer is polling every %s", config.Holds.ExpireInterval)
+
+	expirer.Start(ctx, config.Holds.ExpireInterval)
+}
@@ -0,0 +1,73 @@
+package interest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/domain/interest/usecase"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+)
+
+const dateLayout = "2006-01-02"
+
+// Run accrues the interest of every day from from through to, posting it at the end of every month. to defaults
+// to yesterday, the last day with an end of day balance, and from to to.
+func Run(from, to string) error {
+	last := usecase.Day(time.Now()).AddDate(0, 0, -1)
+	if to != "" {
+		var err error
+		if last, err = time.Parse(dateLayout, to); err != nil {
+			return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", to)
+		}
+	}
+
+	first := last
+	if from != "" {
+		var err error
+		if first, err = time.Parse(dateLayout, from); err != nil {
+			return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", from)
+		}
+	}
+
+	if first.After(last) {
+		return fmt.Errorf("from %s is after %s", first.Format(dateLayout), last.Format(dateLayout))
+	}
+
+	if !last.Before(usecase.Day(time.Now())) {
+		return fmt.Errorf("%s isn't over yet, interest accrues on end of day balances", last.Format(dateLayout))
+	}
+
+	config := config.GetConfig()
+
+	products, err := config.AccountProducts()
+	if err != nil {
+		return fmt.Errorf("cannot configure account products, %w", err)
+	}
+
+	conn := db.InitDatabase(config)
+	defer conn.Close()
+
+	accruer := usecase.NewAccruer(db.NewStore(conn), products, config.InterestExpenseAccounts(), config.Interest.BatchSize)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		result, err := accruer.Run(ctx, date)
+		if err != nil {
+			return fmt.Errorf("interest of %s: %w", date.Format(dateLayout), err)
+		}
+
+		logger.WithFields(logger.Fields{"component": "command", "action": "run interest", "date": date.Format(dateLayout)}).
+			Infof("accrued %d accounts (%d already), posted %d (%d already), %d failed",
+				result.Accrued, result.AccrualSkipped, result.Posted, result.PostingSkipped, result.Failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/domain/outbox/usecase"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+)
+
+// Relay publishes the outbox events to the configured sink until the process is interrupted, with once it publishes
+// the pending events and returns. sink, file and natsURL override the configuration when set.
+func Relay(sink, file, natsURL string, once bool) error {
+	config := config.GetConfig()
+	if sink == "" {
+		sink = config.Outbox.Sink
+	}
+	if file == "" {
+		file = config.Outbox.File
+	}
+	if natsURL == "" {
+		natsURL = config.Outbox.NATS.URL
+	}
+
+	publisher, err := newSink(sink, file, natsURL, config)
+	if err != nil {
+		return fmt.Errorf("cannot open outbox sink %s, %w", sink, err)
+	}
+	defer publisher.Close()
+
+	conn := db.InitDatabase(config)
+	defer conn.Close()
+
+	relay := usecase.NewRelay(db.NewStore(conn), publisher, config.Outbox.BatchSize)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if once {
+		published, err := relay.RelayPending(ctx)
+		logger.WithFields(logger.Fields{"component": "outbox", "action": "relay events"}).
+			Infof("published %d outbox events to %s", published, sink)
+		return err
+	}
+
+	logger.WithFields(logger.Fields{"component": "outbox", "action": "relay events"}).
+		Infof("outbox relay is publishing to %s every %s", sink, config.Outbox.PollInterval)
+
+	relay.Start(ctx, config.Outbox.PollInterval)
+	return nil
+}
+
+func newSink(sink, file, natsURL string, config *config.Config) (outbox.Sink, error) {
+	switch sink {
+	case "stdout":
+		return outbox.NewWriterSink(os.Stdout), nil
+	case "file":
+		return outbox.NewFileSink(file)
+	case "nats":
+		return outbox.NewNATSSink(natsURL, config.Outbox.NATS.SubjectPrefix, config.Outbox.NATS.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown sink, expected stdout, file or nats")
+	}
+}
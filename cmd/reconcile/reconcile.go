@@ -0,0 +1,78 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/domain/reconcile/usecase"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+)
+
+// Run verifies the ledger and prints its report to out, as JSON when asJSON is set. An error is returned when the
+// ledger has discrepancies so that the command exits with a non-zero code.
+func Run(out io.Writer, asJSON bool) error {
+	config := config.GetConfig()
+
+	conn := db.InitDatabase(config)
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := usecase.NewReconciler(db.NewStore(conn), config.HouseAccountIDs(), 0).Run(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot reconcile ledger, %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printReport(out, report)
+	}
+
+	if !report.Clean() {
+		return fmt.Errorf("ledger has %d discrepancies", report.Discrepancies)
+	}
+
+	return nil
+}
+
+func printReport(out io.Writer, report usecase.Report) {
+	fmt.Fprintf(out, "ledger checked at %s\n", report.CheckedAt.Format("2006-01-02 15:04:05 MST"))
+
+	fmt.Fprintf(out, "\nbalance drifts: %d\n", len(report.BalanceDrifts))
+	for _, drift := range report.BalanceDrifts {
+		fmt.Fprintf(out, "  account %d (%s, %s): balance %d, entries %d, drift %d\n",
+			drift.AccountID, drift.Owner, drift.Currency, drift.Balance, drift.EntriesTotal, drift.Drift)
+	}
+
+	fmt.Fprintf(out, "\nunbalanced transfers: %d\n", len(report.UnbalancedTransfers))
+	for _, transfer := range report.UnbalancedTransfers {
+		fmt.Fprintf(out, "  transfer %d (%d -> %d):\n", transfer.TransferID, transfer.FromAccountID, transfer.ToAccountID)
+		for _, problem := range transfer.Problems {
+			fmt.Fprintf(out, "    %s\n", problem)
+		}
+	}
+
+	fmt.Fprintln(out, "\ncurrencies:")
+	for _, currency := range report.Currencies {
+		status := "ok"
+		if !currency.Balanced {
+			status = "UNBALANCED"
+		}
+		fmt.Fprintf(out, "  %s: house %d, customers %d, total %d %s\n",
+			currency.Currency, currency.HouseTotal, currency.CustomerTotal, currency.Total, status)
+	}
+
+	fmt.Fprintf(out, "\n%d discrepancies\n", report.Discrepancies)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/dhiemaz/bank-api/config"
 	"github.com/dhiemaz/bank-api/infrastructure"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/notify"
 	"log"
 )
 
@@ -28,7 +29,8 @@ func Run() {
 	store := db.NewStore(conn)
 	query := db.New(conn)
 
-	ginServer, err := infrastructure.NewServer(config, store, query)
+	// account watchers are woken by the entry notifications of the database
+	ginServer, err := infrastructure.NewServer(config, store, query, notify.Start(config.Database.URL))
 	if err != nil {
 		log.Fatalf("cannot create HTTP server, err: %s", err)
 	}
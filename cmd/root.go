@@ -2,13 +2,22 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/dhiemaz/bank-api/cmd/audit"
+	"github.com/dhiemaz/bank-api/cmd/fx"
 	"github.com/dhiemaz/bank-api/cmd/gapi"
 	"github.com/dhiemaz/bank-api/cmd/gateway"
+	"github.com/dhiemaz/bank-api/cmd/holds"
+	"github.com/dhiemaz/bank-api/cmd/interest"
 	"github.com/dhiemaz/bank-api/cmd/migration"
+	"github.com/dhiemaz/bank-api/cmd/outbox"
+	"github.com/dhiemaz/bank-api/cmd/reconcile"
 	"github.com/dhiemaz/bank-api/cmd/rest"
+	"github.com/dhiemaz/bank-api/cmd/scheduler"
+	"github.com/dhiemaz/bank-api/cmd/user"
 	"github.com/dhiemaz/bank-api/config"
 	"github.com/dhiemaz/bank-api/infrastructure/logger"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 type Command struct {
@@ -110,13 +119,223 @@ func (c *Command) Run() {
 					Infof("done migration...")
 			},
 		},
+		{
+			Use:   "scheduler",
+			Short: "Run Banking API scheduled transfers executor",
+			Long:  "Run Banking API scheduled transfers executor, polls due scheduled transfers and executes them",
+			PreRun: func(cmd *cobra.Command, args []string) {
+				// Show display text
+				fmt.Println(fmt.Sprintf(text))
+				config.InitLogger()
+			},
+			Run: func(cmd *cobra.Command, args []string) {
+				scheduler.Run()
+			},
+			PostRun: func(cmd *cobra.Command, args []string) {
+				logger.WithFields(logger.Fields{"component": "command", "action": "run scheduler"}).
+					Infof("scheduler stopped")
+			},
+		},
+		{
+			Use:   "holds",
+			Short: "Run Banking API hold expirer",
+			Long:  "Run Banking API hold expirer, releases the holds that were neither captured nor released before they expired",
+			PreRun: func(cmd *cobra.Command, args []string) {
+				// Show display text
+				fmt.Println(fmt.Sprintf(text))
+				config.InitLogger()
+			},
+			Run: func(cmd *cobra.Command, args []string) {
+				holds.Run()
+			},
+			PostRun: func(cmd *cobra.Command, args []string) {
+				logger.WithFields(logger.Fields{"component": "command", "action": "run hold expirer"}).
+					Infof("hold expirer stopped")
+			},
+		},
 	}
 
+	rootCommands = append(rootCommands, fxCommand(), userCommand(), interestCommand(), reconcileCommand(), auditCommand(), outboxCommand())
+
 	for _, command := range rootCommands {
 		c.rootCmd.AddCommand(command)
 	}
 
-	c.rootCmd.Execute()
+	if err := c.rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// fxCommand groups the exchange rate commands
+func fxCommand() *cobra.Command {
+	var file, source string
+
+	loadCommand := &cobra.Command{
+		Use:   "load",
+		Short: "Load exchange rates from a CSV or JSON file",
+		Long:  "Load exchange rates from a CSV file (base,quote,rate) or a JSON array of {base, quote, rate} objects",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			config.InitLogger()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fx.LoadRates(file, source)
+		},
+	}
+
+	loadCommand.Flags().StringVarP(&file, "file", "f", "", "CSV or JSON file holding the rates")
+	loadCommand.Flags().StringVar(&source, "source", "", "source recorded with the rates, defaults to the file name")
+	loadCommand.MarkFlagRequired("file")
+
+	command := &cobra.Command{
+		Use:   "fx",
+		Short: "Manage Banking API exchange rates",
+		Long:  "Manage Banking API exchange rates",
+	}
+	command.AddCommand(loadCommand)
+
+	return command
+}
+
+// userCommand groups the user management commands
+func userCommand() *cobra.Command {
+	var username, role string
+
+	roleCommand := &cobra.Command{
+		Use:   "role",
+		Short: "Set the role of a user",
+		Long:  "Set the role of a user (customer, support, admin or auditor), it applies from the next login or token renewal",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			config.InitLogger()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return user.SetRole(username, role)
+		},
+	}
+
+	roleCommand.Flags().StringVarP(&username, "username", "u", "", "username of the user")
+	roleCommand.Flags().StringVarP(&role, "role", "r", "", "customer, support, admin or auditor")
+	roleCommand.MarkFlagRequired("username")
+	roleCommand.MarkFlagRequired("role")
+
+	command := &cobra.Command{
+		Use:   "user",
+		Short: "Manage Banking API users",
+		Long:  "Manage Banking API users",
+	}
+	command.AddCommand(roleCommand)
+
+	return command
+}
+
+// interestCommand accrues and posts the interest of account products
+func interestCommand() *cobra.Command {
+	var from, date string
+
+	command := &cobra.Command{
+		Use:   "interest",
+		Short: "Accrue and post Banking API interest",
+		Long: "Accrue the interest of a day on end of day balances and post the interest of the month on its last day. " +
+			"Runs are idempotent per date, a date can be run again safely, --from catches up a range of dates",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			config.InitLogger()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return interest.Run(from, date)
+		},
+	}
+
+	command.Flags().StringVarP(&date, "date", "d", "", "day to accrue (YYYY-MM-DD), yesterday by default")
+	command.Flags().StringVar(&from, "from", "", "first day to accrue (YYYY-MM-DD), only --date by default")
+
+	return command
+}
+
+// reconcileCommand verifies the ledger
+func reconcileCommand() *cobra.Command {
+	var asJSON bool
+
+	command := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Verify the Banking API ledger",
+		Long: "Recompute every account balance from its entries, check that every transfer has exactly one matching " +
+			"debit and credit entry and that every currency sums to zero against the house accounts. " +
+			"Exits with a non-zero code when a discrepancy is found",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			config.InitLogger()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reconcile.Run(cmd.OutOrStdout(), asJSON)
+		},
+	}
+
+	command.Flags().BoolVar(&asJSON, "json", false, "print the report as JSON")
+
+	return command
+}
+
+// auditCommand groups the audit log commands
+func auditCommand() *cobra.Command {
+	var asJSON bool
+
+	verifyCommand := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the hash chain of the audit log",
+		Long: "Walk the audit log from its first event, recompute every hash and report the first broken link. " +
+			"Exits with a non-zero code when the chain is broken",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			config.InitLogger()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return audit.Verify(cmd.OutOrStdout(), asJSON)
+		},
+	}
+
+	verifyCommand.Flags().BoolVar(&asJSON, "json", false, "print the report as JSON")
+
+	command := &cobra.Command{
+		Use:   "audit",
+		Short: "Manage Banking API audit log",
+		Long:  "Manage Banking API audit log",
+	}
+	command.AddCommand(verifyCommand)
+
+	return command
+}
+
+// outboxCommand groups the outbox commands
+func outboxCommand() *cobra.Command {
+	var sink, file, natsURL string
+	var once bool
+
+	relayCommand := &cobra.Command{
+		Use:   "relay",
+		Short: "Publish the domain events of the outbox",
+		Long: "Publish the domain events written to the outbox to stdout, a file of JSON lines or a NATS server, at " +
+			"least once and in order per transfer, account or user. Runs until interrupted, --once drains the outbox and exits",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			config.InitLogger()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return outbox.Relay(sink, file, natsURL, once)
+		},
+	}
+
+	relayCommand.Flags().StringVar(&sink, "sink", "", "stdout, file or nats, outbox.sink by default")
+	relayCommand.Flags().StringVar(&file, "file", "", "file the file sink appends to, outbox.file by default")
+	relayCommand.Flags().StringVar(&natsURL, "nats-url", "", "server of the nats sink, outbox.nats.url by default")
+	relayCommand.Flags().BoolVar(&once, "once", false, "publish the pending events and exit")
+
+	command := &cobra.Command{
+		Use:   "outbox",
+		Short: "Manage Banking API domain events",
+		Long:  "Manage Banking API domain events",
+	}
+	command.AddCommand(relayCommand)
+
+	return command
 }
 
 // GetRoot the command line service
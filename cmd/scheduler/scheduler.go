@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/domain/schedule/usecase"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+)
+
+// Run polls due scheduled transfers and executes them until the process is interrupted
+func Run() {
+	config := config.GetConfig()
+
+	conn := db.InitDatabase(config)
+	store := db.NewStore(conn)
+
+	fees, err := config.FeeSchedule()
+	if err != nil {
+		logger.Fatalf("cannot configure fees, err: %s", err)
+	}
+
+	limits, err := config.TransferLimits()
+	if err != nil {
+		logger.Fatalf("cannot configure transfer limits, err: %s", err)
+	}
+
+	runner := usecase.NewRunner(store, usecase.RunnerConfig{
+		BatchSize:    config.Scheduler.BatchSize,
+		LockTimeout:  config.Scheduler.LockTimeout,
+		RetryBackoff: config.Scheduler.RetryBackoff,
+		MaxBackoff:   config.Scheduler.MaxBackoff,
+
+		FrozenAcceptsCredits: config.Accounts.FrozenAcceptsCredits,
+		Fees:                 fees,
+		Limits:               limits,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.WithFields(logger.Fields{"component": "scheduler", "action": "run scheduler"}).
+		Infof("scheduler is polling every %s", config.Scheduler.PollInterval)
+
+	runner.Start(ctx, config.Scheduler.PollInterval)
+}
@@ -0,0 +1,37 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dhiemaz/bank-api/config"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/rbac"
+)
+
+// SetRole changes the role of a user straight in the database, it is how the first admin is created
+func SetRole(username, role string) error {
+	if !rbac.Role(role).Valid() {
+		return api_error.ErrInvalidRole
+	}
+
+	conn := db.InitDatabase(config.GetConfig())
+	defer conn.Close()
+
+	user, err := db.New(conn).UpdateUserRole(context.Background(), db.UpdateUserRoleParams{Username: username, Role: role})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", api_error.ErrUserNotFound, username)
+		}
+		return err
+	}
+
+	logger.WithFields(logger.Fields{"component": "command", "action": "set user role", "username": username}).
+		Infof("user %s is now %s", user.Username, user.Role)
+
+	return nil
+}
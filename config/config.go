@@ -3,9 +3,14 @@ package config
 import (
 	"fmt"
 	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/interest"
+	"github.com/dhiemaz/bank-api/utils/limit"
 	"github.com/spf13/viper"
 	"log"
+	"sort"
 	"sync"
+	"time"
 )
 
 type Config struct {
@@ -18,6 +23,78 @@ type Config struct {
 		Password      string `mapstructure:"password"`
 		Name          string `mapstructure:"name"`
 	} `mapstructure:"database"`
+	Scheduler struct {
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		BatchSize    int32         `mapstructure:"batch_size"`
+		LockTimeout  time.Duration `mapstructure:"lock_timeout"`
+		RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+		MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+	} `mapstructure:"scheduler"`
+	FX struct {
+		QuoteTTL      time.Duration `mapstructure:"quote_ttl"`
+		HouseAccounts []struct {
+			Currency  string `mapstructure:"currency"`
+			AccountID int64  `mapstructure:"account_id"`
+		} `mapstructure:"house_accounts"`
+	} `mapstructure:"fx"`
+	Accounts struct {
+		// FrozenAcceptsCredits lets frozen accounts keep receiving money, debits are refused either way
+		FrozenAcceptsCredits bool `mapstructure:"frozen_accepts_credits"`
+		// OpeningBalance is paid to new accounts by the funding account of their currency
+		OpeningBalance  int64 `mapstructure:"opening_balance"`
+		FundingAccounts []struct {
+			Currency  string `mapstructure:"currency"`
+			AccountID int64  `mapstructure:"account_id"`
+		} `mapstructure:"funding_accounts"`
+	} `mapstructure:"accounts"`
+	Holds struct {
+		DefaultTTL     time.Duration `mapstructure:"default_ttl"`
+		MaxTTL         time.Duration `mapstructure:"max_ttl"`
+		ExpireInterval time.Duration `mapstructure:"expire_interval"`
+		BatchSize      int32         `mapstructure:"batch_size"`
+	} `mapstructure:"holds"`
+	Fees struct {
+		// RevenueAccounts are the bank accounts fees are credited to, one per currency
+		RevenueAccounts []struct {
+			Currency  string `mapstructure:"currency"`
+			AccountID int64  `mapstructure:"account_id"`
+		} `mapstructure:"revenue_accounts"`
+		Rules []fee.Rule `mapstructure:"rules"`
+	} `mapstructure:"fees"`
+	Limits struct {
+		// Tiers are the transfer limits of every user tier and currency, users are in the standard tier by default
+		Tiers []limit.Limits `mapstructure:"tiers"`
+	} `mapstructure:"limits"`
+	Interest struct {
+		// ExpenseAccounts are the bank accounts paying the interest, one per currency
+		ExpenseAccounts []struct {
+			Currency  string `mapstructure:"currency"`
+			AccountID int64  `mapstructure:"account_id"`
+		} `mapstructure:"expense_accounts"`
+		Products  []interest.Product `mapstructure:"products"`
+		BatchSize int32              `mapstructure:"batch_size"`
+	} `mapstructure:"interest"`
+	Watch struct {
+		// PollInterval is how often watched accounts are read without a notification of their entries
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		// Heartbeat is how often an idle event stream is written to, so that proxies keep it open
+		Heartbeat time.Duration `mapstructure:"heartbeat"`
+	} `mapstructure:"watch"`
+	Outbox struct {
+		// Sink is where the relay publishes the events to, stdout, file or nats
+		Sink         string        `mapstructure:"sink"`
+		File         string        `mapstructure:"file"`
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		BatchSize    int32         `mapstructure:"batch_size"`
+		NATS         struct {
+			URL           string        `mapstructure:"url"`
+			SubjectPrefix string        `mapstructure:"subject_prefix"`
+			Timeout       time.Duration `mapstructure:"timeout"`
+		} `mapstructure:"nats"`
+	} `mapstructure:"outbox"`
+	Currencies struct {
+		Enabled []string `mapstructure:"enabled"`
+	} `mapstructure:"currencies"`
 	Env string `mapstructure:"env"`
 }
 
@@ -45,6 +122,79 @@ func LoadConfig(path string) (*Config, error) {
 	return &newCfg, nil
 }
 
+// FxHouseAccounts returns the configured fx house account id of every currency
+func (c *Config) FxHouseAccounts() map[string]int64 {
+	accounts := make(map[string]int64, len(c.FX.HouseAccounts))
+	for _, house := range c.FX.HouseAccounts {
+		accounts[house.Currency] = house.AccountID
+	}
+	return accounts
+}
+
+// FeeSchedule builds the fee schedule of transfers from the configured rules
+func (c *Config) FeeSchedule() (*fee.Schedule, error) {
+	return fee.NewSchedule(c.Fees.Rules, c.feeRevenueAccounts())
+}
+
+func (c *Config) feeRevenueAccounts() map[string]int64 {
+	accounts := make(map[string]int64, len(c.Fees.RevenueAccounts))
+	for _, revenue := range c.Fees.RevenueAccounts {
+		accounts[revenue.Currency] = revenue.AccountID
+	}
+	return accounts
+}
+
+// TransferLimits builds the transfer limits policy from the configured tiers
+func (c *Config) TransferLimits() (*limit.Policy, error) {
+	return limit.NewPolicy(c.Limits.Tiers)
+}
+
+// AccountProducts builds the catalog of account products from the configured products
+func (c *Config) AccountProducts() (*interest.Catalog, error) {
+	return interest.NewCatalog(c.Interest.Products)
+}
+
+// InterestExpenseAccounts returns the configured interest expense account id of every currency
+func (c *Config) InterestExpenseAccounts() map[string]int64 {
+	accounts := make(map[string]int64, len(c.Interest.ExpenseAccounts))
+	for _, expense := range c.Interest.ExpenseAccounts {
+		accounts[expense.Currency] = expense.AccountID
+	}
+	return accounts
+}
+
+// AccountFundingAccounts returns the configured account funding account id of every currency
+func (c *Config) AccountFundingAccounts() map[string]int64 {
+	accounts := make(map[string]int64, len(c.Accounts.FundingAccounts))
+	for _, funding := range c.Accounts.FundingAccounts {
+		accounts[funding.Currency] = funding.AccountID
+	}
+	return accounts
+}
+
+// HouseAccountIDs returns the ids of every configured bank account, the fx house, fee revenue, interest expense and
+// account funding accounts, sorted
+func (c *Config) HouseAccountIDs() []int64 {
+	seen := make(map[int64]bool)
+	ids := []int64{}
+	for _, accounts := range []map[string]int64{
+		c.FxHouseAccounts(),
+		c.feeRevenueAccounts(),
+		c.InterestExpenseAccounts(),
+		c.AccountFundingAccounts(),
+	} {
+		for _, id := range accounts {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
 // GetConfig ensures the config is loaded only once
 func GetConfig() *Config {
 	once.Do(func() {
@@ -1,26 +1,42 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/dhiemaz/bank-api/domain/account/usecase"
 	"github.com/dhiemaz/bank-api/entities"
-	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
 	"github.com/dhiemaz/bank-api/middlewares"
 	"github.com/dhiemaz/bank-api/utils"
 	"github.com/dhiemaz/bank-api/utils/api_error"
 	"github.com/dhiemaz/bank-api/utils/token"
-	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const defaultHeartbeat = 15 * time.Second
+
 type Handler struct {
 	Usecase usecase.AccountUseCase
+	Watcher usecase.WatchUseCase
+	// Heartbeat is how often an idle event stream is written a comment to
+	Heartbeat time.Duration
 }
 
-func NewAccountHandler(usecase usecase.AccountUseCase) *Handler {
+func NewAccountHandler(usecase usecase.AccountUseCase, watcher usecase.WatchUseCase, heartbeat time.Duration) *Handler {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+
 	return &Handler{
-		Usecase: usecase,
+		Usecase:   usecase,
+		Watcher:   watcher,
+		Heartbeat: heartbeat,
 	}
 }
 
@@ -33,7 +49,7 @@ func NewAccountHandler(usecase usecase.AccountUseCase) *Handler {
 //	@Produce		json
 //	@Param			body	body		createAccountReq	true	"Account to create"
 //	@Success		200		{object}	response.JSON{data=accountResponse}
-//	@Failure		400,500	{object}	response.JSON{}
+//	@Failure		400,403,500	{object}	response.JSON{}
 //	@Security		bearerAuth
 //	@Router			/accounts [post]
 func (account *Handler) CreateAccount(ctx *gin.Context) {
@@ -43,14 +59,9 @@ func (account *Handler) CreateAccount(ctx *gin.Context) {
 	}
 
 	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
-	accountData, err := account.Usecase.AccountRegistration(ctx, payload.Username, request)
+	accountData, err := account.Usecase.AccountRegistration(ctx, payload, request)
 	if err != nil {
-		if err.Error() == "foreign_key_violation" && err.Error() == "unique_violation" {
-			ctx.JSON(http.StatusForbidden, entities.Err(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+		ctx.JSON(accountErrorStatus(err), entities.Err(err))
 		return
 	}
 
@@ -75,17 +86,10 @@ func (account *Handler) GetAccount(ctx *gin.Context) {
 		return
 	}
 
-	accountData, err := account.Usecase.IsValidAccount(ctx, request.ID)
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	accountData, err := account.Usecase.GetOwnedAccount(ctx, payload, request.ID)
 	if err != nil {
-		if err.Error() == "account not found" {
-			ctx.JSON(http.StatusNotFound, entities.Err(err))
-		}
-
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
-	}
-
-	if !isUserAccountOwner(ctx, accountData) {
-		ctx.JSON(http.StatusUnauthorized, api_error.ErrNotAccountOwner)
+		ctx.JSON(accountErrorStatus(err), entities.Err(err))
 		return
 	}
 
@@ -104,7 +108,7 @@ func (account *Handler) GetAccount(ctx *gin.Context) {
 //	@Router			/accounts/del [get]
 func (account *Handler) GetDeletedAccounts(ctx *gin.Context) {
 	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
-	accounts, err := account.Usecase.GetDeletedAccounts(ctx, payload.Username)
+	accounts, err := account.Usecase.GetDeletedAccounts(ctx, payload)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
 		return
@@ -131,7 +135,7 @@ func (account *Handler) GetDeletedAccounts(ctx *gin.Context) {
 func (account *Handler) GetAccounts(ctx *gin.Context) {
 	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
 
-	accounts, err := account.Usecase.GetAccounts(ctx, payload.Username)
+	accounts, err := account.Usecase.GetAccounts(ctx, payload)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
 		return
@@ -147,53 +151,49 @@ func (account *Handler) GetAccounts(ctx *gin.Context) {
 
 // DeleteAccount godoc
 //
-//	@Summary		deletes an account by id for the currently logged-in user
-//	@Description	deletes an account by id for the currently logged-in user
+//	@Summary		closes an account by id for the currently logged-in user
+//	@Description	closes an account by id for the currently logged-in user, a positive balance is swept to another account of the user first
 //	@Tags			accounts
 //	@Produce		json
-//	@Param			id		path		int64	true	"Account ID"
-//	@Success		200		{object}	response.JSON{data=int64}
-//	@Failure		400,500	{object}	response.JSON{}
+//	@Param			id					path		int64	true	"Account ID"
+//	@Param			sweep_to_account_id	query		int64	false	"Account receiving the remaining balance"
+//	@Param			sweep_quote_id		query		string	false	"FX quote for sweeping to an account of another currency"
+//	@Param			reason				query		string	false	"Reason for closing the account"
+//	@Success		200					{object}	response.JSON{data=closeAccountResponse}
+//	@Failure		400,401,403,404,409,500	{object}	response.JSON{}
 //	@Security		bearerAuth
 //	@Router			/accounts/{id} [delete]
 func (account *Handler) DeleteAccount(ctx *gin.Context) {
-	var request entities.DeleteAccountRequest
-	if err := ctx.ShouldBindUri(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, entities.Err(err))
+	var uri entities.DeleteAccountRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
 		return
 	}
 
-	accountData, err := account.Usecase.IsValidAccount(ctx, request.ID)
-	if err != nil {
-		if err.Error() == "account not found" {
-			ctx.JSON(http.StatusNotFound, entities.Err(err))
-		}
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
-	}
-
-	if !isUserAccountOwner(ctx, accountData) {
-		ctx.JSON(http.StatusUnauthorized, entities.Err(api_error.ErrNotAccountOwner))
+	var request entities.CloseAccountRequest
+	if err := utils.ParseQuery(ctx, &request); err != nil {
 		return
 	}
 
-	err = account.Usecase.DeleteAccount(ctx, request.ID)
+	request.AccountID = uri.ID
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	result, err := account.Usecase.DeleteAccount(ctx, payload, request)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+		ctx.JSON(accountErrorStatus(err), entities.Err(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entities.Success(request.ID))
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapCloseAccountToResponse(result)))
 }
 
 // RestoreAccount godoc
 //
-//	@Summary		deletes an account by id for the currently logged-in user
-//	@Description	deletes an account by id for the currently logged-in user
+//	@Summary		reopens a closed or dormant account by id for the currently logged-in user
+//	@Description	reopens a closed or dormant account by id for the currently logged-in user
 //	@Tags			accounts
 //	@Produce		json
 //	@Param			id		path		int64	true	"Account ID"
 //	@Success		200		{object}	response.JSON{data=int64}
-//	@Failure		400,500	{object}	response.JSON{}
+//	@Failure		400,401,403,404,409,500	{object}	response.JSON{}
 //	@Security		bearerAuth
 //	@Router			/accounts/res/{id} [patch]
 func (account *Handler) RestoreAccount(ctx *gin.Context) {
@@ -202,30 +202,128 @@ func (account *Handler) RestoreAccount(ctx *gin.Context) {
 		return
 	}
 
-	accountData, err := account.Usecase.IsValidAccount(ctx, request.ID)
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	err := account.Usecase.RestoreAccount(ctx, payload, request.ID)
 	if err != nil {
-		if err.Error() == "account not found" {
-			ctx.JSON(http.StatusNotFound, entities.Err(err))
-		}
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+		ctx.JSON(accountErrorStatus(err), entities.Err(err))
+		return
 	}
 
-	if !isUserAccountOwner(ctx, accountData) {
-		ctx.JSON(http.StatusUnauthorized, entities.Err(api_error.ErrNotAccountOwner))
+	ctx.JSON(http.StatusOK, entities.Success(request.ID))
+}
+
+// WatchAccount godoc
+//
+//	@Summary		streams the entries posted to an account as server-sent events
+//	@Description	streams the entries posted to an account of the currently logged-in user with the balance after each
+//	@Description	of them. The id of every event is the entry id, a reconnecting client resumes after it with the
+//	@Description	Last-Event-ID header or last_event_id, without either only new entries are streamed.
+//	@Tags			accounts
+//	@Produce		text/event-stream
+//	@Param			id				path		int64	true	"Account ID"
+//	@Param			last_event_id	query		int64	false	"Resume after the event of this id"
+//	@Param			Last-Event-ID	header		int64	false	"Resume after the event of this id"
+//	@Success		200				{object}	entities.AccountEventResponse
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/accounts/{id}/events [get]
+func (account *Handler) WatchAccount(ctx *gin.Context) {
+	var request entities.WatchAccountRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.Err(err))
 		return
 	}
 
-	err = account.Usecase.RestoreAccount(ctx, request.ID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.Err(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entities.Success(request.ID))
-}
+	if lastEventID := ctx.GetHeader("Last-Event-ID"); lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			ctx.JSON(http.StatusBadRequest, entities.Err(errors.New("invalid Last-Event-ID")))
+			return
+		}
+		request.LastEventID = id
+	}
 
-func isUserAccountOwner(ctx *gin.Context, account *db.Account) bool {
+	// checked before the stream starts, its status can't be changed afterwards
 	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
-	log.Println(payload.Username, account.Owner)
-	return payload.Username == account.Owner
+	if _, err := account.Usecase.GetOwnedAccount(ctx, payload, request.ID); err != nil {
+		ctx.JSON(accountErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	events := make(chan entities.AccountEventResponse)
+	done := make(chan error, 1)
+	go func() {
+		done <- account.Watcher.WatchAccount(watchCtx, payload, request.ID, request.LastEventID, func(event entities.AccountEventResponse) error {
+			select {
+			case events <- event:
+				return nil
+			case <-watchCtx.Done():
+				return watchCtx.Err()
+			}
+		})
+	}()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// keeps nginx from buffering the stream
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(account.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(ctx.Writer, "id: %d\nevent: entry\ndata: %s\n\n", event.ID, data)
+		case <-heartbeat.C:
+			fmt.Fprint(ctx.Writer, ": heartbeat\n\n")
+		case err := <-done:
+			// a stream ended by the client isn't an error
+			if ctx.Request.Context().Err() == nil {
+				logger.WithFields(logger.Fields{"component": "handler", "action": "watch account", "account_id": request.ID}).
+					Errorf("account events stream stopped, error : %v", err)
+				fmt.Fprint(ctx.Writer, "event: error\ndata: stream stopped\n\n")
+			}
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, api_error.ErrAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, api_error.ErrAccountFrozen), errors.Is(err, api_error.ErrAccountExists),
+		errors.Is(err, api_error.ErrUserNotFound), errors.Is(err, api_error.ErrNotAccountOwner):
+		return http.StatusForbidden
+	case errors.Is(err, api_error.ErrAccountDeleted):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, api_error.ErrAccountBalanceNotZero), api_error.IsInvalidStatusTransition(err),
+		errors.Is(err, api_error.ErrFxQuoteExpired), errors.Is(err, api_error.ErrFxQuoteUsed):
+		return http.StatusConflict
+	case errors.Is(err, api_error.ErrFxQuoteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, api_error.ErrInvalidSweepAccount), api_error.IsCurrencyMismatch(err),
+		errors.Is(err, api_error.ErrFxQuoteMismatch), errors.Is(err, api_error.ErrFxAmountTooSmall),
+		errors.Is(err, api_error.ErrSameAccountTransfer), errors.Is(err, api_error.ErrInvalidProduct):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
 }
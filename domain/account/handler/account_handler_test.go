@@ -11,6 +11,7 @@ import (
 	"github.com/dhiemaz/bank-api/internal/handlers"
 	"github.com/dhiemaz/bank-api/internal/middlewares"
 	"github.com/dhiemaz/bank-api/util/token"
+	"github.com/dhiemaz/bank-api/utils/random"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -113,10 +114,10 @@ func TestCreateAccount(t *testing.T) {
 
 func createRandomAccount(owner string) db.Account {
 	return db.Account{
-		ID:       utils.RandomInteger(1, 1000),
+		ID:       random.Integer(1, 1000),
 		Owner:    owner,
-		Balance:  utils.RandomMoney(),
-		Currency: utils.RandomCurrency(),
+		Balance:  random.Money(),
+		Currency: random.Currency(),
 	}
 }
 
@@ -313,7 +314,7 @@ func TestListAccount(t *testing.T) {
 }
 
 func TestDeleteAccount(t *testing.T) {
-	account := createRandomAccount(utils.RandomOwner())
+	account := createRandomAccount(random.Owner())
 
 	testCases := []struct {
 		name      string
@@ -326,7 +327,8 @@ func TestDeleteAccount(t *testing.T) {
 			testCaseBase: handlers.testCaseBase{
 				buildStubs: func(store *mockdb.MockStore) {
 					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
-					store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(nil)
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+					store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ChangeAccountStatusTxResult{Account: account}, nil)
 				},
 				checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 					require.Equal(t, http.StatusOK, recorder.Code)
@@ -340,7 +342,7 @@ func TestDeleteAccount(t *testing.T) {
 			accountId: 0,
 			testCaseBase: handlers.testCaseBase{
 				buildStubs: func(store *mockdb.MockStore) {
-					store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(0)
+					store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
 				},
 				checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 					require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -373,7 +375,7 @@ func TestDeleteAccount(t *testing.T) {
 				checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 					require.Equal(t, http.StatusUnauthorized, recorder.Code)
 				}, setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
-					middlewares.addAuthHeader(t, req, maker, middlewares.authorizationTypeBearer, utils.RandomOwner())
+					middlewares.addAuthHeader(t, req, maker, middlewares.authorizationTypeBearer, random.Owner())
 				},
 			},
 		},
@@ -393,7 +395,8 @@ func TestDeleteAccount(t *testing.T) {
 			testCaseBase: handlers.testCaseBase{
 				buildStubs: func(store *mockdb.MockStore) {
 					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
-					store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(sql.ErrConnDone)
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+					store.EXPECT().ChangeAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ChangeAccountStatusTxResult{}, sql.ErrConnDone)
 				},
 				checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 					require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -1,50 +1,90 @@
 package usecase
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	audit "github.com/dhiemaz/bank-api/domain/audit/usecase"
 	"github.com/dhiemaz/bank-api/entities"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
 	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/interest"
 	"github.com/dhiemaz/bank-api/utils/token"
-	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 // AccountUseCase :
 type AccountUseCase interface {
-	AccountRegistration(ctx *gin.Context, username string, request entities.CreateAccountRequest) (*db.Account, error)
-	IsValidAccount(ctx *gin.Context, accountID int64) (*db.Account, error)
-	GetDeletedAccounts(ctx *gin.Context, username string) ([]db.Account, error)
-	GetAccounts(ctx *gin.Context, username string) ([]db.Account, error)
-	DeleteAccount(ctx *gin.Context, accountID int64) error
-	RestoreAccount(ctx *gin.Context, accountID int64) error
+	AccountRegistration(ctx context.Context, caller *token.Payload, request entities.CreateAccountRequest) (*db.Account, error)
+	IsValidAccount(ctx context.Context, accountID int64) (*db.Account, error)
+	GetOwnedAccount(ctx context.Context, caller *token.Payload, accountID int64) (*db.Account, error)
+	GetDeletedAccounts(ctx context.Context, caller *token.Payload) ([]db.Account, error)
+	GetAccounts(ctx context.Context, caller *token.Payload) ([]db.Account, error)
+	DeleteAccount(ctx context.Context, caller *token.Payload, request entities.CloseAccountRequest) (*db.ChangeAccountStatusTxResult, error)
+	RestoreAccount(ctx context.Context, caller *token.Payload, accountID int64) error
 }
 
 type UseCase struct {
-	db  db.Querier
-	jwt token.JWTMaker
+	db              db.Store
+	jwt             token.JWTMaker
+	fxHouseAccounts map[string]int64
+	// products are the account products accounts can be opened with
+	products *interest.Catalog
+	// openingBalance is paid to new accounts by the funding account of their currency
+	openingBalance  int64
+	fundingAccounts map[string]int64
+	// auditor audits the accounts opened, closed and restored, nil audits nothing
+	auditor *audit.Auditor
 }
 
-func NewAccountUseCase(db db.Querier) *UseCase {
-	return &UseCase{db: db}
+func NewAccountUseCase(db db.Store, fxHouseAccounts map[string]int64, products *interest.Catalog, openingBalance int64,
+	fundingAccounts map[string]int64, auditor *audit.Auditor) *UseCase {
+	return &UseCase{
+		db:              db,
+		fxHouseAccounts: fxHouseAccounts,
+		products:        products,
+		openingBalance:  openingBalance,
+		fundingAccounts: fundingAccounts,
+		auditor:         auditor,
+	}
 }
 
-func (account *UseCase) AccountRegistration(ctx *gin.Context, username string, request entities.CreateAccountRequest) (*db.Account, error) {
-	accountData, err := account.db.CreateAccount(ctx, db.CreateAccountParams{
-		Owner:    username,
-		Balance:  1000,
-		Currency: request.Currency,
+// AccountRegistration : open an account of the caller of one of the configured products, checking by default. The opening balance
+// is paid by the funding account of the currency, without one the account opens empty.
+func (account *UseCase) AccountRegistration(ctx context.Context, caller *token.Payload, request entities.CreateAccountRequest) (*db.Account, error) {
+	if request.Product == "" {
+		request.Product = interest.DefaultProduct
+	}
+
+	if _, ok := account.products.Lookup(request.Product); !ok {
+		return nil, api_error.ErrInvalidProduct
+	}
+
+	opened, err := account.db.OpenAccountTx(ctx, db.OpenAccountTxParam{
+		Owner:            caller.Username,
+		Currency:         request.Currency,
+		Product:          request.Product,
+		OpeningBalance:   account.openingBalance,
+		FundingAccountID: account.fundingAccounts[request.Currency],
+		Audit:            account.auditor.OpenAccount(caller),
 	})
+	accountData := opened.Account
 
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
+			case "foreign_key_violation":
 				logger.WithFields(logger.Fields{"component": "usecase", "action": "create account", "data": accountData}).
 					Errorf("failed to create account due to foreign key violation")
 
-				return nil, errors.New(pqErr.Code.Name())
+				return nil, api_error.ErrUserNotFound
+			case "unique_violation":
+				logger.WithFields(logger.Fields{"component": "usecase", "action": "create account", "data": accountData}).
+					Errorf("failed to create account due to unique violation")
+
+				return nil, api_error.ErrAccountExists
 			}
 		}
 
@@ -57,7 +97,7 @@ func (account *UseCase) AccountRegistration(ctx *gin.Context, username string, r
 	return &accountData, nil
 }
 
-func (account *UseCase) IsValidAccount(ctx *gin.Context, accountID int64) (*db.Account, error) {
+func (account *UseCase) IsValidAccount(ctx context.Context, accountID int64) (*db.Account, error) {
 	accountData, err := account.db.GetAccount(ctx, accountID)
 	if err != nil {
 		if err != nil {
@@ -66,7 +106,7 @@ func (account *UseCase) IsValidAccount(ctx *gin.Context, accountID int64) (*db.A
 				Errorf("failed get account with id %v, error : %v", accountID, err)
 
 			if errors.Is(err, sql.ErrNoRows) {
-				return nil, errors.New("not found account")
+				return nil, api_error.ErrAccountNotFound
 			}
 			return nil, err
 		}
@@ -75,11 +115,28 @@ func (account *UseCase) IsValidAccount(ctx *gin.Context, accountID int64) (*db.A
 	return &accountData, nil
 }
 
-func (account *UseCase) GetDeletedAccounts(ctx *gin.Context, username string) ([]db.Account, error) {
-	accountData, err := account.db.GetDeletedAccounts(ctx, username)
+// GetOwnedAccount : an account of the caller, accounts of other users are refused with ErrNotAccountOwner
+func (account *UseCase) GetOwnedAccount(ctx context.Context, caller *token.Payload, accountID int64) (*db.Account, error) {
+	accountData, err := account.IsValidAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountData.Owner != caller.Username {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get owned account", "account_id": accountID}).
+			Errorf("failed get account [%d], account doesn't belong to %s", accountID, caller.Username)
+
+		return nil, api_error.ErrNotAccountOwner
+	}
+
+	return accountData, nil
+}
+
+func (account *UseCase) GetDeletedAccounts(ctx context.Context, caller *token.Payload) ([]db.Account, error) {
+	accountData, err := account.db.GetDeletedAccounts(ctx, caller.Username)
 	if err != nil {
 
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "get deleted accounts", "username": username}).
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get deleted accounts", "username": caller.Username}).
 			Errorf("failed get deleted account, error : %v", err)
 
 		return nil, err
@@ -88,10 +145,10 @@ func (account *UseCase) GetDeletedAccounts(ctx *gin.Context, username string) ([
 	return accountData, nil
 }
 
-func (account *UseCase) GetAccounts(ctx *gin.Context, username string) ([]db.Account, error) {
-	accountData, err := account.db.GetAccounts(ctx, username)
+func (account *UseCase) GetAccounts(ctx context.Context, caller *token.Payload) ([]db.Account, error) {
+	accountData, err := account.db.GetAccounts(ctx, caller.Username)
 	if err != nil {
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "get accounts", "username": username}).
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get accounts", "username": caller.Username}).
 			Errorf("failed get accounts, error : %v", err)
 
 		return nil, err
@@ -99,20 +156,70 @@ func (account *UseCase) GetAccounts(ctx *gin.Context, username string) ([]db.Acc
 	return accountData, nil
 }
 
-func (account *UseCase) DeleteAccount(ctx *gin.Context, accountID int64) error {
-	err := account.db.DeleteAccount(ctx, accountID)
+// DeleteAccount : close an account of the caller. An account with a positive balance is closed only when
+// the balance can be swept to another account of the user, a frozen account can only be closed by staff.
+func (account *UseCase) DeleteAccount(ctx context.Context, caller *token.Payload, request entities.CloseAccountRequest) (*db.ChangeAccountStatusTxResult, error) {
+	accountData, err := account.GetOwnedAccount(ctx, caller, request.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountData.Status == db.AccountStatusFrozen {
+		return nil, api_error.ErrAccountFrozen
+	}
+
+	arg := db.ChangeAccountStatusTxParam{
+		AccountID:        request.AccountID,
+		Status:           db.AccountStatusClosed,
+		Reason:           request.Reason,
+		ChangedBy:        caller.Username,
+		SweepToAccountID: request.SweepToAccountID,
+		HouseAccounts:    account.fxHouseAccounts,
+		Audit:            account.auditor.ChangeAccountStatus(caller, audit.ActionCloseAccount, *accountData),
+	}
+
+	if request.SweepQuoteID != "" {
+		if arg.SweepQuoteID, err = uuid.Parse(request.SweepQuoteID); err != nil {
+			return nil, api_error.ErrFxQuoteNotFound
+		}
+	}
+
+	result, err := account.db.ChangeAccountStatusTx(ctx, arg)
 	if err != nil {
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "delete account", "account_id": accountID}).
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "delete account", "account_id": request.AccountID}).
 			Errorf("failed delete account, error : %v", err)
+
+		return nil, err
 	}
-	return err
+
+	return &result, nil
 }
 
-func (account *UseCase) RestoreAccount(ctx *gin.Context, accountID int64) error {
-	err := account.db.RestoreAccount(ctx, accountID)
+// RestoreAccount : reopen a closed account or reactivate a dormant one of the caller, a frozen account can only be
+// unfrozen by staff
+func (account *UseCase) RestoreAccount(ctx context.Context, caller *token.Payload, accountID int64) error {
+	accountData, err := account.GetOwnedAccount(ctx, caller, accountID)
+	if err != nil {
+		return err
+	}
+
+	if accountData.Status == db.AccountStatusFrozen {
+		return api_error.ErrAccountFrozen
+	}
+
+	_, err = account.db.ChangeAccountStatusTx(ctx, db.ChangeAccountStatusTxParam{
+		AccountID: accountID,
+		Status:    db.AccountStatusActive,
+		ChangedBy: caller.Username,
+		Audit:     account.auditor.ChangeAccountStatus(caller, audit.ActionRestoreAccount, *accountData),
+	})
+
 	if err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "restore an account", "account_id": accountID}).
 			Errorf("failed restoring an account, error : %v", err)
+
+		return err
 	}
-	return err
+
+	return nil
 }
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/notify"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"time"
+)
+
+const (
+	defaultWatchPollInterval = 30 * time.Second
+	watchPageSize            = 100
+)
+
+// WatchUseCase : live feed of the entries of an account of the caller. The entries after lastEventID are sent first,
+// then every new entry as it's committed.
+type WatchUseCase interface {
+	WatchAccount(ctx context.Context, caller *token.Payload, accountID, lastEventID int64,
+		send func(entities.AccountEventResponse) error) error
+}
+
+type Watcher struct {
+	db      db.Store
+	account AccountUseCase
+	bus     *notify.Bus
+	// pollInterval is how often the entries are read without a notification, in case one was lost
+	pollInterval time.Duration
+}
+
+func NewWatcher(db db.Store, account AccountUseCase, bus *notify.Bus, pollInterval time.Duration) *Watcher {
+	if pollInterval <= 0 {
+		pollInterval = defaultWatchPollInterval
+	}
+
+	return &Watcher{
+		db:           db,
+		account:      account,
+		bus:          bus,
+		pollInterval: pollInterval,
+	}
+}
+
+// WatchAccount : sends the entries posted to an account of the caller, with the balance after each of them, in order
+// until ctx is done or send fails. Entries after lastEventID are sent first, with lastEventID 0 only the entries
+// posted from now on are. The entries are read when the bus is notified of the account, the database stays the
+// source of the events so none are skipped when a notification is lost.
+func (watcher *Watcher) WatchAccount(ctx context.Context, caller *token.Payload, accountID, lastEventID int64,
+	send func(entities.AccountEventResponse) error) error {
+	accountData, err := watcher.account.GetOwnedAccount(ctx, caller, accountID)
+	if err != nil {
+		return err
+	}
+
+	// subscribed before the entries are read so that entries posted in between wake the watcher
+	wakeups, cancel := watcher.bus.Subscribe(accountID)
+	defer cancel()
+
+	if lastEventID <= 0 {
+		lastEventID, err = watcher.db.GetLatestEntryID(ctx, accountID)
+		if err != nil {
+			logger.WithFields(logger.Fields{"component": "usecase", "action": "watch account", "account_id": accountID}).
+				Errorf("failed get latest entry of account, error : %v", err)
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(watcher.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		lastEventID, err = watcher.sendEvents(ctx, accountData.Currency, accountID, lastEventID, send)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wakeups:
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendEvents sends the entries of the account after lastEventID page by page and returns the id of the last one sent
+func (watcher *Watcher) sendEvents(ctx context.Context, code string, accountID, lastEventID int64,
+	send func(entities.AccountEventResponse) error) (int64, error) {
+	for {
+		events, err := watcher.db.ListAccountEvents(ctx, db.ListAccountEventsParams{
+			AccountID: accountID,
+			AfterID:   lastEventID,
+			PageSize:  watchPageSize,
+		})
+		if err != nil {
+			logger.WithFields(logger.Fields{"component": "usecase", "action": "watch account", "account_id": accountID}).
+				Errorf("failed list events after %d, error : %v", lastEventID, err)
+			return lastEventID, err
+		}
+
+		for _, event := range events {
+			if err = send(utils.MapAccountEventToResponse(event, code)); err != nil {
+				return lastEventID, err
+			}
+			lastEventID = event.ID
+		}
+
+		if len(events) < watchPageSize {
+			return lastEventID, nil
+		}
+	}
+}
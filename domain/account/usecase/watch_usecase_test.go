@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/entities"
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/notify"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var errStopWatching = errors.New("stop watching")
+
+func newTestWatcher(store db.Store, bus *notify.Bus) *Watcher {
+	return NewWatcher(store, NewAccountUseCase(store, nil, nil, 0, nil, nil), bus, time.Hour)
+}
+
+func TestWatchAccountResume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), int64(1)).Return(db.Account{ID: 1, Owner: "alice", Currency: "USD"}, nil)
+	store.EXPECT().GetLatestEntryID(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().ListAccountEvents(gomock.Any(), db.ListAccountEventsParams{AccountID: 1, AfterID: 10, PageSize: watchPageSize}).
+		Return([]db.ListAccountEventsRow{
+			{ID: 11, AccountID: 1, Amount: -250, Balance: 750, TransferID: sql.NullInt64{Int64: 5, Valid: true}},
+			{ID: 12, AccountID: 1, Amount: 100, Balance: 850},
+		}, nil)
+
+	var sent []entities.AccountEventResponse
+	err := newTestWatcher(store, notify.NewBus()).WatchAccount(context.Background(), &token.Payload{Username: "alice"}, 1, 10,
+		func(event entities.AccountEventResponse) error {
+			sent = append(sent, event)
+			if len(sent) == 2 {
+				return errStopWatching
+			}
+			return nil
+		})
+	require.ErrorIs(t, err, errStopWatching)
+
+	require.Len(t, sent, 2)
+	require.Equal(t, int64(11), sent[0].ID)
+	require.Equal(t, "-2.50", sent[0].AmountDecimal)
+	require.Equal(t, "7.50", sent[0].BalanceDecimal)
+	require.Equal(t, int64(5), *sent[0].TransferID)
+	require.Nil(t, sent[1].TransferID)
+}
+
+func TestWatchAccountWakeup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bus := notify.NewBus()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), int64(1)).Return(db.Account{ID: 1, Owner: "alice", Currency: "USD"}, nil)
+	// without a last event only the entries from now on are sent
+	store.EXPECT().GetLatestEntryID(gomock.Any(), int64(1)).Return(int64(20), nil)
+	gomock.InOrder(
+		store.EXPECT().ListAccountEvents(gomock.Any(), db.ListAccountEventsParams{AccountID: 1, AfterID: 20, PageSize: watchPageSize}).
+			DoAndReturn(func(context.Context, db.ListAccountEventsParams) ([]db.ListAccountEventsRow, error) {
+				// the transfer commits once the watcher has caught up
+				bus.Publish(1)
+				return []db.ListAccountEventsRow{}, nil
+			}),
+		store.EXPECT().ListAccountEvents(gomock.Any(), db.ListAccountEventsParams{AccountID: 1, AfterID: 20, PageSize: watchPageSize}).
+			Return([]db.ListAccountEventsRow{{ID: 21, AccountID: 1, Amount: 100, Balance: 1100}}, nil),
+	)
+
+	var sent []entities.AccountEventResponse
+	err := newTestWatcher(store, bus).WatchAccount(context.Background(), &token.Payload{Username: "alice"}, 1, 0,
+		func(event entities.AccountEventResponse) error {
+			sent = append(sent, event)
+			return errStopWatching
+		})
+	require.ErrorIs(t, err, errStopWatching)
+	require.Len(t, sent, 1)
+	require.Equal(t, int64(21), sent[0].ID)
+}
+
+func TestWatchAccountCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), int64(1)).Return(db.Account{ID: 1, Owner: "alice", Currency: "USD"}, nil)
+	store.EXPECT().ListAccountEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, db.ListAccountEventsParams) ([]db.ListAccountEventsRow, error) {
+			cancel()
+			return []db.ListAccountEventsRow{}, nil
+		})
+
+	err := newTestWatcher(store, notify.NewBus()).WatchAccount(ctx, &token.Payload{Username: "alice"}, 1, 5,
+		func(entities.AccountEventResponse) error { return nil })
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWatchAccountNotOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), int64(1)).Return(db.Account{ID: 1, Owner: "bob", Currency: "USD"}, nil)
+	store.EXPECT().ListAccountEvents(gomock.Any(), gomock.Any()).Times(0)
+
+	err := newTestWatcher(store, notify.NewBus()).WatchAccount(context.Background(), &token.Payload{Username: "alice"}, 1, 0,
+		func(entities.AccountEventResponse) error { return nil })
+	require.ErrorIs(t, err, api_error.ErrNotAccountOwner)
+}
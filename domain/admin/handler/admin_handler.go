@@ -0,0 +1,447 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/dhiemaz/bank-api/domain/admin/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/middlewares"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/rbac"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	Usecase usecase.AdminUseCase
+}
+
+func NewAdminHandler(usecase usecase.AdminUseCase) *Handler {
+	return &Handler{
+		Usecase: usecase,
+	}
+}
+
+// SearchUsers godoc
+//
+//	@Summary		searches users
+//	@Description	searches users on part of their username, email or full name, sorted on username
+//	@Tags			admin
+//	@Produce		json
+//	@Param			q				query		string	false	"Part of the username, email or full name"
+//	@Param			role			query		string	false	"Role of the users"	Enums(customer, support, admin, auditor)
+//	@Param			cursor			query		string	false	"Cursor of the page"
+//	@Param			limit			query		int		false	"Page size"
+//	@Success		200				{object}	response.JSON{data=[]userResponse}
+//	@Failure		400,401,403,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/users [get]
+func (admin *Handler) SearchUsers(ctx *gin.Context) {
+	var request entities.SearchUsersRequest
+	if err := utils.ParseQuery(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	users, nextCursor, err := admin.Usecase.SearchUsers(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	response := make([]entities.UserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, utils.MapUserToResponse(&users[i]))
+	}
+
+	ctx.JSON(http.StatusOK, entities.SuccessPage(response, nextCursor))
+}
+
+// GetUser godoc
+//
+//	@Summary		gets a user
+//	@Description	gets a user with every account it owns, deleted ones included
+//	@Tags			admin
+//	@Produce		json
+//	@Param			username			path		string	true	"Username"
+//	@Success		200					{object}	response.JSON{data=adminUserResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/users/{username} [get]
+func (admin *Handler) GetUser(ctx *gin.Context) {
+	var request entities.UsernameURIRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	user, accounts, err := admin.Usecase.GetUser(ctx, payload, request.Username)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	response := entities.AdminUserResponse{
+		UserResponse: utils.MapUserToResponse(user),
+		Accounts:     make([]entities.AdminAccountResponse, 0, len(accounts)),
+	}
+	for i := range accounts {
+		response.Accounts = append(response.Accounts, utils.MapAccountToAdminResponse(&accounts[i]))
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(response))
+}
+
+// SetUserRole godoc
+//
+//	@Summary		sets the role of a user
+//	@Description	sets the role of a user, it applies from the next login or token renewal of the user
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			username			path		string				true	"Username"
+//	@Param			body				body		setUserRoleRequest	true	"Role"
+//	@Success		200					{object}	response.JSON{data=userResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/users/{username}/role [put]
+func (admin *Handler) SetUserRole(ctx *gin.Context) {
+	var uri entities.UsernameURIRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.SetUserRoleRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	user, err := admin.Usecase.SetUserRole(ctx, payload, uri.Username, rbac.Role(request.Role))
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapUserToResponse(user)))
+}
+
+// SetUserTier godoc
+//
+//	@Summary		sets the transfer limits tier of a user
+//	@Description	moves a user to another configured transfer limits tier, it applies from the next transfer of the user
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			username			path		string				true	"Username"
+//	@Param			body				body		setUserTierRequest	true	"Tier"
+//	@Success		200					{object}	response.JSON{data=userResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/users/{username}/tier [put]
+func (admin *Handler) SetUserTier(ctx *gin.Context) {
+	var uri entities.UsernameURIRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.SetUserTierRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	user, err := admin.Usecase.SetUserTier(ctx, payload, uri.Username, request.Tier)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapUserToResponse(user)))
+}
+
+// GetAccount godoc
+//
+//	@Summary		gets any account
+//	@Description	gets an account whoever owns it
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id					path		int64	true	"Account ID"
+//	@Success		200					{object}	response.JSON{data=adminAccountResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/accounts/{id} [get]
+func (admin *Handler) GetAccount(ctx *gin.Context) {
+	var request entities.GetAccountRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	account, err := admin.Usecase.GetAccount(ctx, payload, request.ID)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapAccountToAdminResponse(account)))
+}
+
+// FreezeAccount godoc
+//
+//	@Summary		freezes an account
+//	@Description	freezes an account, it can't be debited anymore until it is unfrozen
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		int64					true	"Account ID"
+//	@Param			body				body		freezeAccountRequest	true	"Reason"
+//	@Success		200					{object}	response.JSON{data=adminAccountResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/accounts/{id}/freeze [post]
+func (admin *Handler) FreezeAccount(ctx *gin.Context) {
+	admin.setFrozen(ctx, admin.Usecase.FreezeAccount)
+}
+
+// UnfreezeAccount godoc
+//
+//	@Summary		unfreezes an account
+//	@Description	lifts the freeze of an account
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		int64					true	"Account ID"
+//	@Param			body				body		freezeAccountRequest	true	"Reason"
+//	@Success		200					{object}	response.JSON{data=adminAccountResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/accounts/{id}/unfreeze [post]
+func (admin *Handler) UnfreezeAccount(ctx *gin.Context) {
+	admin.setFrozen(ctx, admin.Usecase.UnfreezeAccount)
+}
+
+func (admin *Handler) setFrozen(ctx *gin.Context, set func(ctx context.Context, caller *token.Payload, accountID int64, reason string) (*db.Account, error)) {
+	var uri entities.GetAccountRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.FreezeAccountRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	account, err := set(ctx, payload, uri.ID, request.Reason)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapAccountToAdminResponse(account)))
+}
+
+// SetAccountStatus godoc
+//
+//	@Summary		changes the status of an account
+//	@Description	moves an account to active, frozen, dormant or closed, a positive balance is swept to another account of the owner when closing
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id							path		int64						true	"Account ID"
+//	@Param			body						body		setAccountStatusRequest		true	"Status and reason"
+//	@Success		200							{object}	response.JSON{data=closeAccountResponse}
+//	@Failure		400,401,403,404,409,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/accounts/{id}/status [put]
+func (admin *Handler) SetAccountStatus(ctx *gin.Context) {
+	var uri entities.GetAccountRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.SetAccountStatusRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	result, err := admin.Usecase.SetAccountStatus(ctx, payload, uri.ID, request)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapCloseAccountToResponse(result)))
+}
+
+// GetAccountStatusChanges godoc
+//
+//	@Summary		lists the status changes of an account
+//	@Description	lists every status change of an account, latest first
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id					path		int64	true	"Account ID"
+//	@Success		200					{object}	response.JSON{data=[]accountStatusChangeResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/accounts/{id}/status [get]
+func (admin *Handler) GetAccountStatusChanges(ctx *gin.Context) {
+	var uri entities.GetAccountRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	changes, err := admin.Usecase.GetAccountStatusChanges(ctx, payload, uri.ID)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	response := make([]entities.AccountStatusChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		response = append(response, utils.MapAccountStatusChangeToResponse(change))
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(response))
+}
+
+// GetAccountTransfers godoc
+//
+//	@Summary		lists the transfers of any account
+//	@Description	lists the transfers of an account whoever owns it, latest first
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id						path		int64	true	"Account ID"
+//	@Param			direction				query		string	false	"Direction of the transfers"	Enums(incoming, outgoing)
+//	@Param			min_amount				query		int64	false	"Minimum amount"
+//	@Param			max_amount				query		int64	false	"Maximum amount"
+//	@Param			from					query		string	false	"Created at or after (RFC 3339)"
+//	@Param			to						query		string	false	"Created before (RFC 3339)"
+//	@Param			counterparty_account_id	query		int64	false	"Other account of the transfers"
+//	@Param			cursor					query		string	false	"Cursor of the page"
+//	@Param			limit					query		int		false	"Page size"
+//	@Success		200						{object}	response.JSON{data=[]transferResponse}
+//	@Failure		400,401,403,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/accounts/{id}/transfers [get]
+func (admin *Handler) GetAccountTransfers(ctx *gin.Context) {
+	var request entities.GetAccountRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	var filter entities.ListTransfersFilter
+	if err := utils.ParseQuery(ctx, &filter); err != nil {
+		return
+	}
+
+	pgQuery, err := utils.ParsePagination(ctx)
+	if err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	transfers, nextCursor, err := admin.Usecase.GetAccountTransfers(ctx, payload, request.ID, filter, pgQuery)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	response := []*entities.TransferResponse{}
+	for _, transfer := range transfers {
+		response = append(response, utils.MapTransferToResponse(transfer))
+	}
+
+	ctx.JSON(http.StatusOK, entities.SuccessPage(response, nextCursor))
+}
+
+// GetTransfer godoc
+//
+//	@Summary		gets any transfer
+//	@Description	gets a transfer whoever made it
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id					path		int64	true	"Transfer ID"
+//	@Success		200					{object}	response.JSON{data=transferResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/transfers/{id} [get]
+func (admin *Handler) GetTransfer(ctx *gin.Context) {
+	var request entities.TransferURIRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	transfer, err := admin.Usecase.GetTransfer(ctx, payload, request.ID)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapTransferToResponse(*transfer)))
+}
+
+// GetActions godoc
+//
+//	@Summary		lists the admin actions
+//	@Description	lists the actions taken through the admin API, latest first
+//	@Tags			admin
+//	@Produce		json
+//	@Param			actor			query		string	false	"Username of the staff member"
+//	@Param			cursor			query		string	false	"Cursor of the page"
+//	@Param			limit			query		int		false	"Page size"
+//	@Success		200				{object}	response.JSON{data=[]adminActionResponse}
+//	@Failure		400,401,403,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/admin/actions [get]
+func (admin *Handler) GetActions(ctx *gin.Context) {
+	var request entities.ListAdminActionsRequest
+	if err := utils.ParseQuery(ctx, &request); err != nil {
+		return
+	}
+
+	pgQuery, err := utils.ParsePagination(ctx)
+	if err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	actions, nextCursor, err := admin.Usecase.GetActions(ctx, payload, request.Actor, pgQuery)
+	if err != nil {
+		ctx.JSON(adminErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	response := make([]entities.AdminActionResponse, 0, len(actions))
+	for _, action := range actions {
+		response = append(response, utils.MapAdminActionToResponse(action))
+	}
+
+	ctx.JSON(http.StatusOK, entities.SuccessPage(response, nextCursor))
+}
+
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, api_error.ErrInvalidCursor), errors.Is(err, api_error.ErrInvalidRole), errors.Is(err, api_error.ErrInvalidTier),
+		errors.Is(err, api_error.ErrInvalidAccountStatus), errors.Is(err, api_error.ErrInvalidSweepAccount),
+		api_error.IsCurrencyMismatch(err), errors.Is(err, api_error.ErrFxQuoteMismatch),
+		errors.Is(err, api_error.ErrFxAmountTooSmall):
+		return http.StatusBadRequest
+	case api_error.IsInvalidStatusTransition(err), errors.Is(err, api_error.ErrAccountBalanceNotZero),
+		errors.Is(err, api_error.ErrFxQuoteExpired), errors.Is(err, api_error.ErrFxQuoteUsed):
+		return http.StatusConflict
+	case errors.Is(err, api_error.ErrUserNotFound), errors.Is(err, api_error.ErrAccountNotFound),
+		errors.Is(err, api_error.ErrTransferNotFound), errors.Is(err, api_error.ErrFxQuoteNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,453 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/dhiemaz/bank-api/domain/transaction/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/limit"
+	"github.com/dhiemaz/bank-api/utils/rbac"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/google/uuid"
+)
+
+// Actions recorded in admin_actions
+const (
+	ActionSearchUsers          = "search_users"
+	ActionViewUser             = "view_user"
+	ActionSetUserRole          = "set_user_role"
+	ActionSetUserTier          = "set_user_tier"
+	ActionViewAccount          = "view_account"
+	ActionFreezeAccount        = "freeze_account"
+	ActionUnfreezeAccount      = "unfreeze_account"
+	ActionSetAccountStatus     = "set_account_status"
+	ActionViewAccountStatus    = "view_account_status"
+	ActionViewAccountTransfers = "view_account_transfers"
+	ActionViewTransfer         = "view_transfer"
+	ActionViewAdminActions     = "view_admin_actions"
+)
+
+// AdminUseCase : back-office operations on any user, account or transfer. Every call is recorded in admin_actions,
+// reads before they run and changes in the transaction of the change once it's made. A call that can't be recorded
+// doesn't run or is rolled back.
+type AdminUseCase interface {
+	SearchUsers(ctx context.Context, caller *token.Payload, request entities.SearchUsersRequest) ([]db.User, string, error)
+	GetUser(ctx context.Context, caller *token.Payload, username string) (*db.User, []db.Account, error)
+	SetUserRole(ctx context.Context, caller *token.Payload, username string, role rbac.Role) (*db.User, error)
+	SetUserTier(ctx context.Context, caller *token.Payload, username string, tier string) (*db.User, error)
+	GetAccount(ctx context.Context, caller *token.Payload, accountID int64) (*db.Account, error)
+	FreezeAccount(ctx context.Context, caller *token.Payload, accountID int64, reason string) (*db.Account, error)
+	UnfreezeAccount(ctx context.Context, caller *token.Payload, accountID int64, reason string) (*db.Account, error)
+	SetAccountStatus(ctx context.Context, caller *token.Payload, accountID int64, request entities.SetAccountStatusRequest) (*db.ChangeAccountStatusTxResult, error)
+	GetAccountStatusChanges(ctx context.Context, caller *token.Payload, accountID int64) ([]db.AccountStatusChange, error)
+	GetAccountTransfers(ctx context.Context, caller *token.Payload, accountID int64, filter entities.ListTransfersFilter, pagination *utils.PaginationQuery) ([]db.Transfer, string, error)
+	GetTransfer(ctx context.Context, caller *token.Payload, transferID int64) (*db.Transfer, error)
+	GetActions(ctx context.Context, caller *token.Payload, actor string, pagination *utils.PaginationQuery) ([]db.AdminAction, string, error)
+}
+
+type UseCase struct {
+	db              db.Store
+	fxHouseAccounts map[string]int64
+	// limits are the transfer limits of every tier, users can only be moved to a configured tier
+	limits *limit.Policy
+}
+
+func NewAdminUseCase(db db.Store, fxHouseAccounts map[string]int64, limits *limit.Policy) *UseCase {
+	return &UseCase{db: db, fxHouseAccounts: fxHouseAccounts, limits: limits}
+}
+
+// adminAction : the admin action of the caller, details are kept as JSON
+func (admin *UseCase) adminAction(ctx context.Context, caller *token.Payload, action, target string, details interface{}) (db.CreateAdminActionParams, error) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return db.CreateAdminActionParams{}, err
+	}
+
+	return db.CreateAdminActionParams{
+		Actor:   caller.Username,
+		Role:    string(caller.Role),
+		Action:  action,
+		Target:  target,
+		Details: raw,
+	}, nil
+}
+
+// record : store the admin action of a read before it runs
+func (admin *UseCase) record(ctx context.Context, caller *token.Payload, action, target string, details interface{}) error {
+	arg, err := admin.adminAction(ctx, caller, action, target, details)
+	if err != nil {
+		return err
+	}
+
+	if _, err = admin.db.CreateAdminAction(ctx, arg); err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": action, "actor": arg.Actor, "target": target}).
+			Errorf("failed record admin action, error : %v", err)
+
+		return err
+	}
+
+	logAction(arg)
+	return nil
+}
+
+func logAction(arg db.CreateAdminActionParams) {
+	logger.WithFields(logger.Fields{"component": "usecase", "action": arg.Action, "actor": arg.Actor, "target": arg.Target}).
+		Infof("admin action by %s (%s) on %s", arg.Actor, arg.Role, arg.Target)
+}
+
+// SearchUsers : one page of the users matching the request, sorted on username
+func (admin *UseCase) SearchUsers(ctx context.Context, caller *token.Payload, request entities.SearchUsersRequest) ([]db.User, string, error) {
+	if request.Limit == 0 {
+		request.Limit = utils.DefaultPageSize
+	}
+
+	arg := db.SearchUsersParams{
+		Query:    sql.NullString{String: request.Query, Valid: request.Query != ""},
+		Role:     sql.NullString{String: request.Role, Valid: request.Role != ""},
+		PageSize: request.Limit + 1,
+	}
+
+	if request.Cursor != "" {
+		after, err := utils.DecodeKeyCursor(request.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		arg.AfterUsername = sql.NullString{String: after, Valid: true}
+	}
+
+	if err := admin.record(ctx, caller, ActionSearchUsers, "users", request); err != nil {
+		return nil, "", err
+	}
+
+	users, err := admin.db.SearchUsers(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "search users", "payload": request}).
+			Errorf("failed search users, error : %v", err)
+
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(users) > int(request.Limit) {
+		users = users[:request.Limit]
+		nextCursor = utils.EncodeKeyCursor(users[len(users)-1].Username)
+	}
+
+	return users, nextCursor, nil
+}
+
+// GetUser : a user with every account it owns, deleted ones included
+func (admin *UseCase) GetUser(ctx context.Context, caller *token.Payload, username string) (*db.User, []db.Account, error) {
+	if err := admin.record(ctx, caller, ActionViewUser, userTarget(username), nil); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := admin.getUser(ctx, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accounts, err := admin.db.GetAccounts(ctx, username)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get user", "username": username}).
+			Errorf("failed get accounts of user, error : %v", err)
+
+		return nil, nil, err
+	}
+
+	deleted, err := admin.db.GetDeletedAccounts(ctx, username)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get user", "username": username}).
+			Errorf("failed get deleted accounts of user, error : %v", err)
+
+		return nil, nil, err
+	}
+
+	return user, append(accounts, deleted...), nil
+}
+
+// SetUserRole : change the role of a user, it applies to the access tokens issued from the next login or renewal
+func (admin *UseCase) SetUserRole(ctx context.Context, caller *token.Payload, username string, role rbac.Role) (*db.User, error) {
+	if !role.Valid() {
+		return nil, api_error.ErrInvalidRole
+	}
+
+	action, err := admin.adminAction(ctx, caller, ActionSetUserRole, userTarget(username), map[string]interface{}{"role": role})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := admin.db.SetUserRoleTx(ctx, db.SetUserRoleTxParam{
+		UpdateUserRoleParams: db.UpdateUserRoleParams{Username: username, Role: string(role)},
+		Action:               action,
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "set user role", "username": username, "role": role}).
+			Errorf("failed set user role, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	logAction(action)
+	return &user, nil
+}
+
+// SetUserTier : move a user to another transfer limits tier, it applies to the next transfer of the user
+func (admin *UseCase) SetUserTier(ctx context.Context, caller *token.Payload, username string, tier string) (*db.User, error) {
+	if !admin.limits.ValidTier(tier) {
+		return nil, api_error.ErrInvalidTier
+	}
+
+	action, err := admin.adminAction(ctx, caller, ActionSetUserTier, userTarget(username), map[string]interface{}{"tier": tier})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := admin.db.SetUserTierTx(ctx, db.SetUserTierTxParam{
+		UpdateUserTierParams: db.UpdateUserTierParams{Username: username, Tier: tier},
+		Action:               action,
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "set user tier", "username": username, "tier": tier}).
+			Errorf("failed set user tier, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	logAction(action)
+	return &user, nil
+}
+
+// GetAccount : any account, whoever owns it
+func (admin *UseCase) GetAccount(ctx context.Context, caller *token.Payload, accountID int64) (*db.Account, error) {
+	if err := admin.record(ctx, caller, ActionViewAccount, accountTarget(accountID), nil); err != nil {
+		return nil, err
+	}
+
+	return admin.getAccount(ctx, accountID)
+}
+
+// FreezeAccount : stop any debit of an account, credits are accepted as long as the policy allows it
+func (admin *UseCase) FreezeAccount(ctx context.Context, caller *token.Payload, accountID int64, reason string) (*db.Account, error) {
+	return admin.setFrozen(ctx, caller, ActionFreezeAccount, accountID, db.AccountStatusFrozen, reason)
+}
+
+// UnfreezeAccount : lift the freeze of an account
+func (admin *UseCase) UnfreezeAccount(ctx context.Context, caller *token.Payload, accountID int64, reason string) (*db.Account, error) {
+	return admin.setFrozen(ctx, caller, ActionUnfreezeAccount, accountID, db.AccountStatusActive, reason)
+}
+
+func (admin *UseCase) setFrozen(ctx context.Context, caller *token.Payload, action string, accountID int64, status, reason string) (*db.Account, error) {
+	arg := db.ChangeAccountStatusTxParam{AccountID: accountID, Status: status, Reason: reason}
+	result, err := admin.changeStatus(ctx, caller, action, map[string]interface{}{"reason": reason}, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Account, nil
+}
+
+// SetAccountStatus : move an account to any status its current status allows, closing an account with a positive
+// balance sweeps it to another account of the owner
+func (admin *UseCase) SetAccountStatus(ctx context.Context, caller *token.Payload, accountID int64, request entities.SetAccountStatusRequest) (*db.ChangeAccountStatusTxResult, error) {
+	if !db.ValidAccountStatus(request.Status) {
+		return nil, api_error.ErrInvalidAccountStatus
+	}
+
+	arg := db.ChangeAccountStatusTxParam{
+		AccountID:        accountID,
+		Status:           request.Status,
+		Reason:           request.Reason,
+		SweepToAccountID: request.SweepToAccountID,
+	}
+
+	if request.SweepQuoteID != "" {
+		quoteID, err := uuid.Parse(request.SweepQuoteID)
+		if err != nil {
+			return nil, api_error.ErrFxQuoteNotFound
+		}
+		arg.SweepQuoteID = quoteID
+	}
+
+	return admin.changeStatus(ctx, caller, ActionSetAccountStatus, request, arg)
+}
+
+// GetAccountStatusChanges : every status change of any account, latest first
+func (admin *UseCase) GetAccountStatusChanges(ctx context.Context, caller *token.Payload, accountID int64) ([]db.AccountStatusChange, error) {
+	if err := admin.record(ctx, caller, ActionViewAccountStatus, accountTarget(accountID), nil); err != nil {
+		return nil, err
+	}
+
+	if _, err := admin.getAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	changes, err := admin.db.ListAccountStatusChanges(ctx, accountID)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get account status changes", "account_id": accountID}).
+			Errorf("failed get account status changes, error : %v", err)
+
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// changeStatus : run the status change of arg on behalf of the caller, recorded as action with details
+func (admin *UseCase) changeStatus(ctx context.Context, caller *token.Payload, action string, details interface{}, arg db.ChangeAccountStatusTxParam) (*db.ChangeAccountStatusTxResult, error) {
+	adminAction, err := admin.adminAction(ctx, caller, action, accountTarget(arg.AccountID), details)
+	if err != nil {
+		return nil, err
+	}
+
+	arg.ChangedBy = adminAction.Actor
+	arg.HouseAccounts = admin.fxHouseAccounts
+	arg.AdminAction = &adminAction
+
+	result, err := admin.db.ChangeAccountStatusTx(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "change account status", "account_id": arg.AccountID, "status": arg.Status}).
+			Errorf("failed change account status, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrAccountNotFound
+		}
+
+		return nil, err
+	}
+
+	logAction(adminAction)
+	return &result, nil
+}
+
+// GetAccountTransfers : one page of the transfers of any account, latest first
+func (admin *UseCase) GetAccountTransfers(ctx context.Context, caller *token.Payload, accountID int64, filter entities.ListTransfersFilter, pagination *utils.PaginationQuery) ([]db.Transfer, string, error) {
+	if err := admin.record(ctx, caller, ActionViewAccountTransfers, accountTarget(accountID), filter); err != nil {
+		return nil, "", err
+	}
+
+	if _, err := admin.getAccount(ctx, accountID); err != nil {
+		return nil, "", err
+	}
+
+	transfers, nextCursor, err := usecase.ListAccountTransfers(ctx, admin.db, accountID, filter, pagination)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get account transfers", "account_id": accountID}).
+			Errorf("failed get account transfers, error : %v", err)
+
+		return nil, "", err
+	}
+
+	return transfers, nextCursor, nil
+}
+
+// GetTransfer : any transfer
+func (admin *UseCase) GetTransfer(ctx context.Context, caller *token.Payload, transferID int64) (*db.Transfer, error) {
+	if err := admin.record(ctx, caller, ActionViewTransfer, "transfer:"+strconv.FormatInt(transferID, 10), nil); err != nil {
+		return nil, err
+	}
+
+	transfer, err := admin.db.GetTransfer(ctx, transferID)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer", "transfer_id": transferID}).
+			Errorf("failed get transfer, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrTransferNotFound
+		}
+
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// GetActions : one page of the admin actions, latest first, only those of actor when it is set
+func (admin *UseCase) GetActions(ctx context.Context, caller *token.Payload, actor string, pagination *utils.PaginationQuery) ([]db.AdminAction, string, error) {
+	if err := admin.record(ctx, caller, ActionViewAdminActions, "admin_actions", map[string]interface{}{"actor": actor}); err != nil {
+		return nil, "", err
+	}
+
+	arg := db.ListAdminActionsParams{
+		Actor:    sql.NullString{String: actor, Valid: actor != ""},
+		PageSize: pagination.FetchSize(),
+	}
+	arg.AfterCreatedAt, arg.AfterID = pagination.AfterParams()
+
+	actions, err := admin.db.ListAdminActions(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get admin actions", "actor": actor}).
+			Errorf("failed get admin actions, error : %v", err)
+
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if pagination.HasNextPage(len(actions)) {
+		actions = actions[:pagination.Limit]
+		last := actions[len(actions)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return actions, nextCursor, nil
+}
+
+func (admin *UseCase) getUser(ctx context.Context, username string) (*db.User, error) {
+	user, err := admin.db.GetUser(ctx, username)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get user", "username": username}).
+			Errorf("failed get user, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (admin *UseCase) getAccount(ctx context.Context, accountID int64) (*db.Account, error) {
+	account, err := admin.db.GetAccount(ctx, accountID)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get account", "account_id": accountID}).
+			Errorf("failed get account, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrAccountNotFound
+		}
+
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func userTarget(username string) string {
+	return "user:" + username
+}
+
+func accountTarget(accountID int64) string {
+	return "account:" + strconv.FormatInt(accountID, 10)
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/token"
+)
+
+// Actions recorded in audit_events
+const (
+	ActionCreateTransfer      = "create_transfer"
+	ActionReverseTransfer     = "reverse_transfer"
+	ActionCreateTransferBatch = "create_transfer_batch"
+	ActionOpenAccount         = "open_account"
+	ActionCloseAccount        = "close_account"
+	ActionRestoreAccount      = "restore_account"
+	ActionRegisterUser        = "register_user"
+	ActionUpdateUser          = "update_user"
+)
+
+// Auditor builds the audit events of the changes made by the usecases. An event is appended to the audit chain by
+// the transaction of its change, a change is committed with its event or not at all. A nil Auditor audits nothing.
+type Auditor struct{}
+
+func NewAuditor() *Auditor {
+	return &Auditor{}
+}
+
+// CreateTransfer audits a transfer of the caller
+func (auditor *Auditor) CreateTransfer(caller *token.Payload) func(result db.TransferTxResult) (audit.Event, error) {
+	if auditor == nil {
+		return nil
+	}
+
+	return func(result db.TransferTxResult) (audit.Event, error) {
+		return newEvent(caller, ActionCreateTransfer, TransferTarget(result.Transfer.ID), nil, result)
+	}
+}
+
+// ReverseTransfer audits a reversal of original by the caller, the event targets the original transfer
+func (auditor *Auditor) ReverseTransfer(caller *token.Payload, original db.Transfer) func(result db.ReverseTransferTxResult) (audit.Event, error) {
+	if auditor == nil {
+		return nil
+	}
+
+	return func(result db.ReverseTransferTxResult) (audit.Event, error) {
+		return newEvent(caller, ActionReverseTransfer, TransferTarget(original.ID), original, result)
+	}
+}
+
+// CreateTransferBatch audits a batch of the caller once it's finished
+func (auditor *Auditor) CreateTransferBatch(caller *token.Payload) func(result db.TransferBatchTxResult) (audit.Event, error) {
+	if auditor == nil {
+		return nil
+	}
+
+	return func(result db.TransferBatchTxResult) (audit.Event, error) {
+		return newEvent(caller, ActionCreateTransferBatch, BatchTarget(result.Batch.ID), nil, result)
+	}
+}
+
+// OpenAccount audits an account opened by the caller
+func (auditor *Auditor) OpenAccount(caller *token.Payload) func(result db.OpenAccountTxResult) (audit.Event, error) {
+	if auditor == nil {
+		return nil
+	}
+
+	return func(result db.OpenAccountTxResult) (audit.Event, error) {
+		return newEvent(caller, ActionOpenAccount, AccountTarget(result.Account.ID), nil, result)
+	}
+}
+
+// ChangeAccountStatus audits a status change of before by the caller, action is ActionCloseAccount or
+// ActionRestoreAccount
+func (auditor *Auditor) ChangeAccountStatus(caller *token.Payload, action string, before db.Account) func(result db.ChangeAccountStatusTxResult) (audit.Event, error) {
+	if auditor == nil {
+		return nil
+	}
+
+	return func(result db.ChangeAccountStatusTxResult) (audit.Event, error) {
+		return newEvent(caller, action, AccountTarget(before.ID), before, result)
+	}
+}
+
+// RegisterUser audits a user registering, the user is the actor as there is no access token yet
+func (auditor *Auditor) RegisterUser() func(user db.User) (audit.Event, error) {
+	if auditor == nil {
+		return nil
+	}
+
+	return func(user db.User) (audit.Event, error) {
+		return eventAs(user.Username, user.Role, ActionRegisterUser, UserTarget(user.Username), nil, utils.MapUserToResponse(&user))
+	}
+}
+
+// UpdateUser audits an update of before by the caller, the users are kept without their password
+func (auditor *Auditor) UpdateUser(caller *token.Payload, before db.User) func(user db.User) (audit.Event, error) {
+	if auditor == nil {
+		return nil
+	}
+
+	return func(user db.User) (audit.Event, error) {
+		return newEvent(caller, ActionUpdateUser, UserTarget(user.Username), utils.MapUserToResponse(&before), utils.MapUserToResponse(&user))
+	}
+}
+
+// newEvent builds an event of the caller, before and after are the target around the action and are kept as JSON,
+// nil when the target didn't exist
+func newEvent(caller *token.Payload, action, target string, before, after interface{}) (audit.Event, error) {
+	var actor, role string
+	if caller != nil {
+		actor, role = caller.Username, string(caller.Role)
+	}
+
+	return eventAs(actor, role, action, target, before, after)
+}
+
+// eventAs builds an event of an actor that isn't authenticated yet, like a user registering
+func eventAs(actor, role, action, target string, before, after interface{}) (audit.Event, error) {
+	event := audit.Event{
+		Actor:     actor,
+		Role:      role,
+		Action:    action,
+		Target:    target,
+		CreatedAt: time.Now(),
+	}
+
+	var err error
+	if event.Before, err = marshalPayload(before); err != nil {
+		return event, err
+	}
+
+	event.After, err = marshalPayload(after)
+	return event, err
+}
+
+func marshalPayload(payload interface{}) (json.RawMessage, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	return json.Marshal(payload)
+}
+
+// TransferTarget names a transfer in the events
+func TransferTarget(transferID int64) string {
+	return "transfer:" + strconv.FormatInt(transferID, 10)
+}
+
+// AccountTarget names an account in the events
+func AccountTarget(accountID int64) string {
+	return "account:" + strconv.FormatInt(accountID, 10)
+}
+
+// BatchTarget names a transfer batch in the events
+func BatchTarget(batchID int64) string {
+	return "batch:" + strconv.FormatInt(batchID, 10)
+}
+
+// UserTarget names a user in the events
+func UserTarget(username string) string {
+	return "user:" + username
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/rbac"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditorChangeAccountStatus(t *testing.T) {
+	caller := &token.Payload{Username: "alice", Role: rbac.RoleCustomer}
+	before := db.Account{ID: 7, Status: db.AccountStatusActive}
+
+	build := NewAuditor().ChangeAccountStatus(caller, ActionCloseAccount, before)
+	event, err := build(db.ChangeAccountStatusTxResult{Account: db.Account{ID: 7, Status: db.AccountStatusClosed}})
+	require.NoError(t, err)
+
+	require.Equal(t, "alice", event.Actor)
+	require.Equal(t, "customer", event.Role)
+	require.Equal(t, ActionCloseAccount, event.Action)
+	require.Equal(t, "account:7", event.Target)
+	require.Contains(t, string(event.Before), `"status":"active"`)
+	require.Contains(t, string(event.After), `"status":"closed"`)
+	require.False(t, event.CreatedAt.IsZero())
+}
+
+func TestAuditorRegisterUser(t *testing.T) {
+	build := NewAuditor().RegisterUser()
+	event, err := build(db.User{Username: "bob", HashedPassword: "secret", Role: "customer"})
+	require.NoError(t, err)
+
+	// the user registers themselves, there is no access token yet
+	require.Equal(t, "bob", event.Actor)
+	require.Equal(t, "customer", event.Role)
+	require.Equal(t, "user:bob", event.Target)
+	require.Nil(t, event.Before)
+	require.NotContains(t, string(event.After), "secret")
+
+	var after map[string]interface{}
+	require.NoError(t, json.Unmarshal(event.After, &after))
+	require.Equal(t, "bob", after["username"])
+}
+
+func TestAuditorCreateTransfer(t *testing.T) {
+	build := NewAuditor().CreateTransfer(&token.Payload{Username: "alice", Role: rbac.RoleCustomer})
+	event, err := build(db.TransferTxResult{Transfer: db.Transfer{ID: 12, Amount: 10}})
+	require.NoError(t, err)
+
+	require.Equal(t, ActionCreateTransfer, event.Action)
+	require.Equal(t, "transfer:12", event.Target)
+	require.Nil(t, event.Before)
+}
+
+func TestNilAuditor(t *testing.T) {
+	var none *Auditor
+	require.Nil(t, none.CreateTransfer(nil))
+	require.Nil(t, none.RegisterUser())
+	require.Nil(t, none.ChangeAccountStatus(nil, ActionRestoreAccount, db.Account{}))
+}
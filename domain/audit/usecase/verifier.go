@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/audit"
+)
+
+// VerifyReport is the result of walking the audit chain, Broken is the first broken link and nil for an intact chain.
+// HeadHash is the hash of the last event verified, keeping it elsewhere also proves that no event was cut from the end.
+type VerifyReport struct {
+	Events   int64       `json:"events"`
+	HeadHash string      `json:"head_hash"`
+	Broken   *BrokenLink `json:"broken,omitempty"`
+}
+
+// BrokenLink is an event that doesn't chain to the event before it or whose content doesn't match its hash
+type BrokenLink struct {
+	EventID  int64  `json:"event_id"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// Intact is true when every event of the chain was verified
+func (report VerifyReport) Intact() bool {
+	return report.Broken == nil
+}
+
+// Verifier walks the audit chain from its first event and recomputes every hash
+type Verifier struct {
+	db        db.Store
+	batchSize int32
+}
+
+func NewVerifier(db db.Store, batchSize int32) *Verifier {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &Verifier{db: db, batchSize: batchSize}
+}
+
+// Run verifies the chain up to its first broken link
+func (verifier *Verifier) Run(ctx context.Context) (VerifyReport, error) {
+	report := VerifyReport{HeadHash: audit.GenesisHash}
+	arg := db.ListAuditEventsParams{PageSize: verifier.batchSize}
+
+	for {
+		events, err := verifier.db.ListAuditEvents(ctx, arg)
+		if err != nil {
+			return report, err
+		}
+
+		for _, event := range events {
+			if event.PrevHash != report.HeadHash {
+				report.Broken = &BrokenLink{
+					EventID:  event.ID,
+					Reason:   "previous hash doesn't match the hash of the previous event",
+					Expected: report.HeadHash,
+					Actual:   event.PrevHash,
+				}
+				return report, nil
+			}
+
+			hash, err := audit.Hash(event.PrevHash, audit.Event{
+				Actor:     event.Actor,
+				Role:      event.Role,
+				Action:    event.Action,
+				Target:    event.Target,
+				Before:    event.Before,
+				After:     event.After,
+				CreatedAt: event.CreatedAt,
+			})
+			if err != nil || hash != event.Hash {
+				report.Broken = &BrokenLink{
+					EventID:  event.ID,
+					Reason:   "content doesn't match the hash of the event",
+					Expected: hash,
+					Actual:   event.Hash,
+				}
+				return report, nil
+			}
+
+			report.Events++
+			report.HeadHash = event.Hash
+		}
+
+		if len(events) < int(arg.PageSize) {
+			return report, nil
+		}
+		arg.AfterID = events[len(events)-1].ID
+	}
+}
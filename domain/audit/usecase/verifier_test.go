@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// chain builds n chained events as the store appends them
+func chain(t *testing.T, n int) []db.AuditEvent {
+	events := make([]db.AuditEvent, 0, n)
+	prevHash := audit.GenesisHash
+
+	for i := 1; i <= n; i++ {
+		event := audit.Event{
+			Actor:     "alice",
+			Role:      "customer",
+			Action:    ActionCreateTransfer,
+			Target:    TransferTarget(int64(i)),
+			Before:    json.RawMessage("null"),
+			After:     json.RawMessage(`{"amount":10}`),
+			CreatedAt: time.Date(2024, 3, 14, 10, i, 0, 0, time.UTC),
+		}
+
+		hash, err := audit.Hash(prevHash, event)
+		require.NoError(t, err)
+
+		events = append(events, db.AuditEvent{
+			ID:        int64(i),
+			Actor:     event.Actor,
+			Role:      event.Role,
+			Action:    event.Action,
+			Target:    event.Target,
+			Before:    event.Before,
+			After:     event.After,
+			PrevHash:  prevHash,
+			Hash:      hash,
+			CreatedAt: event.CreatedAt,
+		})
+		prevHash = hash
+	}
+
+	return events
+}
+
+func TestVerifierRunIntact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := chain(t, 3)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAuditEvents(gomock.Any(), db.ListAuditEventsParams{PageSize: 2}).Return(events[:2], nil)
+	store.EXPECT().ListAuditEvents(gomock.Any(), db.ListAuditEventsParams{AfterID: 2, PageSize: 2}).Return(events[2:], nil)
+
+	report, err := NewVerifier(store, 2).Run(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Intact())
+	require.Equal(t, int64(3), report.Events)
+	require.Equal(t, events[2].Hash, report.HeadHash)
+}
+
+func TestVerifierRunEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Return([]db.AuditEvent{}, nil)
+
+	report, err := NewVerifier(store, 0).Run(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Intact())
+	require.Equal(t, audit.GenesisHash, report.HeadHash)
+}
+
+func TestVerifierRunBroken(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tamper  func(events []db.AuditEvent) []db.AuditEvent
+		eventID int64
+		events  int64
+	}{
+		{
+			name: "EditedPayload",
+			tamper: func(events []db.AuditEvent) []db.AuditEvent {
+				events[1].After = json.RawMessage(`{"amount":1000}`)
+				return events
+			},
+			eventID: 2,
+			events:  1,
+		},
+		{
+			name: "EditedActor",
+			tamper: func(events []db.AuditEvent) []db.AuditEvent {
+				events[0].Actor = "mallory"
+				return events
+			},
+			eventID: 1,
+		},
+		{
+			name: "RemovedEvent",
+			tamper: func(events []db.AuditEvent) []db.AuditEvent {
+				return append(events[:1], events[2:]...)
+			},
+			eventID: 3,
+			events:  1,
+		},
+		{
+			name: "RehashedEvent",
+			tamper: func(events []db.AuditEvent) []db.AuditEvent {
+				// the edited event is hashed again, the next event still chains to the old hash
+				events[0].Actor = "mallory"
+				events[0].Hash, _ = audit.Hash(events[0].PrevHash, audit.Event{
+					Actor: events[0].Actor, Role: events[0].Role, Action: events[0].Action, Target: events[0].Target,
+					Before: events[0].Before, After: events[0].After, CreatedAt: events[0].CreatedAt,
+				})
+				return events
+			},
+			eventID: 2,
+			events:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Return(tc.tamper(chain(t, 3)), nil)
+
+			report, err := NewVerifier(store, 0).Run(context.Background())
+			require.NoError(t, err)
+			require.False(t, report.Intact())
+			require.Equal(t, tc.eventID, report.Broken.EventID)
+			require.Equal(t, tc.events, report.Events)
+		})
+	}
+}
+
+func TestVerifierRunError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection reset"))
+
+	_, err := NewVerifier(store, 0).Run(context.Background())
+	require.Error(t, err)
+}
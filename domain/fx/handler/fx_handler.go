@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhiemaz/bank-api/domain/fx/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/middlewares"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	Usecase usecase.FxUseCase
+}
+
+func NewFxHandler(usecase usecase.FxUseCase) *Handler {
+	return &Handler{
+		Usecase: usecase,
+	}
+}
+
+// GetRates godoc
+//
+//	@Summary		gets the latest exchange rates
+//	@Description	gets the latest rate of every loaded currency pair
+//	@Tags			fx
+//	@Produce		json
+//	@Success		200	{object}	response.JSON{data=[]fxRateResponse}
+//	@Failure		500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/fx/rates [get]
+func (fx *Handler) GetRates(ctx *gin.Context) {
+	rates, err := fx.Usecase.GetRates(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+		return
+	}
+
+	responses := []entities.FxRateResponse{}
+	for _, rate := range rates {
+		responses = append(responses, utils.MapFxRateToResponse(rate))
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(responses))
+}
+
+// CreateQuote godoc
+//
+//	@Summary		locks an exchange rate for a transfer between currencies
+//	@Description	locks the current rate of a currency pair for a short time, pass the quote id as quote_id when creating the transfer
+//	@Tags			fx
+//	@Accept			json
+//	@Produce		json
+//	@Param			body			body		createFxQuoteReq	true	"Currency pair to quote"
+//	@Success		201				{object}	response.JSON{data=fxQuoteResponse}
+//	@Failure		400,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/fx/quotes [post]
+func (fx *Handler) CreateQuote(ctx *gin.Context) {
+	var request entities.CreateFxQuoteRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	quote, err := fx.Usecase.CreateQuote(ctx, payload, request)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, api_error.ErrFxRateNotFound) {
+			status = http.StatusNotFound
+		}
+
+		ctx.JSON(status, entities.Err(err))
+		return
+	}
+
+	var toAmount int64
+	if request.Amount > 0 {
+		if toAmount, err = db.ConvertAmount(request.Amount, quote.Rate, quote.FromCurrency, quote.ToCurrency); err != nil {
+			ctx.JSON(http.StatusBadRequest, entities.Err(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, entities.Success(utils.MapFxQuoteToResponse(quote, request.Amount, toAmount)))
+}
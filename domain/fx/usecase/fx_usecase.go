@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultQuoteTTL = 30 * time.Second
+	// rateScale matches the scale of the rate columns
+	rateScale = 12
+)
+
+type FxUseCase interface {
+	GetRates(ctx context.Context) ([]db.FxRate, error)
+	CreateQuote(ctx context.Context, caller *token.Payload, request entities.CreateFxQuoteRequest) (*db.FxQuote, error)
+}
+
+type UseCase struct {
+	db       db.Store
+	quoteTTL time.Duration
+}
+
+func NewFxUseCase(db db.Store, quoteTTL time.Duration) *UseCase {
+	if quoteTTL <= 0 {
+		quoteTTL = defaultQuoteTTL
+	}
+
+	return &UseCase{db: db, quoteTTL: quoteTTL}
+}
+
+// GetRates : latest rate of every loaded currency pair
+func (fx *UseCase) GetRates(ctx context.Context) ([]db.FxRate, error) {
+	rates, err := fx.db.ListLatestFxRates(ctx)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get fx rates"}).
+			Errorf("failed get fx rates, error : %v", err)
+
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// CreateQuote : lock the current rate of a currency pair for the quote TTL, a transfer between currencies
+// must reference a quote which can be used once
+func (fx *UseCase) CreateQuote(ctx context.Context, caller *token.Payload, request entities.CreateFxQuoteRequest) (*db.FxQuote, error) {
+	rate, err := fx.latestRate(ctx, request.FromCurrency, request.ToCurrency)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create fx quote", "payload": request}).
+			Errorf("failed get fx rate, error : %v", err)
+
+		return nil, err
+	}
+
+	quote, err := fx.db.CreateFxQuote(ctx, db.CreateFxQuoteParams{
+		ID:           uuid.New(),
+		Username:     caller.Username,
+		FromCurrency: request.FromCurrency,
+		ToCurrency:   request.ToCurrency,
+		Rate:         rate,
+		ExpiresAt:    time.Now().Add(fx.quoteTTL),
+	})
+
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create fx quote", "payload": request}).
+			Errorf("failed create fx quote, error : %v", err)
+
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+// latestRate returns the latest rate from one currency to another, inverting the rate of the opposite pair
+// when only that one has been loaded
+func (fx *UseCase) latestRate(ctx context.Context, from, to string) (string, error) {
+	rate, err := fx.db.GetLatestFxRate(ctx, db.GetLatestFxRateParams{BaseCurrency: from, QuoteCurrency: to})
+	if err == nil {
+		return rate.Rate, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	rate, err = fx.db.GetLatestFxRate(ctx, db.GetLatestFxRateParams{BaseCurrency: to, QuoteCurrency: from})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", api_error.ErrFxRateNotFound
+		}
+		return "", err
+	}
+
+	return InvertRate(rate.Rate)
+}
+
+// InvertRate returns 1/rate rounded to the scale of the rate columns
+func InvertRate(rate string) (string, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return "", errors.New("invalid exchange rate " + rate)
+	}
+
+	return new(big.Rat).Inv(r).FloatString(rateScale), nil
+}
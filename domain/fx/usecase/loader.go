@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+type rateRecord struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Rate  string `json:"rate"`
+}
+
+// ParseRates reads rates from a CSV file with base,quote,rate columns (a header row is optional) or from a JSON
+// array of {"base", "quote", "rate"} objects. Rates are kept as decimal strings so no precision is lost.
+func ParseRates(r io.Reader, format, source string) ([]db.CreateFxRateParams, error) {
+	var records []rateRecord
+
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = 3
+		reader.TrimLeadingSpace = true
+
+		lines, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		for i, line := range lines {
+			if i == 0 && strings.EqualFold(line[0], "base") {
+				continue
+			}
+			records = append(records, rateRecord{Base: line[0], Quote: line[1], Rate: line[2]})
+		}
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported rates format %q", format)
+	}
+
+	rates := make([]db.CreateFxRateParams, 0, len(records))
+	for i, record := range records {
+		base, quote := strings.ToUpper(strings.TrimSpace(record.Base)), strings.ToUpper(strings.TrimSpace(record.Quote))
+
+		if !utils.IsSupportedCurrency(base) || !utils.IsSupportedCurrency(quote) || base == quote {
+			return nil, fmt.Errorf("record %d: invalid currency pair %s/%s", i+1, record.Base, record.Quote)
+		}
+
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(record.Rate))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("record %d: invalid rate %q", i+1, record.Rate)
+		}
+
+		rates = append(rates, db.CreateFxRateParams{
+			BaseCurrency:  base,
+			QuoteCurrency: quote,
+			Rate:          rate.FloatString(rateScale),
+			Source:        source,
+		})
+	}
+
+	return rates, nil
+}
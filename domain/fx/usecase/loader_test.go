@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRatesCSV(t *testing.T) {
+	rates, err := ParseRates(strings.NewReader("base,quote,rate\nUSD,IDR,15500.25\nidr, usd, 0.0000645\n"), FormatCSV, "bank")
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+
+	require.Equal(t, "USD", rates[0].BaseCurrency)
+	require.Equal(t, "IDR", rates[0].QuoteCurrency)
+	require.Equal(t, "15500.250000000000", rates[0].Rate)
+	require.Equal(t, "bank", rates[0].Source)
+
+	require.Equal(t, "IDR", rates[1].BaseCurrency)
+	require.Equal(t, "0.000064500000", rates[1].Rate)
+}
+
+func TestParseRatesJSON(t *testing.T) {
+	rates, err := ParseRates(strings.NewReader(`[{"base":"USD","quote":"IDR","rate":"15500"}]`), FormatJSON, "bank")
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	require.Equal(t, "15500.000000000000", rates[0].Rate)
+}
+
+func TestParseRatesInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		format string
+	}{
+		{"UnsupportedCurrency", "USD,XXX,1", FormatCSV},
+		{"SameCurrency", "USD,USD,1", FormatCSV},
+		{"NegativeRate", "USD,IDR,-1", FormatCSV},
+		{"NotANumber", `[{"base":"USD","quote":"IDR","rate":"abc"}]`, FormatJSON},
+		{"MissingColumn", "USD,IDR", FormatCSV},
+		{"UnknownFormat", "USD,IDR,1", "xml"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseRates(strings.NewReader(tc.input), tc.format, "bank")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestInvertRate(t *testing.T) {
+	inverted, err := InvertRate("15500")
+	require.NoError(t, err)
+	require.Equal(t, "0.000064516129", inverted)
+
+	_, err = InvertRate("0")
+	require.Error(t, err)
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhiemaz/bank-api/domain/hold/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/middlewares"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	Usecase usecase.HoldUseCase
+}
+
+func NewHoldHandler(usecase usecase.HoldUseCase) *Handler {
+	return &Handler{
+		Usecase: usecase,
+	}
+}
+
+// CreateHold godoc
+//
+//	@Summary		reserves money for a later transfer
+//	@Description	reserves money on an account of the currently logged-in user, it isn't available anymore until the hold is captured, released or expires
+//	@Tags			holds
+//	@Accept			json
+//	@Produce		json
+//	@Param			body					body		createHoldReq	true	"Hold to create"
+//	@Success		201						{object}	response.JSON{data=holdResponse}
+//	@Failure		400,401,403,422,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/holds [post]
+func (hold *Handler) CreateHold(ctx *gin.Context) {
+	var request entities.CreateHoldRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	holdData, err := hold.Usecase.CreateHold(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(holdErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entities.Success(utils.MapHoldToResponse(*holdData)))
+}
+
+// GetHold godoc
+//
+//	@Summary		gets a hold by id
+//	@Description	gets a hold on or to an account of the currently logged-in user
+//	@Tags			holds
+//	@Produce		json
+//	@Param			id				path		int64	true	"Hold ID"
+//	@Success		200				{object}	response.JSON{data=holdResponse}
+//	@Failure		400,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/holds/{id} [get]
+func (hold *Handler) GetHold(ctx *gin.Context) {
+	var request entities.HoldURIRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	holdData, err := hold.Usecase.GetHold(ctx, payload, request.ID)
+	if err != nil {
+		ctx.JSON(holdErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapHoldToResponse(*holdData)))
+}
+
+// GetAccountHolds godoc
+//
+//	@Summary		lists the holds of an account
+//	@Description	lists the holds on or to an account of the currently logged-in user, latest first
+//	@Tags			holds
+//	@Produce		json
+//	@Param			id				path		int64	true	"Account ID"
+//	@Param			status			query		string	false	"Status of the holds"	Enums(active, captured, released, expired)
+//	@Param			cursor			query		string	false	"Cursor of the next page"
+//	@Param			limit			query		int32	false	"Page Size, 20 by default"
+//	@Success		200				{object}	response.JSON{data=[]holdResponse}
+//	@Failure		400,401,403,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/accounts/{id}/holds [get]
+func (hold *Handler) GetAccountHolds(ctx *gin.Context) {
+	var uri entities.GetAccountRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.ListHoldsRequest
+	if err := utils.ParseQuery(ctx, &request); err != nil {
+		return
+	}
+
+	pgQuery, err := utils.ParsePagination(ctx)
+	if err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	holds, nextCursor, err := hold.Usecase.GetAccountHolds(ctx, payload, uri.ID, request, pgQuery)
+	if err != nil {
+		ctx.JSON(holdErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	responses := []entities.HoldResponse{}
+	for _, holdData := range holds {
+		responses = append(responses, utils.MapHoldToResponse(holdData))
+	}
+
+	ctx.JSON(http.StatusOK, entities.SuccessPage(responses, nextCursor))
+}
+
+// CaptureHold godoc
+//
+//	@Summary		captures a hold
+//	@Description	transfers the whole hold or a part of it to the receiving account of the currently logged-in user, the rest is released
+//	@Tags			holds
+//	@Accept			json
+//	@Produce		json
+//	@Param			id						path		int64			true	"Hold ID"
+//	@Param			body					body		captureHoldReq	false	"Amount to capture, the whole hold by default"
+//	@Success		200						{object}	response.JSON{data=captureHoldResponse}
+//	@Failure		400,401,403,404,409,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/holds/{id}/capture [post]
+func (hold *Handler) CaptureHold(ctx *gin.Context) {
+	var uri entities.HoldURIRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.CaptureHoldRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := utils.ParseBody(ctx, &request); err != nil {
+			return
+		}
+	}
+
+	request.HoldID = uri.ID
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	result, err := hold.Usecase.CaptureHold(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(holdErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapCaptureHoldToResponse(result)))
+}
+
+// ReleaseHold godoc
+//
+//	@Summary		releases a hold
+//	@Description	gives the held money back to the paying account, only the owner of the receiving account can release a hold
+//	@Tags			holds
+//	@Produce		json
+//	@Param			id					path		int64	true	"Hold ID"
+//	@Success		200					{object}	response.JSON{data=holdResponse}
+//	@Failure		400,401,403,404,409,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/holds/{id}/release [post]
+func (hold *Handler) ReleaseHold(ctx *gin.Context) {
+	var request entities.HoldURIRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	holdData, err := hold.Usecase.ReleaseHold(ctx, payload, request.ID)
+	if err != nil {
+		ctx.JSON(holdErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapHoldToResponse(*holdData)))
+}
+
+func holdErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, api_error.ErrHoldNotFound), errors.Is(err, api_error.ErrAccountNotFound):
+		return http.StatusNotFound
+	case api_error.IsHoldNotActive(err), errors.Is(err, api_error.ErrHoldExpired):
+		return http.StatusConflict
+	case errors.Is(err, api_error.ErrInsufficientFunds), errors.Is(err, api_error.ErrTransferLimitExceeded),
+		errors.Is(err, api_error.ErrAccountDeleted):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, api_error.ErrAccountFrozen), errors.Is(err, api_error.ErrAccountDormant),
+		errors.Is(err, api_error.ErrNotAccountOwner):
+		return http.StatusForbidden
+	case errors.Is(err, api_error.ErrInvalidHoldExpiry), api_error.IsCaptureExceedsHold(err),
+		api_error.IsCurrencyMismatch(err), errors.Is(err, api_error.ErrInvalidCursor),
+		errors.Is(err, api_error.ErrSameAccountTransfer):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+)
+
+// Expirer releases the holds that were neither captured nor released before they expired. Several expirers can
+// poll the same database, a hold settled by another one in the meantime is skipped.
+type Expirer struct {
+	db        db.Store
+	batchSize int32
+	now       func() time.Time
+}
+
+func NewExpirer(db db.Store, batchSize int32) *Expirer {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &Expirer{db: db, batchSize: batchSize, now: time.Now}
+}
+
+// Start expires due holds every interval until ctx is done
+func (expirer *Expirer) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := expirer.ExpireDue(ctx); err != nil {
+			logger.WithFields(logger.Fields{"component": "holds", "action": "expire holds"}).
+				Errorf("failed expire holds, error : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireDue expires a batch of holds past their expiry and returns how many were expired
+func (expirer *Expirer) ExpireDue(ctx context.Context) (int, error) {
+	ids, err := expirer.db.ListExpiredHolds(ctx, db.ListExpiredHoldsParams{
+		ExpiresAt: expirer.now(),
+		Limit:     expirer.batchSize,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		_, err = expirer.db.ReleaseHoldTx(ctx, db.ReleaseHoldTxParam{HoldID: id, Status: db.HoldStatusExpired})
+		switch {
+		case err == nil:
+			expired++
+		case api_error.IsHoldNotActive(err):
+			// captured or released since it was listed
+		default:
+			logger.WithFields(logger.Fields{"component": "holds", "action": "expire hold", "hold_id": id}).
+				Errorf("failed expire hold [%d], error : %v", id, err)
+		}
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpirerExpireDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().ListExpiredHolds(gomock.Any(), db.ListExpiredHoldsParams{ExpiresAt: now, Limit: 10}).
+		Return([]int64{1, 2, 3}, nil)
+	store.EXPECT().ReleaseHoldTx(gomock.Any(), db.ReleaseHoldTxParam{HoldID: 1, Status: db.HoldStatusExpired}).
+		Return(db.Hold{ID: 1, Status: db.HoldStatusExpired}, nil)
+	// captured after it was listed
+	store.EXPECT().ReleaseHoldTx(gomock.Any(), db.ReleaseHoldTxParam{HoldID: 2, Status: db.HoldStatusExpired}).
+		Return(db.Hold{}, api_error.ErrHoldNotActive(db.HoldStatusCaptured))
+	store.EXPECT().ReleaseHoldTx(gomock.Any(), db.ReleaseHoldTxParam{HoldID: 3, Status: db.HoldStatusExpired}).
+		Return(db.Hold{}, errors.New("connection reset"))
+
+	expirer := NewExpirer(store, 10)
+	expirer.now = func() time.Time { return now }
+
+	expired, err := expirer.ExpireDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+}
+
+func TestExpirerListError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListExpiredHolds(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection reset"))
+	store.EXPECT().ReleaseHoldTx(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := NewExpirer(store, 0).ExpireDue(context.Background())
+	require.Error(t, err)
+}
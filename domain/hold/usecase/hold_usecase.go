@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	transactionUsecase "github.com/dhiemaz/bank-api/domain/transaction/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/limit"
+	"github.com/dhiemaz/bank-api/utils/token"
+)
+
+const (
+	defaultHoldTTL = 7 * 24 * time.Hour
+	defaultMaxTTL  = 30 * 24 * time.Hour
+)
+
+// HoldUseCase : money reserved on an account of the payer for a later transfer to the payee. The payer creates the
+// hold, the owner of the receiving account captures or releases it.
+type HoldUseCase interface {
+	CreateHold(ctx context.Context, caller *token.Payload, request entities.CreateHoldRequest) (*db.Hold, error)
+	GetHold(ctx context.Context, caller *token.Payload, id int64) (*db.Hold, error)
+	GetAccountHolds(ctx context.Context, caller *token.Payload, accountID int64, request entities.ListHoldsRequest, pagination *utils.PaginationQuery) ([]db.Hold, string, error)
+	CaptureHold(ctx context.Context, caller *token.Payload, request entities.CaptureHoldRequest) (*db.CaptureHoldTxResult, error)
+	ReleaseHold(ctx context.Context, caller *token.Payload, id int64) (*db.Hold, error)
+}
+
+type UseCase struct {
+	db         db.Store
+	transfer   transactionUsecase.TransferUseCase
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	// frozenAcceptsCredits lets holds be taken for frozen accounts, like transfers
+	frozenAcceptsCredits bool
+	// limits bounds the holds like transfers, nil limits nothing
+	limits *limit.Policy
+}
+
+func NewHoldUseCase(db db.Store, transfer transactionUsecase.TransferUseCase, defaultTTL, maxTTL time.Duration, frozenAcceptsCredits bool, limits *limit.Policy) *UseCase {
+	if defaultTTL <= 0 {
+		defaultTTL = defaultHoldTTL
+	}
+
+	if maxTTL <= 0 {
+		maxTTL = defaultMaxTTL
+	}
+
+	if maxTTL < defaultTTL {
+		maxTTL = defaultTTL
+	}
+
+	return &UseCase{db: db, transfer: transfer, defaultTTL: defaultTTL, maxTTL: maxTTL, frozenAcceptsCredits: frozenAcceptsCredits, limits: limits}
+}
+
+// CreateHold : reserve money on an account of the authenticated user, the accounts are checked like for a transfer
+func (hold *UseCase) CreateHold(ctx context.Context, caller *token.Payload, request entities.CreateHoldRequest) (*db.Hold, error) {
+	if _, _, err := hold.transfer.ValidateTransfer(ctx, caller, request.FromAccountID, request.ToAccountID, request.Amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(hold.defaultTTL)
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(now) || request.ExpiresAt.After(now.Add(hold.maxTTL)) {
+			return nil, api_error.ErrInvalidHoldExpiry
+		}
+
+		expiresAt = *request.ExpiresAt
+	}
+
+	result, err := hold.db.CreateHoldTx(ctx, db.CreateHoldTxParam{
+		CreateHoldParams: db.CreateHoldParams{
+			AccountID:   request.FromAccountID,
+			ToAccountID: request.ToAccountID,
+			Amount:      request.Amount,
+			Description: request.Description,
+			CreatedBy:   caller.Username,
+			ExpiresAt:   expiresAt,
+		},
+		FrozenAcceptsCredits: hold.frozenAcceptsCredits,
+		Limits:               hold.limits,
+	})
+
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create hold", "payload": request}).
+			Errorf("failed create hold, error : %v", err)
+
+		return nil, err
+	}
+
+	return &result.Hold, nil
+}
+
+// GetHold : a hold on or to an account of the authenticated user
+func (hold *UseCase) GetHold(ctx context.Context, caller *token.Payload, id int64) (*db.Hold, error) {
+	holdData, err := hold.db.GetHold(ctx, id)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get hold", "hold_id": id}).
+			Errorf("failed get hold, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrHoldNotFound
+		}
+
+		return nil, err
+	}
+
+	ownsFrom, err := hold.ownsAccount(ctx, caller, holdData.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	ownsTo, err := hold.ownsAccount(ctx, caller, holdData.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ownsFrom && !ownsTo {
+		// holds between other users' accounts are reported as missing, so ids can't be probed
+		return nil, api_error.ErrHoldNotFound
+	}
+
+	return &holdData, nil
+}
+
+// GetAccountHolds : one page of the holds on or to an account of the authenticated user, latest first
+func (hold *UseCase) GetAccountHolds(ctx context.Context, caller *token.Payload, accountID int64, request entities.ListHoldsRequest, pagination *utils.PaginationQuery) ([]db.Hold, string, error) {
+	owns, err := hold.ownsAccount(ctx, caller, accountID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !owns {
+		return nil, "", api_error.ErrNotAccountOwner
+	}
+
+	arg := db.ListAccountHoldsParams{
+		AccountID: accountID,
+		Status:    sql.NullString{String: request.Status, Valid: request.Status != ""},
+		PageSize:  pagination.FetchSize(),
+	}
+	arg.AfterCreatedAt, arg.AfterID = pagination.AfterParams()
+
+	holds, err := hold.db.ListAccountHolds(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get account holds", "account_id": accountID}).
+			Errorf("failed get account holds, error : %v", err)
+
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if pagination.HasNextPage(len(holds)) {
+		holds = holds[:pagination.Limit]
+		last := holds[len(holds)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return holds, nextCursor, nil
+}
+
+// CaptureHold : transfer the whole hold or a part of it to the receiving account, the rest is released
+func (hold *UseCase) CaptureHold(ctx context.Context, caller *token.Payload, request entities.CaptureHoldRequest) (*db.CaptureHoldTxResult, error) {
+	if _, err := hold.getPayeeHold(ctx, caller, request.HoldID); err != nil {
+		return nil, err
+	}
+
+	result, err := hold.db.CaptureHoldTx(ctx, db.CaptureHoldTxParam{HoldID: request.HoldID, Amount: request.Amount})
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "capture hold", "payload": request}).
+			Errorf("failed capture hold, error : %v", err)
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ReleaseHold : give the held money back to the available balance of the paying account
+func (hold *UseCase) ReleaseHold(ctx context.Context, caller *token.Payload, id int64) (*db.Hold, error) {
+	if _, err := hold.getPayeeHold(ctx, caller, id); err != nil {
+		return nil, err
+	}
+
+	released, err := hold.db.ReleaseHoldTx(ctx, db.ReleaseHoldTxParam{HoldID: id, Status: db.HoldStatusReleased})
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "release hold", "hold_id": id}).
+			Errorf("failed release hold, error : %v", err)
+
+		return nil, err
+	}
+
+	return &released, nil
+}
+
+// getPayeeHold : a hold the authenticated user can settle, only the owner of the receiving account can
+func (hold *UseCase) getPayeeHold(ctx context.Context, caller *token.Payload, id int64) (*db.Hold, error) {
+	holdData, err := hold.GetHold(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+
+	owns, err := hold.ownsAccount(ctx, caller, holdData.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !owns {
+		return nil, api_error.ErrNotAccountOwner
+	}
+
+	return holdData, nil
+}
+
+func (hold *UseCase) ownsAccount(ctx context.Context, caller *token.Payload, accountID int64) (bool, error) {
+	account, err := hold.db.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return account.Owner == caller.Username, nil
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/interest"
+)
+
+// RunResult counts what a run did, accruals and postings done by an earlier run of the same date are skipped
+type RunResult struct {
+	Accrued        int `json:"accrued"`
+	AccrualSkipped int `json:"accrual_skipped"`
+	Posted         int `json:"posted"`
+	PostingSkipped int `json:"posting_skipped"`
+	Failed         int `json:"failed"`
+}
+
+// Accruer accrues interest every day on the end of day balances of the accounts of interest earning products and
+// posts it at the end of every month. Runs are idempotent per date, running a date again only does what the
+// previous run of that date didn't.
+type Accruer struct {
+	db              db.Store
+	products        *interest.Catalog
+	expenseAccounts map[string]int64
+	batchSize       int32
+}
+
+func NewAccruer(db db.Store, products *interest.Catalog, expenseAccounts map[string]int64, batchSize int32) *Accruer {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &Accruer{db: db, products: products, expenseAccounts: expenseAccounts, batchSize: batchSize}
+}
+
+// Run accrues the interest of date and, when date is the last day of its month, posts the interest of the month
+func (accruer *Accruer) Run(ctx context.Context, date time.Time) (RunResult, error) {
+	var result RunResult
+	date = Day(date)
+
+	if err := accruer.Accrue(ctx, date, &result); err != nil {
+		return result, err
+	}
+
+	if date.AddDate(0, 0, 1).Day() == 1 {
+		if err := accruer.Post(ctx, time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC), &result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// Accrue records the interest earned on date by the end of day balance of every interest earning account
+func (accruer *Accruer) Accrue(ctx context.Context, date time.Time, result *RunResult) error {
+	products := accruer.products.Accruing()
+	if len(products) == 0 {
+		return nil
+	}
+
+	arg := db.ListEndOfDayBalancesParams{
+		DayEnd:   date.AddDate(0, 0, 1),
+		Products: products,
+		PageSize: accruer.batchSize,
+	}
+
+	for {
+		balances, err := accruer.db.ListEndOfDayBalances(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		for _, balance := range balances {
+			product, _ := accruer.products.Lookup(balance.Product)
+
+			_, err = accruer.db.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+				AccountID:             balance.ID,
+				AccrualDate:           date,
+				Product:               product.Name,
+				Balance:               balance.Balance,
+				AnnualRateBasisPoints: product.AnnualRateBasisPoints,
+				DayCount:              product.DayCount,
+				AmountMicros:          product.DailyAccrual(balance.Balance, date),
+			})
+
+			switch {
+			case err == nil:
+				result.Accrued++
+			case errors.Is(err, sql.ErrNoRows):
+				// accrued by an earlier run of the date
+				result.AccrualSkipped++
+			default:
+				result.Failed++
+				logger.WithFields(logger.Fields{"component": "interest", "action": "accrue interest", "account_id": balance.ID, "date": date}).
+					Errorf("failed accrue interest of account [%d], error : %v", balance.ID, err)
+			}
+		}
+
+		if len(balances) < int(arg.PageSize) {
+			return nil
+		}
+		arg.AfterID = balances[len(balances)-1].ID
+	}
+}
+
+// Post pays the interest accrued up to the end of the month of period and not paid yet, accounts whose interest is
+// still below a minor unit keep it for the next month
+func (accruer *Accruer) Post(ctx context.Context, period time.Time, result *RunResult) error {
+	arg := db.ListUnpostedInterestParams{
+		PeriodEnd: period.AddDate(0, 1, 0),
+		PageSize:  accruer.batchSize,
+	}
+
+	for {
+		unposted, err := accruer.db.ListUnpostedInterest(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		for _, account := range unposted {
+			if interest.ToMinorUnits(account.AmountMicros) <= 0 {
+				continue
+			}
+
+			expenseAccountID, ok := accruer.expenseAccounts[account.Currency]
+			if !ok {
+				result.Failed++
+				logger.WithFields(logger.Fields{"component": "interest", "action": "post interest", "account_id": account.AccountID, "period": period}).
+					Errorf("failed post interest of account [%d], no interest expense account for %s", account.AccountID, account.Currency)
+				continue
+			}
+
+			posted, err := accruer.db.PostInterestTx(ctx, db.PostInterestTxParam{
+				AccountID:        account.AccountID,
+				ExpenseAccountID: expenseAccountID,
+				Period:           period,
+			})
+
+			switch {
+			case err == nil && !posted.Replayed:
+				result.Posted++
+			case err == nil, errors.Is(err, api_error.ErrNoInterestToPost):
+				result.PostingSkipped++
+			default:
+				result.Failed++
+				logger.WithFields(logger.Fields{"component": "interest", "action": "post interest", "account_id": account.AccountID, "period": period}).
+					Errorf("failed post interest of account [%d], error : %v", account.AccountID, err)
+			}
+		}
+
+		if len(unposted) < int(arg.PageSize) {
+			return nil
+		}
+		arg.AfterID = unposted[len(unposted)-1].AccountID
+	}
+}
+
+// Day truncates a time to its day in UTC, the day accruals are recorded for
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/interest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCatalog(t *testing.T) *interest.Catalog {
+	products, err := interest.NewCatalog([]interest.Product{
+		{Name: "savings", AnnualRateBasisPoints: 365, DayCount: interest.DayCountActual365},
+	})
+	require.NoError(t, err)
+	return products
+}
+
+func TestAccruerRunAccruesDay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().ListEndOfDayBalances(gomock.Any(), db.ListEndOfDayBalancesParams{
+		DayEnd:   date.AddDate(0, 0, 1),
+		Products: []string{"savings"},
+		PageSize: 2,
+	}).Return([]db.ListEndOfDayBalancesRow{
+		{ID: 1, Currency: "USD", Product: "savings", Balance: 1000000},
+		{ID: 2, Currency: "USD", Product: "savings", Balance: 500},
+	}, nil)
+	store.EXPECT().ListEndOfDayBalances(gomock.Any(), db.ListEndOfDayBalancesParams{
+		DayEnd:   date.AddDate(0, 0, 1),
+		Products: []string{"savings"},
+		AfterID:  2,
+		PageSize: 2,
+	}).Return([]db.ListEndOfDayBalancesRow{{ID: 3, Currency: "USD", Product: "savings", Balance: -10}}, nil)
+
+	store.EXPECT().CreateInterestAccrual(gomock.Any(), db.CreateInterestAccrualParams{
+		AccountID:             1,
+		AccrualDate:           date,
+		Product:               "savings",
+		Balance:               1000000,
+		AnnualRateBasisPoints: 365,
+		DayCount:              interest.DayCountActual365,
+		AmountMicros:          100 * interest.MicrosPerUnit,
+	}).Return(db.InterestAccrual{ID: 1}, nil)
+	// accrued by an earlier run
+	store.EXPECT().CreateInterestAccrual(gomock.Any(), gomock.Any()).Return(db.InterestAccrual{}, sql.ErrNoRows)
+	store.EXPECT().CreateInterestAccrual(gomock.Any(), gomock.Any()).Return(db.InterestAccrual{}, errors.New("connection reset"))
+
+	// not the end of the month, nothing is posted
+	store.EXPECT().ListUnpostedInterest(gomock.Any(), gomock.Any()).Times(0)
+
+	accruer := NewAccruer(store, newTestCatalog(t), map[string]int64{"USD": 9}, 2)
+	result, err := accruer.Run(context.Background(), date.Add(15*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, RunResult{Accrued: 1, AccrualSkipped: 1, Failed: 1}, result)
+}
+
+func TestAccruerRunPostsMonth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	date := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	period := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().ListEndOfDayBalances(gomock.Any(), gomock.Any()).Return([]db.ListEndOfDayBalancesRow{}, nil)
+	store.EXPECT().ListUnpostedInterest(gomock.Any(), db.ListUnpostedInterestParams{
+		PeriodEnd: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		PageSize:  100,
+	}).Return([]db.ListUnpostedInterestRow{
+		{AccountID: 1, Currency: "USD", AmountMicros: 2900 * interest.MicrosPerUnit},
+		// less than a minor unit, kept for the next month
+		{AccountID: 2, Currency: "USD", AmountMicros: 999999},
+		{AccountID: 3, Currency: "USD", AmountMicros: interest.MicrosPerUnit},
+		{AccountID: 4, Currency: "IDR", AmountMicros: interest.MicrosPerUnit},
+		{AccountID: 5, Currency: "USD", AmountMicros: interest.MicrosPerUnit},
+	}, nil)
+
+	store.EXPECT().PostInterestTx(gomock.Any(), db.PostInterestTxParam{AccountID: 1, ExpenseAccountID: 9, Period: period}).
+		Return(db.PostInterestTxResult{Posting: db.InterestPosting{ID: 1, Amount: 2900}}, nil)
+	// posted by an earlier run
+	store.EXPECT().PostInterestTx(gomock.Any(), db.PostInterestTxParam{AccountID: 3, ExpenseAccountID: 9, Period: period}).
+		Return(db.PostInterestTxResult{Replayed: true}, nil)
+	store.EXPECT().PostInterestTx(gomock.Any(), db.PostInterestTxParam{AccountID: 5, ExpenseAccountID: 9, Period: period}).
+		Return(db.PostInterestTxResult{}, api_error.ErrAccountDeleted)
+
+	accruer := NewAccruer(store, newTestCatalog(t), map[string]int64{"USD": 9}, 0)
+	result, err := accruer.Run(context.Background(), date)
+	require.NoError(t, err)
+	// account 4 has no expense account for its currency
+	require.Equal(t, RunResult{Posted: 1, PostingSkipped: 1, Failed: 2}, result)
+}
+
+func TestAccruerNoAccruingProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListEndOfDayBalances(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := NewAccruer(store, nil, nil, 0).Run(context.Background(), time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, RunResult{}, result)
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+)
+
+// Relay publishes the events of the outbox to a sink in the order they were written. Several relays can poll the
+// same database, they take turns on the pending events so the events of an aggregate are never published out of
+// order. An event is published again when it couldn't be marked published.
+type Relay struct {
+	db        db.Store
+	sink      outbox.Sink
+	batchSize int32
+}
+
+func NewRelay(db db.Store, sink outbox.Sink, batchSize int32) *Relay {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &Relay{db: db, sink: sink, batchSize: batchSize}
+}
+
+// Start relays the pending events every interval until ctx is done
+func (relay *Relay) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := relay.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			logger.WithFields(logger.Fields{"component": "outbox", "action": "relay events"}).
+				Errorf("failed relay outbox events, error : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes the pending events batch by batch until the outbox is drained and returns how many were
+// published. It stops at the first event the sink fails to publish, the events after it wait for the next run.
+func (relay *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		result, err := relay.db.RelayOutboxTx(ctx, db.RelayOutboxTxParam{
+			Limit: relay.batchSize,
+			Publish: func(event db.Outbox) error {
+				return relay.sink.Publish(ctx, outbox.Event{
+					ID:            event.ID,
+					Type:          event.EventType,
+					AggregateType: event.AggregateType,
+					AggregateID:   event.AggregateID,
+					Payload:       event.Payload,
+					CreatedAt:     event.CreatedAt,
+				})
+			},
+		})
+		published += len(result.Published)
+
+		if err != nil {
+			return published, err
+		}
+
+		if result.Pending < int(relay.batchSize) {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records the events published and fails the ones in fail
+type recordingSink struct {
+	published []outbox.Event
+	fail      map[int64]bool
+}
+
+func (sink *recordingSink) Publish(_ context.Context, event outbox.Event) error {
+	if sink.fail[event.ID] {
+		return errors.New("sink down")
+	}
+	sink.published = append(sink.published, event)
+	return nil
+}
+
+func (sink *recordingSink) Close() error { return nil }
+
+// relayTx runs the publish callback of RelayOutboxTx over events like the store does
+func relayTx(events ...db.Outbox) func(context.Context, db.RelayOutboxTxParam) (db.RelayOutboxTxResult, error) {
+	return func(_ context.Context, arg db.RelayOutboxTxParam) (db.RelayOutboxTxResult, error) {
+		result := db.RelayOutboxTxResult{Pending: len(events)}
+		for _, event := range events {
+			if err := arg.Publish(event); err != nil {
+				return result, err
+			}
+			result.Published = append(result.Published, event)
+		}
+		return result, nil
+	}
+}
+
+func outboxEvent(id int64, eventType string) db.Outbox {
+	return db.Outbox{ID: id, AggregateType: outbox.AggregateTransfer, AggregateID: "1", EventType: eventType,
+		Payload: json.RawMessage(`{}`)}
+}
+
+func TestRelayPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		// a full batch, there may be more
+		store.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(relayTx(outboxEvent(1, outbox.EventTransferCreated), outboxEvent(2, outbox.EventTransferReversed))),
+		store.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(relayTx(outboxEvent(3, outbox.EventTransferCreated))),
+	)
+
+	sink := &recordingSink{}
+	published, err := NewRelay(store, sink, 2).RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, published)
+
+	require.Len(t, sink.published, 3)
+	require.Equal(t, int64(1), sink.published[0].ID)
+	require.Equal(t, outbox.EventTransferReversed, sink.published[1].Type)
+	require.Equal(t, outbox.AggregateTransfer, sink.published[2].AggregateType)
+}
+
+func TestRelayPendingSinkError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(relayTx(outboxEvent(1, outbox.EventTransferCreated), outboxEvent(2, outbox.EventTransferCreated),
+			outboxEvent(3, outbox.EventTransferCreated)))
+
+	// the events after the failed one aren't published either
+	sink := &recordingSink{fail: map[int64]bool{2: true}}
+	published, err := NewRelay(store, sink, 10).RelayPending(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, published)
+	require.Len(t, sink.published, 1)
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+)
+
+// Report lists the discrepancies found in the ledger, a clean ledger has no drift, no unbalanced transfer and every
+// currency sums to zero
+type Report struct {
+	CheckedAt           time.Time            `json:"checked_at"`
+	BalanceDrifts       []BalanceDrift       `json:"balance_drifts"`
+	UnbalancedTransfers []UnbalancedTransfer `json:"unbalanced_transfers"`
+	Currencies          []CurrencyTotal      `json:"currencies"`
+	Discrepancies       int                  `json:"discrepancies"`
+}
+
+// BalanceDrift is an account whose balance isn't the sum of its entries
+type BalanceDrift struct {
+	AccountID    int64  `json:"account_id"`
+	Owner        string `json:"owner"`
+	Currency     string `json:"currency"`
+	Balance      int64  `json:"balance"`
+	EntriesTotal int64  `json:"entries_total"`
+	Drift        int64  `json:"drift"`
+}
+
+// UnbalancedTransfer is a transfer without exactly one debit entry of its source account and one credit entry of its
+// destination account matching its amounts, or whose entries don't sum to zero in every currency
+type UnbalancedTransfer struct {
+	TransferID    int64    `json:"transfer_id"`
+	FromAccountID int64    `json:"from_account_id"`
+	ToAccountID   int64    `json:"to_account_id"`
+	Amount        int64    `json:"amount"`
+	Fee           int64    `json:"fee"`
+	ToAmount      *int64   `json:"to_amount,omitempty"`
+	DebitEntries  int64    `json:"debit_entries"`
+	Debited       int64    `json:"debited"`
+	CreditEntries int64    `json:"credit_entries"`
+	Credited      int64    `json:"credited"`
+	Problems      []string `json:"problems"`
+}
+
+// CurrencyTotal is the sum of the balances of a currency, what customers hold has to be owed by the house accounts
+type CurrencyTotal struct {
+	Currency      string `json:"currency"`
+	HouseTotal    int64  `json:"house_total"`
+	CustomerTotal int64  `json:"customer_total"`
+	Total         int64  `json:"total"`
+	Balanced      bool   `json:"balanced"`
+}
+
+// Clean is true when the ledger has no discrepancy
+func (report Report) Clean() bool {
+	return report.Discrepancies == 0
+}
+
+// Reconciler verifies the ledger, it recomputes every balance from the entries, checks the entries of every transfer
+// and that every currency sums to zero against the house accounts. It only reads, a report is the only output.
+type Reconciler struct {
+	db              db.Store
+	houseAccountIDs []int64
+	batchSize       int32
+}
+
+func NewReconciler(db db.Store, houseAccountIDs []int64, batchSize int32) *Reconciler {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &Reconciler{db: db, houseAccountIDs: houseAccountIDs, batchSize: batchSize}
+}
+
+// Run checks the whole ledger and reports the discrepancies found
+func (reconciler *Reconciler) Run(ctx context.Context) (Report, error) {
+	report := Report{
+		CheckedAt:           time.Now().UTC(),
+		BalanceDrifts:       []BalanceDrift{},
+		UnbalancedTransfers: []UnbalancedTransfer{},
+	}
+
+	if err := reconciler.checkBalances(ctx, &report); err != nil {
+		return report, fmt.Errorf("check balances: %w", err)
+	}
+
+	if err := reconciler.checkTransfers(ctx, &report); err != nil {
+		return report, fmt.Errorf("check transfers: %w", err)
+	}
+
+	if err := reconciler.checkCurrencies(ctx, &report); err != nil {
+		return report, fmt.Errorf("check currencies: %w", err)
+	}
+
+	return report, nil
+}
+
+func (reconciler *Reconciler) checkBalances(ctx context.Context, report *Report) error {
+	arg := db.ListBalanceDriftParams{PageSize: reconciler.batchSize}
+
+	for {
+		drifts, err := reconciler.db.ListBalanceDrift(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		for _, drift := range drifts {
+			report.BalanceDrifts = append(report.BalanceDrifts, BalanceDrift{
+				AccountID:    drift.ID,
+				Owner:        drift.Owner,
+				Currency:     drift.Currency,
+				Balance:      drift.Balance,
+				EntriesTotal: drift.EntriesTotal,
+				Drift:        drift.Balance - drift.EntriesTotal,
+			})
+			report.Discrepancies++
+		}
+
+		if len(drifts) < int(arg.PageSize) {
+			return nil
+		}
+		arg.AfterID = drifts[len(drifts)-1].ID
+	}
+}
+
+func (reconciler *Reconciler) checkTransfers(ctx context.Context, report *Report) error {
+	arg := db.ListUnbalancedTransfersParams{PageSize: reconciler.batchSize}
+
+	for {
+		transfers, err := reconciler.db.ListUnbalancedTransfers(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		for _, transfer := range transfers {
+			report.UnbalancedTransfers = append(report.UnbalancedTransfers, unbalancedTransfer(transfer))
+			report.Discrepancies++
+		}
+
+		if len(transfers) < int(arg.PageSize) {
+			return nil
+		}
+		arg.AfterID = transfers[len(transfers)-1].ID
+	}
+}
+
+// unbalancedTransfer explains why the entries of a transfer don't match it
+func unbalancedTransfer(transfer db.ListUnbalancedTransfersRow) UnbalancedTransfer {
+	unbalanced := UnbalancedTransfer{
+		TransferID:    transfer.ID,
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		Fee:           transfer.Fee,
+		DebitEntries:  transfer.DebitEntries,
+		Debited:       transfer.Debited,
+		CreditEntries: transfer.CreditEntries,
+		Credited:      transfer.Credited,
+		Problems:      []string{},
+	}
+
+	credit := transfer.Amount
+	if transfer.ToAmount.Valid {
+		credit = transfer.ToAmount.Int64
+		unbalanced.ToAmount = &transfer.ToAmount.Int64
+	}
+
+	if transfer.DebitEntries != 1 {
+		unbalanced.Problems = append(unbalanced.Problems, fmt.Sprintf("%d debit entries, expected 1", transfer.DebitEntries))
+	}
+
+	if transfer.CreditEntries != 1 {
+		unbalanced.Problems = append(unbalanced.Problems, fmt.Sprintf("%d credit entries, expected 1", transfer.CreditEntries))
+	}
+
+	if debit := transfer.Amount + transfer.Fee; transfer.Debited != -debit {
+		unbalanced.Problems = append(unbalanced.Problems, fmt.Sprintf("debited %d, expected %d", -transfer.Debited, debit))
+	}
+
+	if transfer.Credited != credit {
+		unbalanced.Problems = append(unbalanced.Problems, fmt.Sprintf("credited %d, expected %d", transfer.Credited, credit))
+	}
+
+	if !transfer.ZeroSum {
+		unbalanced.Problems = append(unbalanced.Problems, "entries don't sum to zero in every currency")
+	}
+
+	return unbalanced
+}
+
+func (reconciler *Reconciler) checkCurrencies(ctx context.Context, report *Report) error {
+	totals, err := reconciler.db.ListCurrencyTotals(ctx, reconciler.houseAccountIDs)
+	if err != nil {
+		return err
+	}
+
+	report.Currencies = make([]CurrencyTotal, 0, len(totals))
+	for _, total := range totals {
+		currency := CurrencyTotal{
+			Currency:      total.Currency,
+			HouseTotal:    total.HouseTotal,
+			CustomerTotal: total.CustomerTotal,
+			Total:         total.HouseTotal + total.CustomerTotal,
+		}
+		currency.Balanced = currency.Total == 0
+
+		if !currency.Balanced {
+			report.Discrepancies++
+		}
+		report.Currencies = append(report.Currencies, currency)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcilerRunClean(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListBalanceDrift(gomock.Any(), db.ListBalanceDriftParams{PageSize: 500}).Return([]db.ListBalanceDriftRow{}, nil)
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any(), db.ListUnbalancedTransfersParams{PageSize: 500}).
+		Return([]db.ListUnbalancedTransfersRow{}, nil)
+	store.EXPECT().ListCurrencyTotals(gomock.Any(), []int64{1, 2}).Return([]db.ListCurrencyTotalsRow{
+		{Currency: "IDR", HouseTotal: -5000, CustomerTotal: 5000},
+		{Currency: "USD"},
+	}, nil)
+
+	report, err := NewReconciler(store, []int64{1, 2}, 0).Run(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Clean())
+	require.Empty(t, report.BalanceDrifts)
+	require.Empty(t, report.UnbalancedTransfers)
+	require.Equal(t, []CurrencyTotal{
+		{Currency: "IDR", HouseTotal: -5000, CustomerTotal: 5000, Balanced: true},
+		{Currency: "USD", Balanced: true},
+	}, report.Currencies)
+}
+
+func TestReconcilerRunDiscrepancies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListBalanceDrift(gomock.Any(), db.ListBalanceDriftParams{PageSize: 1}).
+		Return([]db.ListBalanceDriftRow{{ID: 7, Owner: "alice", Currency: "USD", Balance: 1000, EntriesTotal: 0}}, nil)
+	store.EXPECT().ListBalanceDrift(gomock.Any(), db.ListBalanceDriftParams{AfterID: 7, PageSize: 1}).
+		Return([]db.ListBalanceDriftRow{}, nil)
+
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any(), db.ListUnbalancedTransfersParams{PageSize: 1}).
+		Return([]db.ListUnbalancedTransfersRow{{
+			ID: 3, FromAccountID: 7, ToAccountID: 8, Amount: 100, Fee: 5,
+			DebitEntries: 1, Debited: -100, CreditEntries: 0, ZeroSum: false,
+		}}, nil)
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any(), db.ListUnbalancedTransfersParams{AfterID: 3, PageSize: 1}).
+		Return([]db.ListUnbalancedTransfersRow{{
+			ID: 4, FromAccountID: 8, ToAccountID: 9, Amount: 100, ToAmount: sql.NullInt64{Int64: 1500000, Valid: true},
+			DebitEntries: 1, Debited: -100, CreditEntries: 2, Credited: 3000000, ZeroSum: true,
+		}}, nil)
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any(), db.ListUnbalancedTransfersParams{AfterID: 4, PageSize: 1}).
+		Return([]db.ListUnbalancedTransfersRow{}, nil)
+
+	store.EXPECT().ListCurrencyTotals(gomock.Any(), []int64{1}).
+		Return([]db.ListCurrencyTotalsRow{{Currency: "USD", HouseTotal: -100, CustomerTotal: 1100}}, nil)
+
+	report, err := NewReconciler(store, []int64{1}, 1).Run(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.Clean())
+	require.Equal(t, 4, report.Discrepancies)
+
+	require.Equal(t, []BalanceDrift{{AccountID: 7, Owner: "alice", Currency: "USD", Balance: 1000, Drift: 1000}}, report.BalanceDrifts)
+
+	require.Len(t, report.UnbalancedTransfers, 2)
+	require.Equal(t, []string{
+		"0 credit entries, expected 1",
+		"debited 100, expected 105",
+		"credited 0, expected 100",
+		"entries don't sum to zero in every currency",
+	}, report.UnbalancedTransfers[0].Problems)
+	require.Nil(t, report.UnbalancedTransfers[0].ToAmount)
+	require.Equal(t, []string{
+		"2 credit entries, expected 1",
+		"credited 3000000, expected 1500000",
+	}, report.UnbalancedTransfers[1].Problems)
+	require.Equal(t, int64(1500000), *report.UnbalancedTransfers[1].ToAmount)
+
+	require.Equal(t, []CurrencyTotal{{Currency: "USD", HouseTotal: -100, CustomerTotal: 1100, Total: 1000}}, report.Currencies)
+}
+
+func TestReconcilerRunError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListBalanceDrift(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection reset"))
+	store.EXPECT().ListUnbalancedTransfers(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := NewReconciler(store, nil, 0).Run(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhiemaz/bank-api/domain/schedule/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/middlewares"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	Usecase usecase.ScheduleUseCase
+}
+
+func NewScheduleHandler(usecase usecase.ScheduleUseCase) *Handler {
+	return &Handler{
+		Usecase: usecase,
+	}
+}
+
+// CreateSchedule godoc
+//
+//	@Summary		schedules a future-dated or recurring transfer
+//	@Description	schedules a future-dated or recurring transfer, the scheduler command executes it when due
+//	@Tags			scheduled transfers
+//	@Accept			json
+//	@Produce		json
+//	@Param			body		body		createScheduledTransferReq	true	"Schedule to create"
+//	@Success		201			{object}	response.JSON{data=scheduledTransferResponse}
+//	@Failure		400,401,403,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/scheduled [post]
+func (schedule *Handler) CreateSchedule(ctx *gin.Context) {
+	var request entities.CreateScheduledTransferRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	scheduleData, err := schedule.Usecase.CreateSchedule(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(scheduleErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entities.Success(utils.MapScheduledTransferToResponse(scheduleData)))
+}
+
+// GetSchedules godoc
+//
+//	@Summary		gets all scheduled transfers of the currently logged-in user
+//	@Description	gets all scheduled transfers of the currently logged-in user
+//	@Tags			scheduled transfers
+//	@Produce		json
+//	@Success		200	{object}	response.JSON{data=[]scheduledTransferResponse}
+//	@Failure		500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/scheduled [get]
+func (schedule *Handler) GetSchedules(ctx *gin.Context) {
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	schedules, err := schedule.Usecase.GetSchedules(ctx, payload)
+	if err != nil {
+		ctx.JSON(scheduleErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	responses := []entities.ScheduledTransferResponse{}
+	for i := range schedules {
+		responses = append(responses, utils.MapScheduledTransferToResponse(&schedules[i]))
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(responses))
+}
+
+// GetSchedule godoc
+//
+//	@Summary		gets a scheduled transfer by id
+//	@Description	gets a scheduled transfer by id
+//	@Tags			scheduled transfers
+//	@Produce		json
+//	@Param			id				path		int64	true	"Scheduled transfer ID"
+//	@Success		200				{object}	response.JSON{data=scheduledTransferResponse}
+//	@Failure		400,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/scheduled/{id} [get]
+func (schedule *Handler) GetSchedule(ctx *gin.Context) {
+	var request entities.GetScheduledTransferRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	scheduleData, err := schedule.Usecase.GetSchedule(ctx, payload, request.ID)
+	if err != nil {
+		ctx.JSON(scheduleErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapScheduledTransferToResponse(scheduleData)))
+}
+
+// UpdateSchedule godoc
+//
+//	@Summary		updates a scheduled transfer
+//	@Description	changes the amount or end date of a scheduled transfer, or pauses and resumes it
+//	@Tags			scheduled transfers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		int64						true	"Scheduled transfer ID"
+//	@Param			body				body		updateScheduledTransferReq	true	"Fields to update"
+//	@Success		200					{object}	response.JSON{data=scheduledTransferResponse}
+//	@Failure		400,404,409,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/scheduled/{id} [patch]
+func (schedule *Handler) UpdateSchedule(ctx *gin.Context) {
+	var uri entities.GetScheduledTransferRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.UpdateScheduledTransferRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	request.ID = uri.ID
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	scheduleData, err := schedule.Usecase.UpdateSchedule(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(scheduleErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapScheduledTransferToResponse(scheduleData)))
+}
+
+// CancelSchedule godoc
+//
+//	@Summary		cancels a scheduled transfer
+//	@Description	cancels a scheduled transfer, its run history is kept
+//	@Tags			scheduled transfers
+//	@Produce		json
+//	@Param			id				path		int64	true	"Scheduled transfer ID"
+//	@Success		200				{object}	response.JSON{data=scheduledTransferResponse}
+//	@Failure		400,404,409,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/scheduled/{id} [delete]
+func (schedule *Handler) CancelSchedule(ctx *gin.Context) {
+	var request entities.GetScheduledTransferRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	scheduleData, err := schedule.Usecase.CancelSchedule(ctx, payload, request.ID)
+	if err != nil {
+		ctx.JSON(scheduleErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapScheduledTransferToResponse(scheduleData)))
+}
+
+// GetScheduleRuns godoc
+//
+//	@Summary		gets the run history of a scheduled transfer
+//	@Description	gets the run history of a scheduled transfer, latest first
+//	@Tags			scheduled transfers
+//	@Produce		json
+//	@Param			id				path		int64	true	"Scheduled transfer ID"
+//	@Param			cursor			query		string	false	"Cursor of the next page"
+//	@Param			limit			query		int32	false	"Page Size, 20 by default"
+//	@Success		200				{object}	response.JSON{data=[]scheduledTransferRunResponse}
+//	@Failure		400,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/scheduled/{id}/runs [get]
+func (schedule *Handler) GetScheduleRuns(ctx *gin.Context) {
+	var request entities.GetScheduledTransferRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	pgQuery, err := utils.ParsePagination(ctx)
+	if err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	runs, nextCursor, err := schedule.Usecase.GetScheduleRuns(ctx, payload, request.ID, pgQuery)
+	if err != nil {
+		ctx.JSON(scheduleErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	responses := []entities.ScheduledTransferRunResponse{}
+	for _, run := range runs {
+		responses = append(responses, utils.MapScheduledTransferRunToResponse(run))
+	}
+
+	ctx.JSON(http.StatusOK, entities.SuccessPage(responses, nextCursor))
+}
+
+func scheduleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, api_error.ErrScheduleNotFound), errors.Is(err, api_error.ErrAccountNotFound):
+		return http.StatusNotFound
+	case api_error.IsScheduleNotEditable(err):
+		return http.StatusConflict
+	case errors.Is(err, api_error.ErrAccountDeleted):
+		return http.StatusUnprocessableEntity
+	case api_error.IsInvalidSchedule(err), errors.Is(err, api_error.ErrSameAccountTransfer):
+		return http.StatusBadRequest
+	case errors.Is(err, api_error.ErrNotAccountOwner):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+)
+
+const (
+	FrequencyOnce    = "once"
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+const (
+	StatusActive    = "active"
+	StatusPaused    = "paused"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusFailed    = "failed"
+)
+
+const (
+	// PolicySkip gives up the occurrence right away when the source account can't cover it
+	PolicySkip = "skip"
+	// PolicyRetry retries the occurrence with backoff up to max_retries times
+	PolicyRetry = "retry"
+)
+
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	RunSkipped   = "skipped"
+)
+
+// OccurrenceAt returns when the n-th occurrence (zero based) of a schedule starting at start is due.
+// Monthly schedules keep the day of month of start and use the last day of shorter months,
+// a schedule starting on Jan 31 runs on Feb 28 (or 29) and then on Mar 31.
+func OccurrenceAt(start time.Time, frequency string, n int32) time.Time {
+	switch frequency {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, int(n))
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*int(n))
+	case FrequencyMonthly:
+		year, month, day := start.Date()
+		hour, min, sec := start.Clock()
+
+		lastDay := time.Date(year, month+time.Month(n)+1, 0, 0, 0, 0, 0, start.Location()).Day()
+		if day > lastDay {
+			day = lastDay
+		}
+
+		return time.Date(year, month+time.Month(n), day, hour, min, sec, start.Nanosecond(), start.Location())
+	default:
+		return start
+	}
+}
+
+// NextOccurrence returns when the occurrence following the current one is due,
+// false when the schedule has no occurrence left.
+func NextOccurrence(schedule db.ScheduledTransfer) (time.Time, bool) {
+	if schedule.Frequency == FrequencyOnce {
+		return time.Time{}, false
+	}
+
+	next := OccurrenceAt(schedule.StartAt, schedule.Frequency, schedule.Occurrences+1)
+	if schedule.EndAt.Valid && next.After(schedule.EndAt.Time) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// RetryBackoff doubles the delay for every attempt, starting at base and capped at max
+func RetryBackoff(base, max time.Duration, attempt int32) time.Duration {
+	delay := base
+	for i := int32(1); i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
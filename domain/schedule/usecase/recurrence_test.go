@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOccurrenceAt(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		frequency string
+		n         int32
+		expected  time.Time
+	}{
+		{"Once", FrequencyOnce, 3, start},
+		{"Daily", FrequencyDaily, 1, time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC)},
+		{"Weekly", FrequencyWeekly, 2, time.Date(2024, time.February, 14, 9, 30, 0, 0, time.UTC)},
+		{"MonthlyLeapFebruary", FrequencyMonthly, 1, time.Date(2024, time.February, 29, 9, 30, 0, 0, time.UTC)},
+		{"MonthlyKeepsDayOfMonth", FrequencyMonthly, 2, time.Date(2024, time.March, 31, 9, 30, 0, 0, time.UTC)},
+		{"MonthlyShortMonth", FrequencyMonthly, 3, time.Date(2024, time.April, 30, 9, 30, 0, 0, time.UTC)},
+		{"MonthlyNextYear", FrequencyMonthly, 13, time.Date(2025, time.February, 28, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, OccurrenceAt(start, tc.frequency, tc.n))
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ok := NextOccurrence(db.ScheduledTransfer{Frequency: FrequencyOnce, StartAt: start})
+	require.False(t, ok)
+
+	next, ok := NextOccurrence(db.ScheduledTransfer{Frequency: FrequencyWeekly, StartAt: start, Occurrences: 1})
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC), next)
+
+	// the end date is inclusive
+	endAt := sql.NullTime{Time: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	next, ok = NextOccurrence(db.ScheduledTransfer{Frequency: FrequencyMonthly, StartAt: start, EndAt: endAt, Occurrences: 1})
+	require.True(t, ok)
+	require.Equal(t, endAt.Time, next)
+
+	_, ok = NextOccurrence(db.ScheduledTransfer{Frequency: FrequencyMonthly, StartAt: start, EndAt: endAt, Occurrences: 2})
+	require.False(t, ok)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	require.Equal(t, time.Minute, RetryBackoff(time.Minute, time.Hour, 1))
+	require.Equal(t, 4*time.Minute, RetryBackoff(time.Minute, time.Hour, 3))
+	require.Equal(t, time.Hour, RetryBackoff(time.Minute, time.Hour, 20))
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
+)
+
+var errScheduleInvalid = errors.New("schedule can no longer be executed")
+
+type RunnerConfig struct {
+	BatchSize    int32
+	LockTimeout  time.Duration
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// FrozenAcceptsCredits lets schedules keep paying into frozen accounts
+	FrozenAcceptsCredits bool
+	// Fees charges scheduled transfers, nil charges nothing
+	Fees *fee.Schedule
+	// Limits bounds scheduled transfers like any other transfer of the owner, nil limits nothing. An occurrence over
+	// a limit is retried until the limit resets.
+	Limits *limit.Policy
+}
+
+// Runner executes due scheduled transfers. Several runners can poll the same database,
+// a schedule is claimed by one of them until it is settled or its lock times out.
+type Runner struct {
+	db     db.Store
+	config RunnerConfig
+	now    func() time.Time
+}
+
+func NewRunner(db db.Store, config RunnerConfig) *Runner {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = 5 * time.Minute
+	}
+
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = time.Minute
+	}
+
+	if config.MaxBackoff < config.RetryBackoff {
+		config.MaxBackoff = config.RetryBackoff
+	}
+
+	return &Runner{db: db, config: config, now: time.Now}
+}
+
+// Start polls for due schedules every interval until ctx is done
+func (runner *Runner) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := runner.RunDue(ctx); err != nil {
+			logger.WithFields(logger.Fields{"component": "scheduler", "action": "run due schedules"}).
+				Errorf("failed run due schedules, error : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue claims a batch of due schedules, executes them and returns how many were processed
+func (runner *Runner) RunDue(ctx context.Context) (int, error) {
+	now := runner.now()
+
+	schedules, err := runner.db.ClaimDueScheduledTransfers(ctx, db.ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(runner.config.LockTimeout),
+		Now:         now,
+		BatchSize:   runner.config.BatchSize,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, schedule := range schedules {
+		if err = runner.execute(ctx, schedule); err != nil {
+			// the schedule stays locked and is picked up again once the lock times out
+			logger.WithFields(logger.Fields{"component": "scheduler", "action": "execute schedule", "schedule_id": schedule.ID}).
+				Errorf("failed record run of schedule [%d], error : %v", schedule.ID, err)
+		}
+	}
+
+	return len(schedules), nil
+}
+
+func (runner *Runner) execute(ctx context.Context, schedule db.ScheduledTransfer) error {
+	attempt := schedule.RetryCount + 1
+	run := db.CreateScheduledTransferRunParams{
+		ScheduledTransferID: schedule.ID,
+		Occurrence:          schedule.Occurrences,
+		Attempt:             attempt,
+	}
+
+	advance := db.AdvanceScheduledTransferParams{
+		ID:          schedule.ID,
+		NextRunAt:   schedule.NextRunAt,
+		Occurrences: schedule.Occurrences,
+		RetryCount:  schedule.RetryCount,
+		Status:      schedule.Status,
+	}
+
+	transfer, err := runner.transfer(ctx, schedule)
+	switch {
+	case err == nil:
+		run.Status = RunSucceeded
+		run.TransferID = sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}
+		runner.nextOccurrence(schedule, &advance, StatusCompleted)
+	case isPermanentFailure(err):
+		run.Status = RunFailed
+		advance.Status = StatusFailed
+	case errors.Is(err, api_error.ErrInsufficientFunds) && schedule.OnInsufficientFunds == PolicySkip:
+		run.Status = RunSkipped
+		runner.nextOccurrence(schedule, &advance, StatusCompleted)
+	case attempt <= schedule.MaxRetries:
+		run.Status = RunFailed
+		advance.RetryCount = attempt
+		advance.NextRunAt = runner.now().Add(RetryBackoff(runner.config.RetryBackoff, runner.config.MaxBackoff, attempt))
+	default:
+		// out of retries, the occurrence is given up
+		run.Status = RunFailed
+		runner.nextOccurrence(schedule, &advance, StatusFailed)
+	}
+
+	if err != nil {
+		run.Error = sql.NullString{String: err.Error(), Valid: true}
+
+		logger.WithFields(logger.Fields{"component": "scheduler", "action": "execute schedule", "schedule_id": schedule.ID}).
+			Errorf("occurrence %d attempt %d of schedule [%d] %s, error : %v", schedule.Occurrences, attempt, schedule.ID, run.Status, err)
+	}
+
+	_, err = runner.db.ScheduledTransferRunTx(ctx, db.ScheduledTransferRunTxParam{Run: run, Advance: advance})
+	return err
+}
+
+// transfer executes the current occurrence of the schedule. The idempotency key is derived from the occurrence,
+// so an occurrence whose transfer went through but whose run could not be recorded is not paid twice.
+func (runner *Runner) transfer(ctx context.Context, schedule db.ScheduledTransfer) (db.TransferTxResult, error) {
+	from, err := runner.db.GetAccount(ctx, schedule.FromAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	to, err := runner.db.GetAccount(ctx, schedule.ToAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	switch {
+	case from.Owner != schedule.Owner:
+		return db.TransferTxResult{}, fmt.Errorf("%w: %v", errScheduleInvalid, api_error.ErrNotAccountOwner)
+	case from.Status == db.AccountStatusClosed:
+		return db.TransferTxResult{}, fmt.Errorf("%w: from account %d: %v", errScheduleInvalid, from.ID, api_error.ErrAccountDeleted)
+	case to.Status == db.AccountStatusClosed:
+		return db.TransferTxResult{}, fmt.Errorf("%w: to account %d: %v", errScheduleInvalid, to.ID, api_error.ErrAccountDeleted)
+	}
+
+	// a freeze may be lifted and a dormant account reactivated, the occurrence is retried like any other failure.
+	// TransferTx checks the statuses again under the account locks.
+	if err = from.CheckDebit(); err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	if err = to.CheckCredit(runner.config.FrozenAcceptsCredits); err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	return runner.db.TransferTx(ctx, db.TransferTxParam{
+		FromAccountID:        schedule.FromAccountID,
+		ToAccountID:          schedule.ToAccountID,
+		Amount:               schedule.Amount,
+		Username:             schedule.Owner,
+		IdempotencyKey:       fmt.Sprintf("scheduled-%d-%d", schedule.ID, schedule.Occurrences),
+		Fee:                  runner.config.Fees.Quote(from.Currency, fee.TypeScheduled, schedule.Amount),
+		FrozenAcceptsCredits: runner.config.FrozenAcceptsCredits,
+		Limits:               runner.config.Limits,
+	})
+}
+
+// nextOccurrence moves the schedule to its next occurrence, or to finalStatus when it has none left
+func (runner *Runner) nextOccurrence(schedule db.ScheduledTransfer, advance *db.AdvanceScheduledTransferParams, finalStatus string) {
+	advance.Occurrences = schedule.Occurrences + 1
+	advance.RetryCount = 0
+
+	next, ok := NextOccurrence(schedule)
+	if !ok {
+		advance.Status = finalStatus
+		return
+	}
+
+	advance.NextRunAt = next
+}
+
+// isPermanentFailure reports errors retrying won't fix, the schedule is stopped on them
+func isPermanentFailure(err error) bool {
+	return errors.Is(err, errScheduleInvalid) || errors.Is(err, sql.ErrNoRows)
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerRunDue(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	from := db.Account{ID: 1, Owner: "owner1", Balance: 100, Currency: "USD"}
+	to := db.Account{ID: 2, Owner: "owner2", Balance: 0, Currency: "USD"}
+
+	schedule := db.ScheduledTransfer{
+		ID:                  7,
+		Owner:               from.Owner,
+		FromAccountID:       from.ID,
+		ToAccountID:         to.ID,
+		Amount:              50,
+		Frequency:           FrequencyMonthly,
+		StartAt:             now,
+		NextRunAt:           now,
+		Status:              StatusActive,
+		OnInsufficientFunds: PolicySkip,
+		MaxRetries:          2,
+	}
+
+	fees, err := fee.NewSchedule([]fee.Rule{{Currency: "USD", TransferType: fee.TypeScheduled, Kind: fee.KindFlat, Flat: 5}},
+		map[string]int64{"USD": 3})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		schedule    func() db.ScheduledTransfer
+		transferErr error
+		check       func(t *testing.T, arg db.ScheduledTransferRunTxParam)
+	}{
+		{
+			name:     "Succeeded",
+			schedule: func() db.ScheduledTransfer { return schedule },
+			check: func(t *testing.T, arg db.ScheduledTransferRunTxParam) {
+				require.Equal(t, RunSucceeded, arg.Run.Status)
+				require.True(t, arg.Run.TransferID.Valid)
+				require.Equal(t, int32(1), arg.Advance.Occurrences)
+				require.Equal(t, time.Date(2024, time.April, 1, 8, 0, 0, 0, time.UTC), arg.Advance.NextRunAt)
+				require.Equal(t, StatusActive, arg.Advance.Status)
+			},
+		},
+		{
+			name:        "SkippedOnInsufficientFunds",
+			schedule:    func() db.ScheduledTransfer { return schedule },
+			transferErr: &api_error.InsufficientFundsError{AccountID: from.ID},
+			check: func(t *testing.T, arg db.ScheduledTransferRunTxParam) {
+				require.Equal(t, RunSkipped, arg.Run.Status)
+				require.Equal(t, int32(1), arg.Advance.Occurrences)
+				require.Zero(t, arg.Advance.RetryCount)
+			},
+		},
+		{
+			name: "RetriedOnInsufficientFunds",
+			schedule: func() db.ScheduledTransfer {
+				retried := schedule
+				retried.OnInsufficientFunds = PolicyRetry
+				return retried
+			},
+			transferErr: &api_error.InsufficientFundsError{AccountID: from.ID},
+			check: func(t *testing.T, arg db.ScheduledTransferRunTxParam) {
+				require.Equal(t, RunFailed, arg.Run.Status)
+				require.Equal(t, int32(0), arg.Advance.Occurrences)
+				require.Equal(t, int32(1), arg.Advance.RetryCount)
+				require.Equal(t, now.Add(time.Minute), arg.Advance.NextRunAt)
+			},
+		},
+		{
+			name: "OutOfRetries",
+			schedule: func() db.ScheduledTransfer {
+				retried := schedule
+				retried.Frequency = FrequencyOnce
+				retried.RetryCount = 2
+				return retried
+			},
+			transferErr: errors.New("connection reset"),
+			check: func(t *testing.T, arg db.ScheduledTransferRunTxParam) {
+				require.Equal(t, RunFailed, arg.Run.Status)
+				require.Equal(t, int32(3), arg.Run.Attempt)
+				require.Equal(t, StatusFailed, arg.Advance.Status)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			due := tc.schedule()
+
+			store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).
+				Return([]db.ScheduledTransfer{due}, nil)
+			store.EXPECT().GetAccount(gomock.Any(), from.ID).Return(from, nil)
+			store.EXPECT().GetAccount(gomock.Any(), to.ID).Return(to, nil)
+			store.EXPECT().TransferTx(gomock.Any(), db.TransferTxParam{
+				FromAccountID:  from.ID,
+				ToAccountID:    to.ID,
+				Amount:         due.Amount,
+				Username:       due.Owner,
+				IdempotencyKey: "scheduled-7-0",
+				Fee: fee.Breakdown{
+					Currency:         "USD",
+					TransferType:     fee.TypeScheduled,
+					Kind:             fee.KindFlat,
+					Flat:             5,
+					Amount:           5,
+					RevenueAccountID: 3,
+				},
+			}).Return(db.TransferTxResult{Transfer: db.Transfer{ID: 99}}, tc.transferErr)
+			store.EXPECT().ScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, arg db.ScheduledTransferRunTxParam) (db.ScheduledTransferRunTxResult, error) {
+					tc.check(t, arg)
+					return db.ScheduledTransferRunTxResult{}, nil
+				})
+
+			runner := NewRunner(store, RunnerConfig{RetryBackoff: time.Minute, MaxBackoff: time.Hour, Fees: fees})
+			runner.now = func() time.Time { return now }
+
+			processed, err := runner.RunDue(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, processed)
+		})
+	}
+}
+
+func TestRunnerStopsOnDeletedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	schedule := db.ScheduledTransfer{ID: 1, Owner: "owner1", FromAccountID: 1, ToAccountID: 2, Amount: 10, Status: StatusActive}
+
+	store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Return([]db.ScheduledTransfer{schedule}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), int64(1)).Return(db.Account{ID: 1, Owner: "owner1"}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), int64(2)).Return(db.Account{ID: 2, Status: db.AccountStatusClosed}, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().ScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ScheduledTransferRunTxParam) (db.ScheduledTransferRunTxResult, error) {
+			require.Equal(t, RunFailed, arg.Run.Status)
+			require.Equal(t, StatusFailed, arg.Advance.Status)
+			return db.ScheduledTransferRunTxResult{}, nil
+		})
+
+	_, err := NewRunner(store, RunnerConfig{}).RunDue(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRunnerRetriesOnFrozenAccount(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		from, to             db.Account
+		frozenAcceptsCredits bool
+		transfers            int
+	}{
+		{
+			name:      "FrozenSource",
+			from:      db.Account{ID: 1, Owner: "owner1", Status: db.AccountStatusFrozen},
+			to:        db.Account{ID: 2, Status: db.AccountStatusActive},
+			transfers: 0,
+		},
+		{
+			name:      "FrozenTarget",
+			from:      db.Account{ID: 1, Owner: "owner1", Status: db.AccountStatusActive},
+			to:        db.Account{ID: 2, Status: db.AccountStatusFrozen},
+			transfers: 0,
+		},
+		{
+			name:                 "FrozenTargetAcceptsCredits",
+			from:                 db.Account{ID: 1, Owner: "owner1", Status: db.AccountStatusActive},
+			to:                   db.Account{ID: 2, Status: db.AccountStatusFrozen},
+			frozenAcceptsCredits: true,
+			transfers:            1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			schedule := db.ScheduledTransfer{ID: 1, Owner: "owner1", FromAccountID: 1, ToAccountID: 2, Amount: 10,
+				Frequency: FrequencyDaily, Status: StatusActive, MaxRetries: 3}
+
+			store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Any()).Return([]db.ScheduledTransfer{schedule}, nil)
+			store.EXPECT().GetAccount(gomock.Any(), int64(1)).Return(tc.from, nil)
+			store.EXPECT().GetAccount(gomock.Any(), int64(2)).Return(tc.to, nil)
+			store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(tc.transfers).Return(db.TransferTxResult{}, nil)
+			store.EXPECT().ScheduledTransferRunTx(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, arg db.ScheduledTransferRunTxParam) (db.ScheduledTransferRunTxResult, error) {
+					if tc.transfers == 0 {
+						require.Equal(t, RunFailed, arg.Run.Status)
+						require.Equal(t, int32(1), arg.Advance.RetryCount)
+						require.Equal(t, StatusActive, arg.Advance.Status)
+					} else {
+						require.Equal(t, RunSucceeded, arg.Run.Status)
+					}
+					return db.ScheduledTransferRunTxResult{}, nil
+				})
+
+			runner := NewRunner(store, RunnerConfig{FrozenAcceptsCredits: tc.frozenAcceptsCredits})
+			_, err := runner.RunDue(context.Background())
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	accountUsecase "github.com/dhiemaz/bank-api/domain/account/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+)
+
+const defaultMaxRetries = 3
+
+type ScheduleUseCase interface {
+	CreateSchedule(ctx context.Context, caller *token.Payload, request entities.CreateScheduledTransferRequest) (*db.ScheduledTransfer, error)
+	GetSchedules(ctx context.Context, caller *token.Payload) ([]db.ScheduledTransfer, error)
+	GetSchedule(ctx context.Context, caller *token.Payload, id int64) (*db.ScheduledTransfer, error)
+	UpdateSchedule(ctx context.Context, caller *token.Payload, request entities.UpdateScheduledTransferRequest) (*db.ScheduledTransfer, error)
+	CancelSchedule(ctx context.Context, caller *token.Payload, id int64) (*db.ScheduledTransfer, error)
+	GetScheduleRuns(ctx context.Context, caller *token.Payload, id int64, pagination *utils.PaginationQuery) ([]db.ScheduledTransferRun, string, error)
+}
+
+type UseCase struct {
+	account accountUsecase.AccountUseCase
+	db      db.Store
+}
+
+func NewScheduleUseCase(db db.Store, account accountUsecase.AccountUseCase) *UseCase {
+	return &UseCase{db: db, account: account}
+}
+
+// CreateSchedule : schedule a future-dated or recurring transfer from an account of the authenticated user
+func (schedule *UseCase) CreateSchedule(ctx context.Context, caller *token.Payload, request entities.CreateScheduledTransferRequest) (*db.ScheduledTransfer, error) {
+	if err := schedule.validateAccounts(ctx, caller.Username, request.FromAccountID, request.ToAccountID); err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create schedule", "payload": request}).
+			Errorf("failed validate schedule accounts, error : %v", err)
+
+		return nil, err
+	}
+
+	if request.StartAt.Before(time.Now()) {
+		return nil, api_error.ErrInvalidSchedule("start_at must be in the future")
+	}
+
+	arg := db.CreateScheduledTransferParams{
+		Owner:               caller.Username,
+		FromAccountID:       request.FromAccountID,
+		ToAccountID:         request.ToAccountID,
+		Amount:              request.Amount,
+		Frequency:           request.Frequency,
+		StartAt:             request.StartAt,
+		NextRunAt:           request.StartAt,
+		OnInsufficientFunds: PolicySkip,
+		MaxRetries:          defaultMaxRetries,
+	}
+
+	if request.EndAt != nil {
+		if !request.EndAt.After(request.StartAt) {
+			return nil, api_error.ErrInvalidSchedule("end_at must be after start_at")
+		}
+
+		arg.EndAt = sql.NullTime{Time: *request.EndAt, Valid: true}
+	}
+
+	if request.OnInsufficientFunds != "" {
+		arg.OnInsufficientFunds = request.OnInsufficientFunds
+	}
+
+	if request.MaxRetries != nil {
+		arg.MaxRetries = *request.MaxRetries
+	}
+
+	scheduleData, err := schedule.db.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create schedule", "payload": request}).
+			Errorf("failed create schedule, error : %v", err)
+
+		return nil, err
+	}
+
+	return &scheduleData, nil
+}
+
+// GetSchedules : list scheduled transfers of the authenticated user
+func (schedule *UseCase) GetSchedules(ctx context.Context, caller *token.Payload) ([]db.ScheduledTransfer, error) {
+	schedules, err := schedule.db.ListScheduledTransfers(ctx, caller.Username)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get schedules", "username": caller.Username}).
+			Errorf("failed get schedules, error : %v", err)
+
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// GetSchedule : get a scheduled transfer owned by the authenticated user
+func (schedule *UseCase) GetSchedule(ctx context.Context, caller *token.Payload, id int64) (*db.ScheduledTransfer, error) {
+	scheduleData, err := schedule.db.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get schedule", "schedule_id": id}).
+			Errorf("failed get schedule, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrScheduleNotFound
+		}
+
+		return nil, err
+	}
+
+	if scheduleData.Owner != caller.Username {
+		// other users' schedules are reported as missing, so ids can't be probed
+		return nil, api_error.ErrScheduleNotFound
+	}
+
+	return &scheduleData, nil
+}
+
+// UpdateSchedule : change the amount or end date of a schedule, or pause and resume it
+func (schedule *UseCase) UpdateSchedule(ctx context.Context, caller *token.Payload, request entities.UpdateScheduledTransferRequest) (*db.ScheduledTransfer, error) {
+	scheduleData, err := schedule.GetSchedule(ctx, caller, request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEditable(scheduleData) {
+		return nil, api_error.ErrScheduleNotEditable(scheduleData.Status)
+	}
+
+	arg := db.UpdateScheduledTransferParams{ID: request.ID}
+	if request.Amount != nil {
+		arg.Amount = sql.NullInt64{Int64: *request.Amount, Valid: true}
+	}
+
+	if request.EndAt != nil {
+		if !request.EndAt.After(scheduleData.StartAt) {
+			return nil, api_error.ErrInvalidSchedule("end_at must be after start_at")
+		}
+
+		arg.EndAt = sql.NullTime{Time: *request.EndAt, Valid: true}
+	}
+
+	if request.Status != nil {
+		arg.Status = sql.NullString{String: *request.Status, Valid: true}
+	}
+
+	updated, err := schedule.db.UpdateScheduledTransfer(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "update schedule", "payload": request}).
+			Errorf("failed update schedule, error : %v", err)
+
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// CancelSchedule : stop a schedule for good, its run history is kept
+func (schedule *UseCase) CancelSchedule(ctx context.Context, caller *token.Payload, id int64) (*db.ScheduledTransfer, error) {
+	scheduleData, err := schedule.GetSchedule(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isEditable(scheduleData) {
+		return nil, api_error.ErrScheduleNotEditable(scheduleData.Status)
+	}
+
+	cancelled, err := schedule.db.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:     id,
+		Status: sql.NullString{String: StatusCancelled, Valid: true},
+	})
+
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "cancel schedule", "schedule_id": id}).
+			Errorf("failed cancel schedule, error : %v", err)
+
+		return nil, err
+	}
+
+	return &cancelled, nil
+}
+
+// GetScheduleRuns : list the executions of a schedule, latest first
+func (schedule *UseCase) GetScheduleRuns(ctx context.Context, caller *token.Payload, id int64, pagination *utils.PaginationQuery) ([]db.ScheduledTransferRun, string, error) {
+	if _, err := schedule.GetSchedule(ctx, caller, id); err != nil {
+		return nil, "", err
+	}
+
+	arg := db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: id,
+		PageSize:            pagination.FetchSize(),
+	}
+	arg.AfterCreatedAt, arg.AfterID = pagination.AfterParams()
+
+	runs, err := schedule.db.ListScheduledTransferRuns(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get schedule runs", "schedule_id": id}).
+			Errorf("failed get schedule runs, error : %v", err)
+
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if pagination.HasNextPage(len(runs)) {
+		runs = runs[:pagination.Limit]
+		last := runs[len(runs)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return runs, nextCursor, nil
+}
+
+func (schedule *UseCase) validateAccounts(ctx context.Context, username string, fromAccount, toAccount int64) error {
+	if fromAccount == toAccount {
+		return api_error.ErrSameAccountTransfer
+	}
+
+	from, err := schedule.account.IsValidAccount(ctx, fromAccount)
+	if err != nil {
+		return err
+	}
+
+	to, err := schedule.account.IsValidAccount(ctx, toAccount)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case from.Owner != username:
+		return api_error.ErrNotAccountOwner
+	case from.Currency != to.Currency:
+		return api_error.ErrCurrencyMismatch(from.Currency, to.Currency)
+	case from.Status == db.AccountStatusClosed, to.Status == db.AccountStatusClosed:
+		return api_error.ErrAccountDeleted
+	}
+
+	return nil
+}
+
+func isEditable(schedule *db.ScheduledTransfer) bool {
+	return schedule.Status == StatusActive || schedule.Status == StatusPaused
+}
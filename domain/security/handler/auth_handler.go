@@ -4,9 +4,12 @@ import (
 	"errors"
 	"github.com/dhiemaz/bank-api/domain/security/usecase"
 	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/middlewares"
 	"github.com/dhiemaz/bank-api/utils"
 	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"net/http"
 )
 
@@ -23,12 +26,12 @@ func NewAuthHandler(usecase usecase.AuthUseCase) *Handler {
 // RenewAccessToken godoc
 //
 //	@Summary		renews an access token
-//	@Description	renews an access token
+//	@Description	exchanges a refresh token for a new access token and a new refresh token, a refresh token can only be used once
 //	@Tags			users
 //	@Produce		json
 //	@Param			body	body		renewAccessTokenReq	true	"Refresh token"
 //	@Success		200		{object}	response.JSON{data=renewAccessTokenRes}
-//	@Failure		400,500	{object}	response.JSON{}
+//	@Failure		400,401,404,500	{object}	response.JSON{}
 //	@Router			/users/renew [post]
 func (auth *Handler) RenewAccessToken(ctx *gin.Context) {
 	var request entities.RenewAccessTokenRequest
@@ -36,14 +39,16 @@ func (auth *Handler) RenewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, accessPayload, err := auth.Usecase.RenewToken(ctx, request)
+	response, err := auth.Usecase.RenewToken(ctx, request, entities.ClientInfo{UserAgent: ctx.Request.UserAgent(), ClientIP: ctx.ClientIP()})
 	if err != nil {
-		if err.Error() == "invalid token" {
+		if errors.Is(err, api_error.ErrSessionNotFound) {
 			ctx.JSON(http.StatusNotFound, entities.Err(err))
 			return
 		}
 
-		if errors.Is(err, api_error.ErrBlockedRefreshToken) && errors.Is(err, api_error.ErrExpiredRefreshToken) && errors.Is(err, api_error.ErrMismatchedRefreshTokens) {
+		if errors.Is(err, api_error.ErrBlockedRefreshToken) || errors.Is(err, api_error.ErrExpiredRefreshToken) ||
+			errors.Is(err, api_error.ErrMismatchedRefreshTokens) || errors.Is(err, api_error.ErrRefreshTokenReused) ||
+			errors.Is(err, token.ErrTokenInvalid) || errors.Is(err, token.ErrTokenExpired) {
 			ctx.JSON(http.StatusUnauthorized, entities.Err(err))
 			return
 		}
@@ -52,6 +57,107 @@ func (auth *Handler) RenewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	response := entities.RenewAccessTokenResponse{AccessToken: accessToken, AccessTokenExpiresAt: accessPayload.ExpireAt}
 	ctx.JSON(http.StatusAccepted, entities.JSON{Data: response})
 }
+
+// Logout godoc
+//
+//	@Summary		logs out the current session
+//	@Description	revokes the session of the access token, its refresh token can no longer be renewed
+//	@Tags			users
+//	@Produce		json
+//	@Success		200			{object}	response.JSON{}
+//	@Failure		401,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/users/logout [post]
+func (auth *Handler) Logout(ctx *gin.Context) {
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	if err := auth.Usecase.Logout(ctx, payload); err != nil {
+		ctx.JSON(sessionErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(nil))
+}
+
+// GetSessions godoc
+//
+//	@Summary		lists the active sessions
+//	@Description	lists the sessions of the user that are neither revoked nor expired, newest first
+//	@Tags			users
+//	@Produce		json
+//	@Success		200		{object}	response.JSON{data=[]sessionResponse}
+//	@Failure		401,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/users/sessions [get]
+func (auth *Handler) GetSessions(ctx *gin.Context) {
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	sessions, err := auth.Usecase.GetSessions(ctx, payload)
+	if err != nil {
+		ctx.JSON(sessionErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	response := make([]entities.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, utils.MapSessionToResponse(session, payload.SessionID))
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(response))
+}
+
+// RevokeSession godoc
+//
+//	@Summary		revokes a session
+//	@Description	revokes one of the sessions of the user
+//	@Tags			users
+//	@Produce		json
+//	@Param			id				path		string	true	"Session ID"
+//	@Success		200				{object}	response.JSON{}
+//	@Failure		400,401,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/users/sessions/{id} [delete]
+func (auth *Handler) RevokeSession(ctx *gin.Context) {
+	var request entities.SessionURIRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	if err := auth.Usecase.RevokeSession(ctx, payload, uuid.MustParse(request.ID)); err != nil {
+		ctx.JSON(sessionErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(nil))
+}
+
+// RevokeOtherSessions godoc
+//
+//	@Summary		revokes every other session
+//	@Description	revokes every session of the user except the one of the access token
+//	@Tags			users
+//	@Produce		json
+//	@Success		200		{object}	response.JSON{data=revokeSessionsResponse}
+//	@Failure		401,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/users/sessions [delete]
+func (auth *Handler) RevokeOtherSessions(ctx *gin.Context) {
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	revoked, err := auth.Usecase.RevokeOtherSessions(ctx, payload)
+	if err != nil {
+		ctx.JSON(sessionErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(entities.RevokeSessionsResponse{Revoked: revoked}))
+}
+
+func sessionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, api_error.ErrSessionNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,36 +1,47 @@
 package usecase
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/dhiemaz/bank-api/entities"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
 	"github.com/dhiemaz/bank-api/infrastructure/logger"
 	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/rbac"
 	"github.com/dhiemaz/bank-api/utils/token"
-	"github.com/gin-gonic/gin"
-	"time"
+	"github.com/google/uuid"
 )
 
 // AuthUseCase :
 type AuthUseCase interface {
-	RenewToken(ctx *gin.Context, request entities.RenewAccessTokenRequest) (string, *token.Payload, error)
+	RenewToken(ctx context.Context, request entities.RenewAccessTokenRequest, client entities.ClientInfo) (*entities.RenewAccessTokenResponse, error)
+	Logout(ctx context.Context, caller *token.Payload) error
+	GetSessions(ctx context.Context, caller *token.Payload) ([]db.Session, error)
+	RevokeSession(ctx context.Context, caller *token.Payload, id uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, caller *token.Payload) (int64, error)
 }
 
 type UseCase struct {
-	db  db.Store
-	jwt token.JWTMaker
+	db    db.Store
+	token token.Maker
 }
 
-func NewAuthUseCase(db db.Store) *UseCase {
-	return &UseCase{db: db}
+func NewAuthUseCase(db db.Store, maker token.Maker) *UseCase {
+	return &UseCase{db: db, token: maker}
 }
 
-func (auth *UseCase) RenewToken(ctx *gin.Context, request entities.RenewAccessTokenRequest) (string, *token.Payload, error) {
+// RenewToken : exchange a refresh token for a new access token and a new refresh token, the presented refresh token
+// is consumed and presenting it again revokes the whole session. The new session is kept with the client renewing it.
+func (auth *UseCase) RenewToken(ctx context.Context, request entities.RenewAccessTokenRequest, client entities.ClientInfo) (*entities.RenewAccessTokenResponse, error) {
 
-	refreshPayload, err := auth.jwt.VerifyToken(request.RefreshToken)
+	refreshPayload, err := auth.token.VerifyToken(request.RefreshToken)
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+
+	if err = refreshPayload.CheckType(token.TypeRefresh); err != nil {
+		return nil, err
 	}
 
 	session, err := auth.db.GetSession(ctx, refreshPayload.ID)
@@ -40,53 +51,128 @@ func (auth *UseCase) RenewToken(ctx *gin.Context, request entities.RenewAccessTo
 			Errorf("failed renew token, error : %v", err)
 
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", nil, errors.New("invalid token")
+			return nil, api_error.ErrSessionNotFound
 		}
 
-		return "", nil, err
+		return nil, err
 	}
 
-	if session.IsBlocked {
+	if err = session.CheckRefresh(refreshPayload.Username, request.RefreshToken); err != nil {
 
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "renew token", "payload": request, "session": session}).
-			Errorf("failed renew token, error : session is blocked")
+			Errorf("failed renew token, error : %v", err)
 
-		return "", nil, api_error.ErrBlockedRefreshToken
+		return nil, err
 	}
 
-	if time.Now().After(session.ExpiresAt) {
+	// Generate New Refresh Token replacing the presented one
+	refreshToken, newRefreshPayload, err := auth.token.CreateRefreshToken(session.Username)
+	if err != nil {
 
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "renew token", "payload": request, "session": session}).
-			Errorf("failed renew token, error : session is expired refresh token")
+			Errorf("failed renew token, error : %v", err)
 
-		return "", nil, api_error.ErrExpiredRefreshToken
+		return nil, err
 	}
 
-	if session.Username != refreshPayload.Username {
+	newSession, err := auth.db.RotateSessionTx(ctx, db.RotateSessionTxParam{
+		ConsumedID: session.ID,
+		Username:   session.Username,
+		Session: db.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    client.UserAgent,
+			ClientIp:     client.ClientIP,
+			ExpiresAt:    newRefreshPayload.ExpireAt,
+		},
+	})
+	if err != nil {
 
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "renew token", "payload": request, "session": session}).
-			Errorf("failed renew token, error : username is not match")
+			Errorf("failed renew token, error : %v", err)
 
-		return "", nil, api_error.ErrMismatchedRefreshTokens
+		return nil, err
 	}
 
-	if session.RefreshToken != request.RefreshToken {
+	// The role is read again so that a role change applies from the next renewal
+	user, err := auth.db.GetUser(ctx, newSession.Username)
+	if err != nil {
 
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "renew token", "payload": request, "session": session}).
-			Errorf("failed renew token, error : refresh token is not match")
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "renew token", "payload": request, "session": newSession}).
+			Errorf("failed renew token, error : %v", err)
 
-		return "", nil, api_error.ErrMismatchedRefreshTokens
+		return nil, err
 	}
 
-	// Generate New Access Token for User
-	accessToken, accessPayload, err := auth.jwt.CreateToken(session.Username)
+	// Generate New Access Token bound to the new session
+	accessToken, accessPayload, err := auth.token.CreateToken(user.Username, rbac.Role(user.Role), newSession.ID)
 	if err != nil {
 
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "renew token", "payload": request, "session": session}).
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "renew token", "payload": request, "session": newSession}).
 			Errorf("failed renew token, error : %v", err)
 
-		return "", nil, err
+		return nil, err
+	}
+
+	return &entities.RenewAccessTokenResponse{
+		SessionID:             newSession.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpireAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: newRefreshPayload.ExpireAt,
+	}, nil
+}
+
+// Logout : revoke the session of the access token, the refresh token and every access token of the session stop
+// working
+func (auth *UseCase) Logout(ctx context.Context, caller *token.Payload) error {
+	return auth.RevokeSession(ctx, caller, caller.SessionID)
+}
+
+// GetSessions : list the sessions of the caller that are neither revoked nor expired
+func (auth *UseCase) GetSessions(ctx context.Context, caller *token.Payload) ([]db.Session, error) {
+	sessions, err := auth.db.ListActiveSessions(ctx, caller.Username)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get sessions", "username": caller.Username}).
+			Errorf("failed get sessions, error : %v", err)
+
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession : revoke a session of the caller, sessions of other users are reported as not found
+func (auth *UseCase) RevokeSession(ctx context.Context, caller *token.Payload, id uuid.UUID) error {
+	revoked, err := auth.db.BlockSession(ctx, db.BlockSessionParams{ID: id, Username: caller.Username})
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "revoke session", "username": caller.Username, "session_id": id}).
+			Errorf("failed revoke session, error : %v", err)
+
+		return err
+	}
+
+	if revoked == 0 {
+		return api_error.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions : revoke every session of the caller but the current one, returns how many
+// sessions were revoked
+func (auth *UseCase) RevokeOtherSessions(ctx context.Context, caller *token.Payload) (int64, error) {
+	revoked, err := auth.db.BlockOtherSessions(ctx, db.BlockOtherSessionsParams{
+		Username: caller.Username,
+		KeepID:   caller.SessionID,
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "revoke other sessions", "username": caller.Username}).
+			Errorf("failed revoke other sessions, error : %v", err)
+
+		return 0, err
 	}
 
-	return accessToken, accessPayload, nil
+	return revoked, nil
 }
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dhiemaz/bank-api/domain/statement/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/middlewares"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	Usecase usecase.StatementUseCase
+}
+
+func NewStatementHandler(usecase usecase.StatementUseCase) *Handler {
+	return &Handler{
+		Usecase: usecase,
+	}
+}
+
+// GetStatement godoc
+//
+//	@Summary		gets the statement of an account for a period
+//	@Description	gets the opening balance, the entries with their running balance and counterparty, and the closing
+//	@Description	balance of an account for the period [from, to). Pass next_cursor as cursor to get the next page.
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			id				path		int64	true	"Account ID"
+//	@Param			from			query		string	true	"Start of the period (RFC 3339)"
+//	@Param			to				query		string	true	"End of the period, exclusive (RFC 3339)"
+//	@Param			cursor			query		string	false	"Cursor of the next page"
+//	@Param			limit			query		int		false	"Entries per page, 20 by default"
+//	@Success		200				{object}	response.JSON{data=statementResponse}
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/accounts/{id}/statement [get]
+func (statement *Handler) GetStatement(ctx *gin.Context) {
+	var uri entities.GetAccountRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.GetStatementRequest
+	if err := utils.ParseQuery(ctx, &request); err != nil {
+		return
+	}
+
+	pgQuery, err := utils.ParsePagination(ctx)
+	if err != nil {
+		return
+	}
+
+	request.AccountID = uri.ID
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	result, nextCursor, err := statement.Usecase.GetStatement(ctx, payload, request, pgQuery)
+	if err != nil {
+		ctx.JSON(statementErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapStatementToResponse(result, request.From, request.To, nextCursor)))
+}
+
+// ExportStatement godoc
+//
+//	@Summary		exports the statement of an account for a period
+//	@Description	exports every entry of an account for the period [from, to) as CSV, OFX 2.2 or ISO 20022 camt.053
+//	@Tags			accounts
+//	@Produce		text/csv,application/x-ofx,application/xml
+//	@Param			id				path		int64	true	"Account ID"
+//	@Param			from			query		string	true	"Start of the period (RFC 3339)"
+//	@Param			to				query		string	true	"End of the period, exclusive (RFC 3339)"
+//	@Success		200				{file}		file
+//	@Failure		400,401,403,404,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/accounts/{id}/statement.csv [get]
+//	@Router			/accounts/{id}/statement.ofx [get]
+//	@Router			/accounts/{id}/statement.xml [get]
+func (statement *Handler) ExportStatement(format string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var uri entities.GetAccountRequest
+		if err := utils.ParseURI(ctx, &uri); err != nil {
+			return
+		}
+
+		var request entities.GetStatementRequest
+		if err := utils.ParseQuery(ctx, &request); err != nil {
+			return
+		}
+
+		request.AccountID = uri.ID
+		payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+		result, err := statement.Usecase.ExportStatement(ctx, payload, request)
+		if err != nil {
+			ctx.JSON(statementErrorStatus(err), entities.Err(err))
+			return
+		}
+
+		var body bytes.Buffer
+		if err = usecase.RenderStatement(&body, format, *result); err != nil {
+			ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+			return
+		}
+
+		filename := fmt.Sprintf("statement-%d-%s-%s.%s", request.AccountID,
+			request.From.UTC().Format("20060102"), request.To.UTC().Format("20060102"), format)
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		ctx.Data(http.StatusOK, usecase.ContentType(format), body.Bytes())
+	}
+}
+
+func statementErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, api_error.ErrInvalidStatementPeriod), errors.Is(err, api_error.ErrInvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, api_error.ErrNotAccountOwner):
+		return http.StatusForbidden
+	case errors.Is(err, api_error.ErrAccountNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/currency"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatOFX     = "ofx"
+	FormatCamt053 = "xml"
+
+	// bankID identifies this bank in OFX and camt.053 documents
+	bankID = "BANKAPI"
+
+	ofxTimeLayout  = "20060102150405.000[+0:UTC]"
+	camtTimeLayout = "2006-01-02T15:04:05Z"
+)
+
+// Statement is a whole statement period, as rendered by the export formats
+type Statement struct {
+	Account        db.Account
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	Entries        []db.StatementEntry
+	GeneratedAt    time.Time
+}
+
+// TransactionID is the identifier of an entry in every export format, it is derived from entries.id only so that
+// importing the same entry twice can be detected by the accounting tool
+func TransactionID(entry db.StatementEntry) string {
+	return "E" + strconv.FormatInt(entry.ID, 10)
+}
+
+// ContentType of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "application/xml; charset=utf-8"
+	}
+}
+
+// RenderStatement writes the statement to w in the given export format
+func RenderStatement(w io.Writer, format string, statement Statement) error {
+	switch format {
+	case FormatCSV:
+		return renderCSV(w, statement)
+	case FormatOFX:
+		return renderOFX(w, statement)
+	case FormatCamt053:
+		return renderCamt053(w, statement)
+	default:
+		return fmt.Errorf("unknown statement format %q", format)
+	}
+}
+
+func description(entry db.StatementEntry) string {
+	if !entry.TransferID.Valid {
+		return "Entry " + strconv.FormatInt(entry.ID, 10)
+	}
+
+	if !entry.CounterpartyAccountID.Valid {
+		return "Transfer " + strconv.FormatInt(entry.TransferID.Int64, 10)
+	}
+
+	direction := "to"
+	if entry.Amount > 0 {
+		direction = "from"
+	}
+
+	return fmt.Sprintf("Transfer %d %s account %d", entry.TransferID.Int64, direction, entry.CounterpartyAccountID.Int64)
+}
+
+func renderCSV(w io.Writer, statement Statement) error {
+	code := statement.Account.Currency
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{
+		"transaction_id", "booked_at", "amount", "currency", "balance",
+		"transfer_id", "counterparty_account_id", "counterparty_owner", "description",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range statement.Entries {
+		var transferID, counterpartyID string
+		if entry.TransferID.Valid {
+			transferID = strconv.FormatInt(entry.TransferID.Int64, 10)
+		}
+
+		if entry.CounterpartyAccountID.Valid {
+			counterpartyID = strconv.FormatInt(entry.CounterpartyAccountID.Int64, 10)
+		}
+
+		err = writer.Write([]string{
+			TransactionID(entry),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			currency.FormatAmount(entry.Amount, code),
+			code,
+			currency.FormatAmount(entry.Balance, code),
+			transferID,
+			counterpartyID,
+			entry.CounterpartyOwner.String,
+			description(entry),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Response struct {
+			Status   ofxStatus `xml:"STATUS"`
+			DTServer string    `xml:"DTSERVER"`
+			Language string    `xml:"LANGUAGE"`
+		} `xml:"SONRS"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		Transaction struct {
+			TrnUID    string          `xml:"TRNUID"`
+			Status    ofxStatus       `xml:"STATUS"`
+			Statement ofxStatementRes `xml:"STMTRS"`
+		} `xml:"STMTTRNRS"`
+	} `xml:"BANKMSGSRSV1"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxStatementRes struct {
+	CurDef  string `xml:"CURDEF"`
+	Account struct {
+		BankID   string `xml:"BANKID"`
+		AcctID   string `xml:"ACCTID"`
+		AcctType string `xml:"ACCTTYPE"`
+	} `xml:"BANKACCTFROM"`
+	TranList struct {
+		DTStart      string           `xml:"DTSTART"`
+		DTEnd        string           `xml:"DTEND"`
+		Transactions []ofxTransaction `xml:"STMTTRN"`
+	} `xml:"BANKTRANLIST"`
+	LedgerBal struct {
+		BalAmt string `xml:"BALAMT"`
+		DTAsOf string `xml:"DTASOF"`
+	} `xml:"LEDGERBAL"`
+}
+
+type ofxTransaction struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FitID    string `xml:"FITID"`
+	Name     string `xml:"NAME,omitempty"`
+	Memo     string `xml:"MEMO"`
+}
+
+func renderOFX(w io.Writer, statement Statement) error {
+	code := statement.Account.Currency
+
+	var doc ofxDocument
+	doc.SignOn.Response.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Response.DTServer = statement.GeneratedAt.UTC().Format(ofxTimeLayout)
+	doc.SignOn.Response.Language = "ENG"
+
+	doc.Bank.Transaction.TrnUID = "0"
+	doc.Bank.Transaction.Status = ofxStatus{Code: 0, Severity: "INFO"}
+
+	stmt := &doc.Bank.Transaction.Statement
+	stmt.CurDef = code
+	stmt.Account.BankID = bankID
+	stmt.Account.AcctID = strconv.FormatInt(statement.Account.ID, 10)
+	stmt.Account.AcctType = "CHECKING"
+	stmt.TranList.DTStart = statement.From.UTC().Format(ofxTimeLayout)
+	stmt.TranList.DTEnd = statement.To.UTC().Format(ofxTimeLayout)
+
+	for _, entry := range statement.Entries {
+		trnType := "CREDIT"
+		if entry.Amount < 0 {
+			trnType = "DEBIT"
+		}
+
+		stmt.TranList.Transactions = append(stmt.TranList.Transactions, ofxTransaction{
+			TrnType:  trnType,
+			DTPosted: entry.CreatedAt.UTC().Format(ofxTimeLayout),
+			TrnAmt:   currency.FormatAmount(entry.Amount, code),
+			FitID:    TransactionID(entry),
+			Name:     entry.CounterpartyOwner.String,
+			Memo:     description(entry),
+		})
+	}
+
+	stmt.LedgerBal.BalAmt = currency.FormatAmount(statement.ClosingBalance, code)
+	stmt.LedgerBal.DTAsOf = statement.To.UTC().Format(ofxTimeLayout)
+
+	header := xml.Header + `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	return encodeXML(w, doc)
+}
+
+type camtDocument struct {
+	XMLName   xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+	Statement struct {
+		GroupHeader struct {
+			MsgID   string `xml:"MsgId"`
+			CreDtTm string `xml:"CreDtTm"`
+		} `xml:"GrpHdr"`
+		Stmt camtStatement `xml:"Stmt"`
+	} `xml:"BkToCstmrStmt"`
+}
+
+type camtStatement struct {
+	ID      string `xml:"Id"`
+	CreDtTm string `xml:"CreDtTm"`
+	FrToDt  struct {
+		FrDtTm string `xml:"FrDtTm"`
+		ToDtTm string `xml:"ToDtTm"`
+	} `xml:"FrToDt"`
+	Acct struct {
+		ID   camtAccountID `xml:"Id"`
+		Ccy  string        `xml:"Ccy"`
+		Ownr struct {
+			Nm string `xml:"Nm"`
+		} `xml:"Ownr"`
+	} `xml:"Acct"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtAccountID struct {
+	Othr struct {
+		ID string `xml:"Id"`
+	} `xml:"Othr"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtDate struct {
+	DtTm string `xml:"DtTm"`
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Dt        camtDate   `xml:"Dt"`
+}
+
+type camtEntry struct {
+	NtryRef     string     `xml:"NtryRef"`
+	Amt         camtAmount `xml:"Amt"`
+	CdtDbtInd   string     `xml:"CdtDbtInd"`
+	Sts         string     `xml:"Sts"`
+	BookgDt     camtDate   `xml:"BookgDt"`
+	ValDt       camtDate   `xml:"ValDt"`
+	AcctSvcrRef string     `xml:"AcctSvcrRef"`
+	BkTxCd      struct {
+		Cd   string `xml:"Prtry>Cd"`
+		Issr string `xml:"Prtry>Issr"`
+	} `xml:"BkTxCd"`
+	Details struct {
+		Refs struct {
+			AcctSvcrRef string `xml:"AcctSvcrRef"`
+		} `xml:"Refs"`
+		RltdPties  *camtRelatedParties `xml:"RltdPties,omitempty"`
+		AddtlTxInf string              `xml:"AddtlTxInf"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+type camtRelatedParties struct {
+	Dbtr     *camtParty     `xml:"Dbtr,omitempty"`
+	DbtrAcct *camtAccountID `xml:"DbtrAcct>Id,omitempty"`
+	Cdtr     *camtParty     `xml:"Cdtr,omitempty"`
+	CdtrAcct *camtAccountID `xml:"CdtrAcct>Id,omitempty"`
+}
+
+type camtParty struct {
+	Nm string `xml:"Nm"`
+}
+
+// camtAmountOf splits a signed amount into the unsigned amount and the credit/debit indicator camt.053 expects
+func camtAmountOf(amount int64, code string) (camtAmount, string) {
+	indicator := "CRDT"
+	if amount < 0 {
+		indicator = "DBIT"
+		amount = -amount
+	}
+
+	return camtAmount{Ccy: code, Value: currency.FormatAmount(amount, code)}, indicator
+}
+
+func renderCamt053(w io.Writer, statement Statement) error {
+	code := statement.Account.Currency
+	statementID := fmt.Sprintf("STMT-%d-%s", statement.Account.ID, statement.From.UTC().Format("20060102150405"))
+	generatedAt := statement.GeneratedAt.UTC().Format(camtTimeLayout)
+
+	var doc camtDocument
+	doc.Statement.GroupHeader.MsgID = statementID
+	doc.Statement.GroupHeader.CreDtTm = generatedAt
+
+	stmt := &doc.Statement.Stmt
+	stmt.ID = statementID
+	stmt.CreDtTm = generatedAt
+	stmt.FrToDt.FrDtTm = statement.From.UTC().Format(camtTimeLayout)
+	stmt.FrToDt.ToDtTm = statement.To.UTC().Format(camtTimeLayout)
+	stmt.Acct.ID.Othr.ID = strconv.FormatInt(statement.Account.ID, 10)
+	stmt.Acct.Ccy = code
+	stmt.Acct.Ownr.Nm = statement.Account.Owner
+
+	opening, openingIndicator := camtAmountOf(statement.OpeningBalance, code)
+	closing, closingIndicator := camtAmountOf(statement.ClosingBalance, code)
+	stmt.Balances = []camtBalance{
+		{Code: "OPBD", Amt: opening, CdtDbtInd: openingIndicator, Dt: camtDate{DtTm: stmt.FrToDt.FrDtTm}},
+		{Code: "CLBD", Amt: closing, CdtDbtInd: closingIndicator, Dt: camtDate{DtTm: stmt.FrToDt.ToDtTm}},
+	}
+
+	for _, entry := range statement.Entries {
+		bookedAt := entry.CreatedAt.UTC().Format(camtTimeLayout)
+		amount, indicator := camtAmountOf(entry.Amount, code)
+
+		ntry := camtEntry{
+			NtryRef:     TransactionID(entry),
+			Amt:         amount,
+			CdtDbtInd:   indicator,
+			Sts:         "BOOK",
+			BookgDt:     camtDate{DtTm: bookedAt},
+			ValDt:       camtDate{DtTm: bookedAt},
+			AcctSvcrRef: TransactionID(entry),
+		}
+		ntry.BkTxCd.Cd = "TRANSFER"
+		ntry.BkTxCd.Issr = bankID
+		ntry.Details.Refs.AcctSvcrRef = TransactionID(entry)
+		ntry.Details.AddtlTxInf = description(entry)
+
+		if entry.CounterpartyAccountID.Valid {
+			party := &camtParty{Nm: entry.CounterpartyOwner.String}
+			account := &camtAccountID{}
+			account.Othr.ID = strconv.FormatInt(entry.CounterpartyAccountID.Int64, 10)
+
+			// a credit was paid by the counterparty, a debit was paid to it
+			if entry.Amount > 0 {
+				ntry.Details.RltdPties = &camtRelatedParties{Dbtr: party, DbtrAcct: account}
+			} else {
+				ntry.Details.RltdPties = &camtRelatedParties{Cdtr: party, CdtrAcct: account}
+			}
+		}
+
+		stmt.Entries = append(stmt.Entries, ntry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	return encodeXML(w, doc)
+}
+
+func encodeXML(w io.Writer, doc interface{}) error {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
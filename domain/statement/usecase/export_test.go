@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatement(currency string) Statement {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	bookedAt := from.Add(10 * time.Hour)
+
+	return Statement{
+		Account:        db.Account{ID: 7, Owner: "alice", Currency: currency},
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 1000,
+		ClosingBalance: 1250,
+		GeneratedAt:    from.AddDate(0, 1, 1),
+		Entries: []db.StatementEntry{
+			{
+				ListStatementEntriesRow: db.ListStatementEntriesRow{
+					ID:                    11,
+					AccountID:             7,
+					Amount:                -250,
+					CreatedAt:             bookedAt,
+					TransferID:            sql.NullInt64{Int64: 3, Valid: true},
+					CounterpartyAccountID: sql.NullInt64{Int64: 8, Valid: true},
+					CounterpartyOwner:     sql.NullString{String: "bob & co", Valid: true},
+				},
+				Balance: 750,
+			},
+			{
+				ListStatementEntriesRow: db.ListStatementEntriesRow{
+					ID:        12,
+					AccountID: 7,
+					Amount:    500,
+					CreatedAt: bookedAt.Add(time.Hour),
+				},
+				Balance: 1250,
+			},
+		},
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, RenderStatement(&out, FormatCSV, testStatement("USD")))
+
+	require.Equal(t, strings.Join([]string{
+		"transaction_id,booked_at,amount,currency,balance,transfer_id,counterparty_account_id,counterparty_owner,description",
+		"E11,2024-03-01T10:00:00Z,-2.50,USD,7.50,3,8,bob & co,Transfer 3 to account 8",
+		"E12,2024-03-01T11:00:00Z,5.00,USD,12.50,,,,Entry 12",
+		"",
+	}, "\n"), out.String())
+
+	// currencies without minor units aren't divided
+	out.Reset()
+	require.NoError(t, RenderStatement(&out, FormatCSV, testStatement("JPY")))
+	require.Contains(t, out.String(), "E11,2024-03-01T10:00:00Z,-250,JPY,750,")
+}
+
+func TestRenderOFX(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, RenderStatement(&out, FormatOFX, testStatement("USD")))
+	require.Contains(t, out.String(), `<?OFX OFXHEADER="200" VERSION="220"`)
+	require.Contains(t, out.String(), "bob &amp; co")
+
+	var doc ofxDocument
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &doc))
+
+	stmt := doc.Bank.Transaction.Statement
+	require.Equal(t, "USD", stmt.CurDef)
+	require.Equal(t, "7", stmt.Account.AcctID)
+	require.Equal(t, "20240301000000.000[+0:UTC]", stmt.TranList.DTStart)
+	require.Equal(t, "12.50", stmt.LedgerBal.BalAmt)
+
+	require.Len(t, stmt.TranList.Transactions, 2)
+	require.Equal(t, ofxTransaction{
+		TrnType:  "DEBIT",
+		DTPosted: "20240301100000.000[+0:UTC]",
+		TrnAmt:   "-2.50",
+		FitID:    "E11",
+		Name:     "bob & co",
+		Memo:     "Transfer 3 to account 8",
+	}, stmt.TranList.Transactions[0])
+	require.Equal(t, "CREDIT", stmt.TranList.Transactions[1].TrnType)
+	require.Equal(t, "E12", stmt.TranList.Transactions[1].FitID)
+}
+
+func TestRenderCamt053(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, RenderStatement(&out, FormatCamt053, testStatement("KWD")))
+	require.Contains(t, out.String(), `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">`)
+
+	var doc camtDocument
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &doc))
+
+	stmt := doc.Statement.Stmt
+	require.Equal(t, "7", stmt.Acct.ID.Othr.ID)
+	require.Equal(t, "KWD", stmt.Acct.Ccy)
+
+	require.Len(t, stmt.Balances, 2)
+	require.Equal(t, "OPBD", stmt.Balances[0].Code)
+	require.Equal(t, camtAmount{Ccy: "KWD", Value: "1.000"}, stmt.Balances[0].Amt)
+	require.Equal(t, "CLBD", stmt.Balances[1].Code)
+	require.Equal(t, "1.250", stmt.Balances[1].Amt.Value)
+
+	require.Len(t, stmt.Entries, 2)
+	debit := stmt.Entries[0]
+	require.Equal(t, "E11", debit.NtryRef)
+	require.Equal(t, camtAmount{Ccy: "KWD", Value: "0.250"}, debit.Amt)
+	require.Equal(t, "DBIT", debit.CdtDbtInd)
+	require.Equal(t, "2024-03-01T10:00:00Z", debit.BookgDt.DtTm)
+	require.NotNil(t, debit.Details.RltdPties)
+	require.Equal(t, "bob & co", debit.Details.RltdPties.Cdtr.Nm)
+	require.Equal(t, "8", debit.Details.RltdPties.CdtrAcct.Othr.ID)
+	require.Nil(t, debit.Details.RltdPties.Dbtr)
+
+	credit := stmt.Entries[1]
+	require.Equal(t, "CRDT", credit.CdtDbtInd)
+	require.Equal(t, "0.500", credit.Amt.Value)
+	require.Nil(t, credit.Details.RltdPties)
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	require.Error(t, RenderStatement(&bytes.Buffer{}, "pdf", testStatement("USD")))
+}
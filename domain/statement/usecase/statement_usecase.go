@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	accountUsecase "github.com/dhiemaz/bank-api/domain/account/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+)
+
+// exportPageSize is the number of entries read at a time by an export
+const exportPageSize = 500
+
+type StatementUseCase interface {
+	GetStatement(ctx context.Context, caller *token.Payload, request entities.GetStatementRequest, pagination *utils.PaginationQuery) (*db.StatementTxResult, string, error)
+	ExportStatement(ctx context.Context, caller *token.Payload, request entities.GetStatementRequest) (*Statement, error)
+}
+
+type UseCase struct {
+	account accountUsecase.AccountUseCase
+	db      db.Store
+}
+
+func NewStatementUseCase(db db.Store, account accountUsecase.AccountUseCase) *UseCase {
+	return &UseCase{db: db, account: account}
+}
+
+// ExportStatement : the whole statement of an account of the authenticated user for the period [from, to), read
+// page by page. Every page derives its running balance from the end of the previous one, so the pages stay
+// consistent even when entries are posted in between.
+func (statement *UseCase) ExportStatement(ctx context.Context, caller *token.Payload, request entities.GetStatementRequest) (*Statement, error) {
+	pagination := &utils.PaginationQuery{Limit: exportPageSize}
+
+	page, nextCursor, err := statement.GetStatement(ctx, caller, request, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Statement{
+		Account:        page.Account,
+		From:           request.From,
+		To:             request.To,
+		OpeningBalance: page.OpeningBalance,
+		ClosingBalance: page.ClosingBalance,
+		Entries:        page.Entries,
+		GeneratedAt:    time.Now(),
+	}
+
+	for nextCursor != "" {
+		last := page.Entries[len(page.Entries)-1]
+		pagination.AfterCreatedAt, pagination.AfterID = last.CreatedAt, last.ID
+
+		page, nextCursor, err = statement.getPage(ctx, request, pagination)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Entries = append(result.Entries, page.Entries...)
+		result.ClosingBalance = page.ClosingBalance
+	}
+
+	return result, nil
+}
+
+// GetStatement : one page of the statement of an account of the authenticated user for the period [from, to),
+// with the cursor of the next page
+func (statement *UseCase) GetStatement(ctx context.Context, caller *token.Payload, request entities.GetStatementRequest, pagination *utils.PaginationQuery) (*db.StatementTxResult, string, error) {
+	if !request.To.After(request.From) {
+		return nil, "", api_error.ErrInvalidStatementPeriod
+	}
+
+	account, err := statement.account.IsValidAccount(ctx, request.AccountID)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get statement", "payload": request}).
+			Errorf("failed validate account [%d], error : %v", request.AccountID, err)
+
+		return nil, "", err
+	}
+
+	if caller.Username != account.Owner {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get statement", "payload": request}).
+			Errorf("failed get statement, account doesn't belong to authenticated user")
+
+		return nil, "", api_error.ErrNotAccountOwner
+	}
+
+	return statement.getPage(ctx, request, pagination)
+}
+
+// getPage : one page of the statement of an account, the caller checked the account and the period
+func (statement *UseCase) getPage(ctx context.Context, request entities.GetStatementRequest, pagination *utils.PaginationQuery) (*db.StatementTxResult, string, error) {
+	arg := db.StatementTxParam{
+		AccountID: request.AccountID,
+		From:      request.From,
+		To:        request.To,
+		PageSize:  pagination.FetchSize(),
+	}
+	arg.AfterCreatedAt, arg.AfterID = pagination.AfterParams()
+
+	result, err := statement.db.StatementTx(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get statement", "payload": request}).
+			Errorf("failed get statement, error : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", api_error.ErrAccountNotFound
+		}
+
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if pagination.HasNextPage(len(result.Entries)) {
+		result.Entries = result.Entries[:pagination.Limit]
+		last := result.Entries[len(result.Entries)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return &result, nextCursor, nil
+}
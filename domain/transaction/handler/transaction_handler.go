@@ -1,14 +1,24 @@
 package handler
 
 import (
+	"errors"
 	"github.com/dhiemaz/bank-api/domain/transaction/usecase"
 	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/middlewares"
 	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 type Handler struct {
 	Usecase usecase.TransferUseCase
 }
@@ -26,9 +36,10 @@ func NewTransactionHandler(usecase usecase.TransferUseCase) *Handler {
 //	@Tags			transfers
 //	@Accept			json
 //	@Produce		json
-//	@Param			body	body		createTransferReq	true	"Transfer to create"
-//	@Success		200		{object}	response.JSON{data=transferResponse}
-//	@Failure		400,500	{object}	response.JSON{}
+//	@Param			Idempotency-Key	header		string				false	"Key making retries of the same transfer safe"
+//	@Param			body			body		createTransferReq	true	"Transfer to create"
+//	@Success		200				{object}	response.JSON{data=transferResponse}
+//	@Failure		400,404,409,422,500	{object}	response.JSON{}
 //	@Security		bearerAuth
 //	@Router			/transfers [post]
 func (transaction *Handler) CreateTransfer(ctx *gin.Context) {
@@ -37,19 +48,17 @@ func (transaction *Handler) CreateTransfer(ctx *gin.Context) {
 		return
 	}
 
-	fromAccount, toAccount, err := transaction.Usecase.ValidateTransfer(ctx, request.FromAccountID, request.ToAccountID)
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	request.IdempotencyKey = ctx.GetHeader(IdempotencyKeyHeader)
+
+	result, err := transaction.Usecase.CreateTransfer(ctx, payload, request)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+		ctx.JSON(transferErrorStatus(err), entities.Err(err))
 		return
 	}
 
-	request.ToAccountID = toAccount.ID
-	request.FromAccountID = fromAccount.ID
-
-	result, err := transaction.Usecase.CreateTransfer(ctx, request)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
-		return
+	if result.Replayed {
+		ctx.Header(IdempotentReplayedHeader, "true")
 	}
 
 	res := utils.FromTransferTxToTransferResponse(result)
@@ -63,36 +72,257 @@ func (transaction *Handler) CreateTransfer(ctx *gin.Context) {
 //	@Tags			transfers
 //	@Accept			json
 //	@Produce		json
-//	@Param			id			path		int64	true	"Account ID"
-//	@Param			page_id		query		int32	true	"Page ID"
-//	@Param			page_size	query		int32	true	"Page Size"
-//	@Success		200			{object}	response.JSON{data=transferResponse}
-//	@Failure		400,500		{object}	response.JSON{}
+//	@Param			id						path		int64	true	"Account ID"
+//	@Param			cursor					query		string	false	"Cursor of the next page"
+//	@Param			limit					query		int32	false	"Page Size, 20 by default"
+//	@Param			direction				query		string	false	"incoming or outgoing"
+//	@Param			min_amount				query		int64	false	"Minimum amount"
+//	@Param			max_amount				query		int64	false	"Maximum amount"
+//	@Param			from					query		string	false	"Created at or after (RFC 3339)"
+//	@Param			to						query		string	false	"Created before (RFC 3339)"
+//	@Param			counterparty_account_id	query		int64	false	"Other account of the transfer"
+//	@Success		200						{object}	response.JSON{data=[]transferResponse}
+//	@Failure		400,401,500				{object}	response.JSON{}
 //	@Security		bearerAuth
 //	@Router			/transfers/{id} [get]
 func (transaction *Handler) GetTransfersList(ctx *gin.Context) {
 	var request entities.GetTransferRequest
-	var pgQuery *utils.PaginationQuery
-	var err error
-
 	if err := utils.ParseURI(ctx, &request); err != nil {
 		return
 	}
 
-	if pgQuery, err = utils.ParsePagination(ctx); err != nil {
-		ctx.JSON(http.StatusBadRequest, entities.Err(err))
+	var filter entities.ListTransfersFilter
+	if err := utils.ParseQuery(ctx, &filter); err != nil {
+		return
+	}
+
+	pgQuery, err := utils.ParsePagination(ctx)
+	if err != nil {
 		return
 	}
 
-	transfers, err := transaction.Usecase.GetListTransfer(ctx, request, pgQuery)
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	transfers, nextCursor, err := transaction.Usecase.GetListTransfer(ctx, payload, request, filter, pgQuery)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+		ctx.JSON(transferErrorStatus(err), entities.Err(err))
+		return
 	}
 
-	var responsesTransfer []*entities.TransferResponse
+	responsesTransfer := []*entities.TransferResponse{}
 	for _, transfer := range transfers {
 		responsesTransfer = append(responsesTransfer, utils.MapTransferToResponse(transfer))
 	}
 
-	ctx.JSON(http.StatusOK, entities.Success(responsesTransfer))
+	ctx.JSON(http.StatusOK, entities.SuccessPage(responsesTransfer, nextCursor))
+}
+
+// ReverseTransfer godoc
+//
+//	@Summary		reverses a transfer fully or partially
+//	@Description	moves money of a transfer back to its sender, amount 0 reverses what is left of the transfer
+//	@Tags			transfers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		int64					true	"Transfer ID"
+//	@Param			body				body		reverseTransferReq		true	"Reversal to create"
+//	@Success		200					{object}	response.JSON{data=transferReversalResponse}
+//	@Failure		400,404,409,422,500	{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/{id}/reverse [post]
+func (transaction *Handler) ReverseTransfer(ctx *gin.Context) {
+	var uri entities.TransferURIRequest
+	if err := utils.ParseURI(ctx, &uri); err != nil {
+		return
+	}
+
+	var request entities.ReverseTransferRequest
+	if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	request.TransferID = uri.ID
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	result, err := transaction.Usecase.ReverseTransfer(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(transferErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.FromReverseTransferTxToResponse(result)))
+}
+
+// PreviewFee godoc
+//
+//	@Summary		previews the fee of a transfer
+//	@Description	computes the fee a transfer would be charged on top of its amount, before it is created
+//	@Tags			transfers
+//	@Produce		json
+//	@Param			from_account_id		query		int64	true	"Account to transfer from"
+//	@Param			to_account_id		query		int64	true	"Account to transfer to"
+//	@Param			amount				query		int64	true	"Amount to transfer"
+//	@Param			transfer_type		query		string	false	"transfer by default, fx between currencies"	Enums(transfer, scheduled, batch)
+//	@Success		200					{object}	response.JSON{data=feePreviewResponse}
+//	@Failure		400,401,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/fee [get]
+func (transaction *Handler) PreviewFee(ctx *gin.Context) {
+	var request entities.PreviewFeeRequest
+	if err := utils.ParseQuery(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	breakdown, err := transaction.Usecase.PreviewFee(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(transferErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapFeePreviewToResponse(request.Amount, *breakdown)))
+}
+
+// CreateTransferBatch godoc
+//
+//	@Summary		creates a batch of transfers from one account
+//	@Description	transfers from one account to many, all or nothing in atomic mode or item by item in best_effort mode. The items are sent as JSON, or as a CSV file of to_account_id,amount rows uploaded in a multipart form. A best_effort batch is accepted as processing and settled in the background, poll it with GET /transfers/batch/{id}.
+//	@Tags			transfers
+//	@Accept			json,mpfd
+//	@Produce		json
+//	@Param			body				body		createTransferBatchReq	false	"Batch to create, as JSON"
+//	@Param			from_account_id		formData	int64					false	"Account to transfer from, with a CSV file"
+//	@Param			mode				formData	string					false	"atomic or best_effort, with a CSV file"
+//	@Param			file				formData	file					false	"CSV file of the items"
+//	@Success		201					{object}	response.JSON{data=transferBatchResponse}	"atomic batch"
+//	@Success		202					{object}	response.JSON{data=transferBatchResponse}	"best_effort batch, processing"
+//	@Failure		400,401,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/batch [post]
+func (transaction *Handler) CreateTransferBatch(ctx *gin.Context) {
+	var request entities.CreateTransferBatchRequest
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		if err := ctx.ShouldBind(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, entities.Err(err))
+			return
+		}
+
+		file, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, entities.Err(err))
+			return
+		}
+
+		csvFile, err := file.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, entities.Err(err))
+			return
+		}
+		defer csvFile.Close()
+
+		request.Items, err = utils.ParseTransferBatchCSV(csvFile)
+		if err != nil {
+			ctx.JSON(transferErrorStatus(err), entities.Err(err))
+			return
+		}
+	} else if err := utils.ParseBody(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	result, err := transaction.Usecase.CreateTransferBatch(ctx, payload, request)
+	if err != nil {
+		ctx.JSON(transferErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Batch.Mode == db.TransferBatchModeBestEffort {
+		status = http.StatusAccepted
+	}
+
+	ctx.JSON(status, entities.Success(utils.MapTransferBatchTxToResponse(result)))
+}
+
+// GetTransferBatch godoc
+//
+//	@Summary		gets a batch of transfers
+//	@Description	gets a batch of transfers of the currently logged-in user with the status of each item
+//	@Tags			transfers
+//	@Produce		json
+//	@Param			id				path		int64	true	"Batch ID"
+//	@Success		200				{object}	response.JSON{data=transferBatchResponse}
+//	@Failure		400,404,500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/batch/{id} [get]
+func (transaction *Handler) GetTransferBatch(ctx *gin.Context) {
+	var request entities.TransferBatchURIRequest
+	if err := utils.ParseURI(ctx, &request); err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	result, err := transaction.Usecase.GetTransferBatch(ctx, payload, request.ID)
+	if err != nil {
+		ctx.JSON(transferErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(utils.MapTransferBatchTxToResponse(result)))
+}
+
+// GetTransferBatches godoc
+//
+//	@Summary		lists the batches of transfers
+//	@Description	lists the batches of transfers of the currently logged-in user without their items, latest first
+//	@Tags			transfers
+//	@Produce		json
+//	@Param			cursor			query		string	false	"Cursor of the next page"
+//	@Param			limit			query		int32	false	"Page Size, 20 by default"
+//	@Success		200				{object}	response.JSON{data=[]transferBatchResponse}
+//	@Failure		400,500			{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/transfers/batch [get]
+func (transaction *Handler) GetTransferBatches(ctx *gin.Context) {
+	pgQuery, err := utils.ParsePagination(ctx)
+	if err != nil {
+		return
+	}
+
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	batches, nextCursor, err := transaction.Usecase.GetTransferBatches(ctx, payload, pgQuery)
+	if err != nil {
+		ctx.JSON(transferErrorStatus(err), entities.Err(err))
+		return
+	}
+
+	responses := []entities.TransferBatchResponse{}
+	for _, batch := range batches {
+		responses = append(responses, utils.MapTransferBatchToResponse(batch))
+	}
+
+	ctx.JSON(http.StatusOK, entities.SuccessPage(responses, nextCursor))
+}
+
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, api_error.ErrInsufficientFunds), api_error.IsReversalExceedsRemaining(err),
+		errors.Is(err, api_error.ErrTransferLimitExceeded), errors.Is(err, api_error.ErrAccountDeleted):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, api_error.ErrIdempotencyKeyConflict), errors.Is(err, api_error.ErrTransferFullyReversed),
+		errors.Is(err, api_error.ErrFxQuoteExpired), errors.Is(err, api_error.ErrFxQuoteUsed):
+		return http.StatusConflict
+	case errors.Is(err, api_error.ErrAccountFrozen), errors.Is(err, api_error.ErrAccountDormant),
+		errors.Is(err, api_error.ErrNotAccountOwner):
+		return http.StatusForbidden
+	case errors.Is(err, api_error.ErrTransferNotFound), errors.Is(err, api_error.ErrFxQuoteNotFound),
+		errors.Is(err, api_error.ErrTransferBatchNotFound), errors.Is(err, api_error.ErrAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, api_error.ErrInvalidIdempotencyKey), api_error.IsCurrencyMismatch(err),
+		errors.Is(err, api_error.ErrFxQuoteMismatch), errors.Is(err, api_error.ErrFxAmountTooSmall),
+		errors.Is(err, api_error.ErrFxReversalUnsupported), errors.Is(err, api_error.ErrInvalidBatchSize),
+		api_error.IsInvalidBatchCSV(err), errors.Is(err, api_error.ErrInvalidCursor),
+		errors.Is(err, api_error.ErrSameAccountTransfer):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
+	"github.com/dhiemaz/bank-api/utils/token"
+)
+
+const maxTransferBatchItems = 1000
+
+// CreateTransferBatch : transfer from one account of the caller to many accounts. Every item is checked
+// with ValidateTransfer first, an item that fails it is recorded as failed with the reason. The transfer limits count
+// the earlier items of the batch. The batch is executed all or nothing, or item by item in best effort mode, and
+// kept with the status of each item. A best effort batch is returned as processing and settled in the background,
+// its progress is polled with GetTransferBatch.
+func (transfer *UseCase) CreateTransferBatch(ctx context.Context, caller *token.Payload, request entities.CreateTransferBatchRequest) (*db.TransferBatchTxResult, error) {
+	if len(request.Items) == 0 || len(request.Items) > maxTransferBatchItems {
+		return nil, api_error.ErrInvalidBatchSize
+	}
+
+	from, err := transfer.account.IsValidAccount(ctx, request.FromAccountID)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create transfer batch", "from_account": request.FromAccountID}).
+			Errorf("failed validate from_account [%d], error : %v", request.FromAccountID, err)
+
+		return nil, api_error.ErrAccountNotFound
+	}
+
+	if !isUserAccountOwner(caller, from) {
+		return nil, api_error.ErrNotAccountOwner
+	}
+
+	arg := db.TransferBatchTxParam{
+		Owner:                caller.Username,
+		FromAccountID:        request.FromAccountID,
+		Mode:                 request.Mode,
+		Items:                make([]db.TransferBatchItemParam, 0, len(request.Items)),
+		FrozenAcceptsCredits: transfer.frozenAcceptsCredits,
+		Limits:               transfer.limits,
+		Audit:                transfer.auditor.CreateTransferBatch(caller),
+	}
+
+	var pending limit.Usage
+	for _, item := range request.Items {
+		itemParam := db.TransferBatchItemParam{
+			ToAccountID: item.ToAccountID,
+			Amount:      item.Amount,
+			Fee:         transfer.fees.Quote(from.Currency, fee.TypeBatch, item.Amount),
+		}
+		if _, _, err = transfer.validateTransfer(ctx, caller, request.FromAccountID, item.ToAccountID, item.Amount, pending); err != nil {
+			itemParam.Error = err.Error()
+		} else {
+			pending = pending.Add(item.Amount)
+		}
+
+		arg.Items = append(arg.Items, itemParam)
+	}
+
+	result, err := transfer.db.TransferBatchTx(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create transfer batch", "from_account": request.FromAccountID}).
+			Errorf("failed create transfer batch, err : %v", err)
+
+		return nil, err
+	}
+
+	if arg.Mode == db.TransferBatchModeBestEffort {
+		go transfer.settleTransferBatch(arg, result)
+	}
+
+	return &result, nil
+}
+
+// settleTransferBatch executes the items of a best effort batch after the request that created it has been answered
+func (transfer *UseCase) settleTransferBatch(arg db.TransferBatchTxParam, batch db.TransferBatchTxResult) {
+	if _, err := transfer.db.SettleTransferBatchTx(context.Background(), arg, batch); err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "settle transfer batch", "batch_id": batch.Batch.ID}).
+			Errorf("failed settle transfer batch [%d], err : %v", batch.Batch.ID, err)
+	}
+}
+
+// GetTransferBatch : a batch of the caller with the status of its items, polled while a best effort
+// batch is processing
+func (transfer *UseCase) GetTransferBatch(ctx context.Context, caller *token.Payload, id int64) (*db.TransferBatchTxResult, error) {
+	batch, err := transfer.db.GetTransferBatch(ctx, id)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer batch", "batch_id": id}).
+			Errorf("failed get transfer batch, err : %v", err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrTransferBatchNotFound
+		}
+
+		return nil, err
+	}
+
+	if batch.Owner != caller.Username {
+		// batches of other users are reported as missing, so ids can't be probed
+		return nil, api_error.ErrTransferBatchNotFound
+	}
+
+	items, err := transfer.db.ListTransferBatchItems(ctx, id)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer batch", "batch_id": id}).
+			Errorf("failed get transfer batch items, err : %v", err)
+
+		return nil, err
+	}
+
+	return &db.TransferBatchTxResult{Batch: batch, Items: items}, nil
+}
+
+// GetTransferBatches : one page of the batches of the caller, latest first
+func (transfer *UseCase) GetTransferBatches(ctx context.Context, caller *token.Payload, pagination *utils.PaginationQuery) ([]db.TransferBatch, string, error) {
+	arg := db.ListTransferBatchesParams{
+		Owner:    caller.Username,
+		PageSize: pagination.FetchSize(),
+	}
+	arg.AfterCreatedAt, arg.AfterID = pagination.AfterParams()
+
+	batches, err := transfer.db.ListTransferBatches(ctx, arg)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer batches", "username": caller.Username}).
+			Errorf("failed get transfer batches, err : %v", err)
+
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if pagination.HasNextPage(len(batches)) {
+		batches = batches[:pagination.Limit]
+		last := batches[len(batches)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return batches, nextCursor, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/entities"
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferBatchSettlesBestEffortInBackground(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), int64(1)).AnyTimes().
+		Return(db.Account{ID: 1, Owner: "alice", Currency: "USD", Balance: 1000, Status: db.AccountStatusActive}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), int64(2)).AnyTimes().
+		Return(db.Account{ID: 2, Owner: "bob", Currency: "USD", Status: db.AccountStatusActive}, nil)
+
+	recorded := db.TransferBatchTxResult{
+		Batch: db.TransferBatch{ID: 3, Owner: "alice", FromAccountID: 1, Mode: db.TransferBatchModeBestEffort, Status: db.TransferBatchStatusProcessing, TotalItems: 1},
+		Items: []db.TransferBatchItem{{ID: 4, BatchID: 3, Line: 1, ToAccountID: 2, Amount: 100, Status: db.TransferBatchItemStatusPending}},
+	}
+	store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Return(recorded, nil)
+
+	settled := make(chan db.TransferBatchTxResult, 1)
+	store.EXPECT().SettleTransferBatchTx(gomock.Any(), gomock.Any(), recorded).
+		DoAndReturn(func(_ context.Context, arg db.TransferBatchTxParam, batch db.TransferBatchTxResult) (db.TransferBatchTxResult, error) {
+			require.Equal(t, db.TransferBatchModeBestEffort, arg.Mode)
+			settled <- batch
+			return batch, nil
+		})
+
+	result, err := newTestTransferUseCase(store).CreateTransferBatch(context.Background(), &token.Payload{Username: "alice"},
+		entities.CreateTransferBatchRequest{
+			FromAccountID: 1,
+			Mode:          db.TransferBatchModeBestEffort,
+			Items:         []entities.TransferBatchItemRequest{{ToAccountID: 2, Amount: 100}},
+		})
+	require.NoError(t, err)
+	require.Equal(t, db.TransferBatchStatusProcessing, result.Batch.Status)
+
+	select {
+	case batch := <-settled:
+		require.Equal(t, recorded.Batch.ID, batch.Batch.ID)
+	case <-time.After(time.Second):
+		t.Fatal("batch wasn't settled")
+	}
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"github.com/dhiemaz/bank-api/utils/limit"
+)
+
+// checkLimits : check that a transfer of amount from an account stays within the limits of the tier of its owner.
+// pending is the usage of transfers that aren't recorded yet, like the earlier items of a batch. This is only a fast
+// path, the limits are checked again while the account is locked.
+func (transfer *UseCase) checkLimits(ctx context.Context, from *db.Account, amount int64, pending limit.Usage) error {
+	if transfer.limits == nil {
+		return nil
+	}
+
+	owner, err := transfer.db.GetUser(ctx, from.Owner)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "check transfer limits", "from_account": from.ID}).
+			Errorf("failed get owner [%s] of from_account [%d], err : %v", from.Owner, from.ID, err)
+
+		return err
+	}
+
+	limits, ok := transfer.limits.Lookup(owner.Tier, from.Currency)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	usage, err := AccountTransferUsage(ctx, transfer.db, from.ID, now)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "check transfer limits", "from_account": from.ID}).
+			Errorf("failed get transfer usage of from_account [%d], err : %v", from.ID, err)
+
+		return err
+	}
+
+	usage.DailyTotal += pending.DailyTotal
+	usage.MonthlyTotal += pending.MonthlyTotal
+	usage.HourlyCount += pending.HourlyCount
+
+	return limits.Check(usage, amount, limit.WindowsAt(now))
+}
+
+// AccountTransferUsage : what an account sent or holds for a payee in the limit windows around now, reversals don't
+// count
+func AccountTransferUsage(ctx context.Context, querier db.Querier, accountID int64, now time.Time) (limit.Usage, error) {
+	windows := limit.WindowsAt(now)
+
+	row, err := querier.GetTransferUsage(ctx, db.GetTransferUsageParams{
+		DayStart:   windows.DayStart,
+		HourStart:  windows.HourStart,
+		AccountID:  accountID,
+		MonthStart: windows.MonthStart,
+	})
+	if err != nil {
+		return limit.Usage{}, err
+	}
+
+	return limit.Usage{
+		DailyTotal:   row.DailyTotal,
+		MonthlyTotal: row.MonthlyTotal,
+		HourlyCount:  row.HourlyCount,
+		HourlyOldest: row.HourlyOldest.Time,
+	}, nil
+}
@@ -1,98 +1,191 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"github.com/dhiemaz/bank-api/domain/account/usecase"
+	audit "github.com/dhiemaz/bank-api/domain/audit/usecase"
 	"github.com/dhiemaz/bank-api/entities"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
 	"github.com/dhiemaz/bank-api/infrastructure/logger"
-	"github.com/dhiemaz/bank-api/middlewares"
 	"github.com/dhiemaz/bank-api/utils"
 	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
 	"github.com/dhiemaz/bank-api/utils/token"
-	"github.com/gin-gonic/gin"
-	"log"
+	"github.com/google/uuid"
 )
 
+const maxIdempotencyKeyLength = 255
+
 type TransferUseCase interface {
-	ValidateTransfer(ctx *gin.Context, fromAccount, toAccount int64) (from *db.Account, to *db.Account, err error)
-	CreateTransfer(ctx *gin.Context, request entities.CreateTransferRequest) (*db.TransferTxResult, error)
-	GetListTransfer(ctx *gin.Context, request entities.GetTransferRequest, pagination *utils.PaginationQuery) ([]db.Transfer, error)
+	ValidateTransfer(ctx context.Context, caller *token.Payload, fromAccount, toAccount, amount int64) (from *db.Account, to *db.Account, err error)
+	CreateTransfer(ctx context.Context, caller *token.Payload, request entities.CreateTransferRequest) (*db.TransferTxResult, error)
+	GetListTransfer(ctx context.Context, caller *token.Payload, request entities.GetTransferRequest, filter entities.ListTransfersFilter, pagination *utils.PaginationQuery) ([]db.Transfer, string, error)
+	ReverseTransfer(ctx context.Context, caller *token.Payload, request entities.ReverseTransferRequest) (*db.ReverseTransferTxResult, error)
+	CreateTransferBatch(ctx context.Context, caller *token.Payload, request entities.CreateTransferBatchRequest) (*db.TransferBatchTxResult, error)
+	GetTransferBatch(ctx context.Context, caller *token.Payload, id int64) (*db.TransferBatchTxResult, error)
+	GetTransferBatches(ctx context.Context, caller *token.Payload, pagination *utils.PaginationQuery) ([]db.TransferBatch, string, error)
+	PreviewFee(ctx context.Context, caller *token.Payload, request entities.PreviewFeeRequest) (*fee.Breakdown, error)
 }
 
 type UseCase struct {
-	account usecase.AccountUseCase
-	db      db.Store
+	account         usecase.AccountUseCase
+	db              db.Store
+	fxHouseAccounts map[string]int64
+	// frozenAcceptsCredits lets transfers pay into frozen accounts, debits from them are always refused
+	frozenAcceptsCredits bool
+	// fees charges transfers, nil charges nothing
+	fees *fee.Schedule
+	// limits bounds the transfers of every user tier, nil limits nothing
+	limits *limit.Policy
+	// auditor audits the transfers, reversals and batches, nil audits nothing
+	auditor *audit.Auditor
 }
 
-func NewTransferUseCase(db db.Store, account usecase.AccountUseCase) *UseCase {
-	return &UseCase{db: db, account: account}
+func NewTransferUseCase(db db.Store, account usecase.AccountUseCase, fxHouseAccounts map[string]int64, frozenAcceptsCredits bool, fees *fee.Schedule, limits *limit.Policy, auditor *audit.Auditor) *UseCase {
+	return &UseCase{db: db, account: account, fxHouseAccounts: fxHouseAccounts, frozenAcceptsCredits: frozenAcceptsCredits, fees: fees, limits: limits, auditor: auditor}
 }
 
-func (transfer *UseCase) ValidateTransfer(ctx *gin.Context, fromAccount, toAccount int64) (from *db.Account, to *db.Account, err error) {
-	if fromAccount == fromAccount {
-		return nil, nil, api_error.ErrSameAccountTransfer(fromAccount, toAccount)
-	}
+// ValidateTransfer : check both accounts and that the source account can cover the amount. The balance and status
+// checks here are only a fast path, TransferTx checks them again while holding a lock on the accounts. Currencies are checked by
+// TransferTx and FxTransferTx, accounts of different currencies need an fx quote. Frozen, dormant and closed accounts
+// can't be debited, frozen accounts are credited only when the policy allows it. The transfer has to stay within the
+// limits of the tier of the owner, aggregated over the transfers already sent from the account.
+func (transfer *UseCase) ValidateTransfer(ctx context.Context, caller *token.Payload, fromAccount, toAccount, amount int64) (from *db.Account, to *db.Account, err error) {
+	return transfer.validateTransfer(ctx, caller, fromAccount, toAccount, amount, limit.Usage{})
+}
 
-	from, err = transfer.account.IsValidAccount(ctx, fromAccount)
+func (transfer *UseCase) validateTransfer(ctx context.Context, caller *token.Payload, fromAccount, toAccount, amount int64, pending limit.Usage) (from *db.Account, to *db.Account, err error) {
+	from, to, err = transfer.checkAccounts(ctx, caller, fromAccount, toAccount)
 	if err != nil {
+		return nil, nil, err
+	}
 
+	if err = from.CheckDebit(); err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "validate transfer", "from_account": fromAccount, "to_account": toAccount}).
-			Errorf("failed validate from_account [%d], error : %v", fromAccount, err)
+			Errorf("failed from_account [%d] can't be debited, error : %v", fromAccount, err)
 
 		return nil, nil, err
 	}
 
-	to, err = transfer.account.IsValidAccount(ctx, toAccount)
-	if err != nil {
-
+	if err = to.CheckCredit(transfer.frozenAcceptsCredits); err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "validate transfer", "from_account": fromAccount, "to_account": toAccount}).
-			Errorf("failed validate to_account [%d], error : %v", toAccount, err)
+			Errorf("failed to_account [%d] can't be credited, error : %v", toAccount, err)
 
 		return nil, nil, err
 	}
 
-	if !isUserAccountOwner(ctx, from) {
+	if err = db.CheckSufficientFunds(*from, amount); err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "validate transfer", "from_account": fromAccount, "to_account": toAccount}).
+			Errorf("failed from_account [%d] can't cover the transfer, error : %v", fromAccount, err)
 
+		return nil, nil, err
+	}
+
+	if err = transfer.checkLimits(ctx, from, amount, pending); err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "validate transfer", "from_account": fromAccount, "to_account": toAccount}).
-			Errorf("from_account [%d] is not user account owner")
+			Errorf("failed from_account [%d] is over its transfer limits, error : %v", fromAccount, err)
 
-		return nil, nil, api_error.ErrNotAccountOwner
+		return nil, nil, err
 	}
 
-	if from.Currency != to.Currency {
+	return
+}
+
+// checkAccounts : both accounts of a transfer exist and the source account belongs to the caller
+func (transfer *UseCase) checkAccounts(ctx context.Context, caller *token.Payload, fromAccount, toAccount int64) (from *db.Account, to *db.Account, err error) {
+	if fromAccount == toAccount {
+		return nil, nil, api_error.ErrSameAccountTransfer
+	}
+
+	from, err = transfer.account.IsValidAccount(ctx, fromAccount)
+	if err != nil {
+
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "validate transfer", "from_account": fromAccount, "to_account": toAccount}).
-			Errorf("currency must be the same")
+			Errorf("failed validate from_account [%d], error : %v", fromAccount, err)
 
-		return nil, nil, api_error.ErrCurrencyMismatch(from.Currency, to.Currency)
+		return nil, nil, err
 	}
 
-	if to.IsDeleted {
+	to, err = transfer.account.IsValidAccount(ctx, toAccount)
+	if err != nil {
 
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "validate transfer", "from_account": fromAccount, "to_account": toAccount}).
-			Errorf("failed to_account [%d] is deleted", toAccount)
+			Errorf("failed validate to_account [%d], error : %v", toAccount, err)
 
-		return nil, nil, api_error.ErrAccountDeleted(from.ID)
+		return nil, nil, err
 	}
 
-	if from.IsDeleted {
+	if !isUserAccountOwner(caller, from) {
+
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "validate transfer", "from_account": fromAccount, "to_account": toAccount}).
-			Errorf("failed from_account [%d] is deleted", fromAccount)
+			Errorf("from_account [%d] is not user account owner", fromAccount)
 
-		return nil, nil, api_error.ErrAccountDeleted(to.ID)
+		return nil, nil, api_error.ErrNotAccountOwner
 	}
 
 	return
 }
 
-func (transfer *UseCase) CreateTransfer(ctx *gin.Context, request entities.CreateTransferRequest) (*db.TransferTxResult, error) {
+// CreateTransfer : move money between two accounts, a request carrying an idempotency key is executed at most once.
+// A request carrying an fx quote moves money between accounts of different currencies at the rate of the quote.
+// The fee of the transfer is charged to the source account on top of the amount. The transfer is validated first,
+// except for the statuses, funds and limits of a request carrying an idempotency key: a retry of a transfer that went
+// through has to be replayed even when the first attempt spent the balance or the limits. TransferTx and FxTransferTx
+// claim the key before checking them under the account lock, so a new key is checked all the same.
+func (transfer *UseCase) CreateTransfer(ctx context.Context, caller *token.Payload, request entities.CreateTransferRequest) (*db.TransferTxResult, error) {
+	if len(request.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, api_error.ErrInvalidIdempotencyKey
+	}
+
+	var from *db.Account
+	var err error
+	if request.IdempotencyKey != "" {
+		from, _, err = transfer.checkAccounts(ctx, caller, request.FromAccountID, request.ToAccountID)
+	} else {
+		from, _, err = transfer.ValidateTransfer(ctx, caller, request.FromAccountID, request.ToAccountID, request.Amount)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	transferType := fee.TypeTransfer
+	if request.QuoteID != "" {
+		transferType = fee.TypeFx
+	}
+
 	arg := db.TransferTxParam{
-		FromAccountID: request.FromAccountID,
-		ToAccountID:   request.ToAccountID,
-		Amount:        request.Amount,
+		FromAccountID:        request.FromAccountID,
+		ToAccountID:          request.ToAccountID,
+		Amount:               request.Amount,
+		Username:             caller.Username,
+		Fee:                  transfer.fees.Quote(from.Currency, transferType, request.Amount),
+		FrozenAcceptsCredits: transfer.frozenAcceptsCredits,
+		Limits:               transfer.limits,
+		Audit:                transfer.auditor.CreateTransfer(caller),
+		IdempotencyKey:       request.IdempotencyKey,
+	}
+
+	var result db.TransferTxResult
+
+	if request.QuoteID != "" {
+		quoteID, parseErr := uuid.Parse(request.QuoteID)
+		if parseErr != nil {
+			return nil, api_error.ErrFxQuoteNotFound
+		}
+
+		result, err = transfer.db.FxTransferTx(ctx, db.FxTransferTxParam{
+			TransferTxParam: arg,
+			QuoteID:         quoteID,
+			HouseAccounts:   transfer.fxHouseAccounts,
+		})
+	} else {
+		result, err = transfer.db.TransferTx(ctx, arg)
 	}
 
-	result, err := transfer.db.TransferTx(ctx, arg)
 	if err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "create transfer", "payload": request}).
 			Errorf("failed create transfer, err : %v", err)
@@ -100,42 +193,165 @@ func (transfer *UseCase) CreateTransfer(ctx *gin.Context, request entities.Creat
 		return nil, err
 	}
 
+	if result.Replayed {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "create transfer", "payload": request}).
+			Infof("replayed transfer [%d] for idempotency key %s", result.Transfer.ID, request.IdempotencyKey)
+	}
+
 	return &result, nil
 }
 
-func (transfer *UseCase) GetListTransfer(ctx *gin.Context, request entities.GetTransferRequest, pagination *utils.PaginationQuery) ([]db.Transfer, error) {
+// GetListTransfer : one page of the transfers of an account, latest first, and the cursor of the next page
+func (transfer *UseCase) GetListTransfer(ctx context.Context, caller *token.Payload, request entities.GetTransferRequest, filter entities.ListTransfersFilter, pagination *utils.PaginationQuery) ([]db.Transfer, string, error) {
 	account, err := transfer.account.IsValidAccount(ctx, request.AccountID)
 	if err != nil {
 
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer list data", "payload": request}).
 			Errorf("failed get transfer list, err : %v", err)
 
-		return nil, errors.New("invalid account id")
+		return nil, "", err
 	}
 
-	if !isUserAccountOwner(ctx, account) {
+	if !isUserAccountOwner(caller, account) {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer list data", "payload": request}).
 			Errorf("failed get transfer list, account doesn't belong to authenticated user")
 
+		return nil, "", api_error.ErrNotAccountOwner
+	}
+
+	transfersData, nextCursor, err := ListAccountTransfers(ctx, transfer.db, request.AccountID, filter, pagination)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer list data", "payload": request}).
+			Errorf("failed get transfer list, err : %v", err)
+
+		return nil, "", err
+	}
+
+	return transfersData, nextCursor, nil
+}
+
+// ListAccountTransfers : one page of the transfers of an account matching filter, latest first, without checking who
+// owns the account
+func ListAccountTransfers(ctx context.Context, querier db.Querier, accountID int64, filter entities.ListTransfersFilter, pagination *utils.PaginationQuery) ([]db.Transfer, string, error) {
+	arg := db.ListTransfersParams{
+		AccountID: accountID,
+		Direction: filter.Direction,
+		PageSize:  pagination.FetchSize(),
+	}
+	arg.AfterCreatedAt, arg.AfterID = pagination.AfterParams()
+
+	if filter.CounterpartyAccountID != nil {
+		arg.CounterpartyAccountID = sql.NullInt64{Int64: *filter.CounterpartyAccountID, Valid: true}
+	}
+
+	if filter.MinAmount != nil {
+		arg.MinAmount = sql.NullInt64{Int64: *filter.MinAmount, Valid: true}
+	}
+
+	if filter.MaxAmount != nil {
+		arg.MaxAmount = sql.NullInt64{Int64: *filter.MaxAmount, Valid: true}
+	}
+
+	if filter.From != nil {
+		arg.FromTime = sql.NullTime{Time: *filter.From, Valid: true}
+	}
+
+	if filter.To != nil {
+		arg.ToTime = sql.NullTime{Time: *filter.To, Valid: true}
+	}
+
+	transfersData, err := querier.ListTransfers(ctx, arg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if pagination.HasNextPage(len(transfersData)) {
+		transfersData = transfersData[:pagination.Limit]
+		last := transfersData[len(transfersData)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return transfersData, nextCursor, nil
+}
+
+// ReverseTransfer : send money of a transfer back to its sender, only the owner of the receiving account may do it.
+// A zero amount reverses what is left of the transfer.
+func (transfer *UseCase) ReverseTransfer(ctx context.Context, caller *token.Payload, request entities.ReverseTransferRequest) (*db.ReverseTransferTxResult, error) {
+	original, err := transfer.db.GetTransfer(ctx, request.TransferID)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "reverse transfer", "payload": request}).
+			Errorf("failed get transfer [%d], err : %v", request.TransferID, err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrTransferNotFound
+		}
+
+		return nil, err
+	}
+
+	account, err := transfer.account.IsValidAccount(ctx, original.ToAccountID)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "reverse transfer", "payload": request}).
+			Errorf("failed validate to_account [%d], err : %v", original.ToAccountID, err)
+
+		return nil, err
+	}
+
+	if !isUserAccountOwner(caller, account) {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "reverse transfer", "payload": request}).
+			Errorf("failed reverse transfer, to_account [%d] doesn't belong to authenticated user", original.ToAccountID)
+
 		return nil, api_error.ErrNotAccountOwner
 	}
 
-	transfersData, err := transfer.db.ListTransfers(ctx, db.ListTransfersParams{
-		AccountID: request.AccountID, PageSize: pagination.Limit, PageID: (pagination.Offset - 1) * pagination.Offset,
+	result, err := transfer.db.ReverseTransfer(ctx, db.ReverseTransferTxParam{
+		TransferID:           request.TransferID,
+		Amount:               request.Amount,
+		Reason:               request.Reason,
+		InitiatedBy:          caller.Username,
+		FrozenAcceptsCredits: transfer.frozenAcceptsCredits,
+		Audit:                transfer.auditor.ReverseTransfer(caller, original),
 	})
 
 	if err != nil {
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "get transfer list data", "payload": request}).
-			Errorf("failed get transfer list, err : %v", err)
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "reverse transfer", "payload": request}).
+			Errorf("failed reverse transfer, err : %v", err)
 
 		return nil, err
 	}
 
-	return transfersData, nil
+	return &result, nil
+}
+
+// PreviewFee : the fee a transfer would be charged, transfers between currencies are priced as fx transfers
+func (transfer *UseCase) PreviewFee(ctx context.Context, caller *token.Payload, request entities.PreviewFeeRequest) (*fee.Breakdown, error) {
+	from, err := transfer.account.IsValidAccount(ctx, request.FromAccountID)
+	if err != nil {
+		return nil, api_error.ErrAccountNotFound
+	}
+
+	if !isUserAccountOwner(caller, from) {
+		return nil, api_error.ErrNotAccountOwner
+	}
+
+	to, err := transfer.account.IsValidAccount(ctx, request.ToAccountID)
+	if err != nil {
+		return nil, api_error.ErrAccountNotFound
+	}
+
+	transferType := request.TransferType
+	switch {
+	case from.Currency != to.Currency:
+		transferType = fee.TypeFx
+	case transferType == "":
+		transferType = fee.TypeTransfer
+	}
+
+	breakdown := transfer.fees.Quote(from.Currency, transferType, request.Amount)
+	return &breakdown, nil
 }
 
-func isUserAccountOwner(ctx *gin.Context, account *db.Account) bool {
-	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
-	log.Println(payload.Username, account.Owner)
-	return payload.Username == account.Owner
+func isUserAccountOwner(caller *token.Payload, account *db.Account) bool {
+	return caller.Username == account.Owner
 }
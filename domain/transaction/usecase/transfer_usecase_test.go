@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	accountUsecase "github.com/dhiemaz/bank-api/domain/account/usecase"
+	"github.com/dhiemaz/bank-api/entities"
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransferUseCase(store db.Store) *UseCase {
+	return NewTransferUseCase(store, accountUsecase.NewAccountUseCase(store, nil, nil, 0, nil, nil), nil, false, nil, nil, nil)
+}
+
+// the first attempt spent the whole balance, the account can't cover the transfer anymore
+func expectSpentAccounts(store *mockdb.MockStore) {
+	store.EXPECT().GetAccount(gomock.Any(), int64(1)).Return(db.Account{ID: 1, Owner: "alice", Currency: "USD", Status: db.AccountStatusActive}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), int64(2)).Return(db.Account{ID: 2, Owner: "bob", Currency: "USD", Status: db.AccountStatusActive}, nil)
+}
+
+func TestCreateTransferReplaysIdempotentRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	expectSpentAccounts(store)
+
+	stored := db.TransferTxResult{Transfer: db.Transfer{ID: 7, FromAccountID: 1, ToAccountID: 2, Amount: 100}, Replayed: true}
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.TransferTxParam) (db.TransferTxResult, error) {
+			require.Equal(t, "retry-1", arg.IdempotencyKey)
+			require.Equal(t, "alice", arg.Username)
+			return stored, nil
+		})
+
+	result, err := newTestTransferUseCase(store).CreateTransfer(context.Background(), &token.Payload{Username: "alice"},
+		entities.CreateTransferRequest{FromAccountID: 1, ToAccountID: 2, Amount: 100, IdempotencyKey: "retry-1"})
+	require.NoError(t, err)
+	require.True(t, result.Replayed)
+	require.Equal(t, int64(7), result.Transfer.ID)
+}
+
+func TestCreateTransferChecksFundsWithoutKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	expectSpentAccounts(store)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := newTestTransferUseCase(store).CreateTransfer(context.Background(), &token.Payload{Username: "alice"},
+		entities.CreateTransferRequest{FromAccountID: 1, ToAccountID: 2, Amount: 100})
+	require.ErrorIs(t, err, api_error.ErrInsufficientFunds)
+}
@@ -41,7 +41,7 @@ func (user *Handler) LoginUser(ctx *gin.Context) {
 		return
 	}
 
-	response, err := user.Usecase.Login(ctx, req)
+	response, err := user.Usecase.Login(ctx, req, entities.ClientInfo{UserAgent: ctx.Request.UserAgent(), ClientIP: ctx.ClientIP()})
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, entities.Err(err))
 		return
@@ -70,8 +70,8 @@ func (user *Handler) Register(ctx *gin.Context) {
 
 	userData, err := user.Usecase.UserRegistration(ctx, request)
 	if err != nil {
-		if err.Error() == "unique_violation" {
-			ctx.JSON(http.StatusConflict, entities.Err(errors.New("username is already taken")))
+		if errors.Is(err, api_error.ErrUsernameTaken) {
+			ctx.JSON(http.StatusConflict, entities.Err(err))
 			return
 		}
 
@@ -124,9 +124,10 @@ func (user *Handler) UpdateUser(ctx *gin.Context) {
 	}
 
 	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
-	dbUser, err := user.Usecase.UpdateUser(ctx, payload.Username, request)
+	dbUser, err := user.Usecase.UpdateUser(ctx, payload, request)
 	if err != nil {
-		if errors.Is(err, api_error.ErrEmailSameAsOld) {
+		if errors.Is(err, api_error.ErrEmailSameAsOld) || errors.Is(err, api_error.ErrPasswordWrong) ||
+			errors.Is(err, api_error.ErrNothingToUpdate) {
 			ctx.JSON(http.StatusBadRequest, entities.Err(err))
 		} else {
 			ctx.JSON(http.StatusInternalServerError, entities.Err(err))
@@ -136,3 +137,25 @@ func (user *Handler) UpdateUser(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, entities.Success(utils.MapUserToResponse(dbUser)))
 }
+
+// GetLimits godoc
+//
+//	@Summary		Get the transfer limits of the current user
+//	@Description	Get the transfer limits of the tier of the current user for each of their accounts, with what was already sent and what is left
+//	@Tags			users
+//	@Produce		json
+//	@Success		200		{object}	response.JSON{data=userLimitsResponse}
+//	@Failure		500		{object}	response.JSON{}
+//	@Security		bearerAuth
+//	@Router			/users/limits [get]
+func (user *Handler) GetLimits(ctx *gin.Context) {
+	payload := ctx.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	limits, err := user.Usecase.GetLimits(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, entities.Err(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entities.Success(limits))
+}
@@ -1,38 +1,49 @@
 package usecase
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	auditUsecase "github.com/dhiemaz/bank-api/domain/audit/usecase"
+	transactionUsecase "github.com/dhiemaz/bank-api/domain/transaction/usecase"
 	"github.com/dhiemaz/bank-api/entities"
 	db "github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
 	"github.com/dhiemaz/bank-api/infrastructure/logger"
 	"github.com/dhiemaz/bank-api/utils"
 	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/limit"
+	"github.com/dhiemaz/bank-api/utils/rbac"
 	"github.com/dhiemaz/bank-api/utils/token"
-	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
+	"strings"
+	"time"
 )
 
 // UserUseCase :
 type UserUseCase interface {
-	Login(ctx *gin.Context, request entities.LoginUserRequest) (*entities.LoginUserResponse, error)
-	UserRegistration(ctx *gin.Context, request entities.CreateUserRequest) (*db.User, error)
-	GetUser(ctx *gin.Context, username string) (*db.User, error)
-	CheckUserExist(ctx *gin.Context, username string) (*db.User, error)
-	UpdateUser(ctx *gin.Context, username string, request entities.UpdateUserRequest) (*db.User, error)
+	Login(ctx context.Context, request entities.LoginUserRequest, client entities.ClientInfo) (*entities.LoginUserResponse, error)
+	UserRegistration(ctx context.Context, request entities.CreateUserRequest) (*db.User, error)
+	GetUser(ctx context.Context, username string) (*db.User, error)
+	CheckUserExist(ctx context.Context, username string) (*db.User, error)
+	UpdateUser(ctx context.Context, caller *token.Payload, request entities.UpdateUserRequest) (*db.User, error)
+	GetLimits(ctx context.Context, username string) (*entities.UserLimitsResponse, error)
 }
 
 type UseCase struct {
-	db  db.Querier
-	jwt token.JWTMaker
+	db    db.Store
+	token token.Maker
+	// limits are the transfer limits of every tier, nil limits nothing
+	limits *limit.Policy
+	// auditor audits the users registered and updated, nil audits nothing
+	auditor *auditUsecase.Auditor
 }
 
-func NewUserUseCase(db db.Querier) *UseCase {
-	return &UseCase{db: db}
+func NewUserUseCase(db db.Store, maker token.Maker, limits *limit.Policy, auditor *auditUsecase.Auditor) *UseCase {
+	return &UseCase{db: db, token: maker, limits: limits, auditor: auditor}
 }
 
-// Login : user login
-func (user *UseCase) Login(ctx *gin.Context, request entities.LoginUserRequest) (*entities.LoginUserResponse, error) {
+// Login : user login, the session opened is kept with the client it was opened from
+func (user *UseCase) Login(ctx context.Context, request entities.LoginUserRequest, client entities.ClientInfo) (*entities.LoginUserResponse, error) {
 	userData, err := user.CheckUserExist(ctx, request.Username)
 	if err != nil {
 
@@ -48,21 +59,11 @@ func (user *UseCase) Login(ctx *gin.Context, request entities.LoginUserRequest)
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "user login", "payload": request}).
 			Errorf("failed check hashed password login, err : %v", err)
 
-		return nil, err
-	}
-
-	// Generate New Access Token for User
-	accessToken, accessPayload, err := user.jwt.CreateToken(request.Username)
-	if err != nil {
-
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "user login", "payload": request}).
-			Errorf("failed create token, err : %v", err)
-
-		return nil, err
+		return nil, api_error.ErrIncorrectPassword
 	}
 
 	// Generate New Refresh Token for User
-	refreshToken, refreshPayload, err := user.jwt.CreateRefreshToken(request.Username)
+	refreshToken, refreshPayload, err := user.token.CreateRefreshToken(request.Username)
 	if err != nil {
 
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "user login", "payload": request}).
@@ -73,13 +74,31 @@ func (user *UseCase) Login(ctx *gin.Context, request entities.LoginUserRequest)
 
 	session, err := user.db.CreateSession(ctx, db.CreateSessionParams{
 		ID:           refreshPayload.ID,
+		FamilyID:     refreshPayload.ID,
 		Username:     request.Username,
 		RefreshToken: refreshToken,
-		UserAgent:    ctx.Request.UserAgent(),
-		ClientIp:     ctx.ClientIP(),
+		UserAgent:    client.UserAgent,
+		ClientIp:     client.ClientIP,
 		ExpiresAt:    refreshPayload.ExpireAt,
 	})
 
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "user login", "payload": request}).
+			Errorf("failed create session, err : %v", err)
+
+		return nil, err
+	}
+
+	// Generate New Access Token for the session
+	accessToken, accessPayload, err := user.token.CreateToken(request.Username, rbac.Role(userData.Role), session.ID)
+	if err != nil {
+
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "user login", "payload": request}).
+			Errorf("failed create token, err : %v", err)
+
+		return nil, err
+	}
+
 	response := entities.LoginUserResponse{
 		SessionID:             session.ID,
 		AccessToken:           accessToken,
@@ -93,7 +112,7 @@ func (user *UseCase) Login(ctx *gin.Context, request entities.LoginUserRequest)
 }
 
 // UserRegistration : register a new user
-func (user *UseCase) UserRegistration(ctx *gin.Context, request entities.CreateUserRequest) (*db.User, error) {
+func (user *UseCase) UserRegistration(ctx context.Context, request entities.CreateUserRequest) (*db.User, error) {
 	hashPassword, err := utils.GenerateHashPassword(request.Password)
 	if err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "user registration", "payload": request}).
@@ -102,11 +121,14 @@ func (user *UseCase) UserRegistration(ctx *gin.Context, request entities.CreateU
 		return nil, err
 	}
 
-	userData, err := user.db.CreateUser(ctx, db.CreateUserParams{
-		Username:       request.Username,
-		HashedPassword: hashPassword,
-		FullName:       request.FullName,
-		Email:          request.Email,
+	userData, err := user.db.CreateUserTx(ctx, db.CreateUserTxParam{
+		CreateUserParams: db.CreateUserParams{
+			Username:       request.Username,
+			HashedPassword: hashPassword,
+			FullName:       request.FullName,
+			Email:          request.Email,
+		},
+		Audit: user.auditor.RegisterUser(),
 	})
 
 	if err != nil {
@@ -118,7 +140,7 @@ func (user *UseCase) UserRegistration(ctx *gin.Context, request entities.CreateU
 		if errors.As(err, &pqErr) {
 			switch pqErr.Code.Name() {
 			case "unique_violation":
-				return nil, errors.New("unique violation")
+				return nil, api_error.ErrUsernameTaken
 			}
 		}
 		return nil, err
@@ -128,7 +150,7 @@ func (user *UseCase) UserRegistration(ctx *gin.Context, request entities.CreateU
 }
 
 // GetUser : get single user
-func (user *UseCase) GetUser(ctx *gin.Context, username string) (*db.User, error) {
+func (user *UseCase) GetUser(ctx context.Context, username string) (*db.User, error) {
 	userData, err := user.CheckUserExist(ctx, username)
 	if err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "get user", "username": username}).
@@ -140,9 +162,10 @@ func (user *UseCase) GetUser(ctx *gin.Context, username string) (*db.User, error
 	return userData, nil
 }
 
-// UpdateUser : update existing user data
-func (user *UseCase) UpdateUser(ctx *gin.Context, username string, request entities.UpdateUserRequest) (*db.User, error) {
-	var params db.UpdateUserParams
+// UpdateUser : update the full name, email or password of the caller, a new password needs the current one
+func (user *UseCase) UpdateUser(ctx context.Context, caller *token.Payload, request entities.UpdateUserRequest) (*db.User, error) {
+	username := caller.Username
+	params := db.UpdateUserParams{Username: username}
 
 	userData, err := user.CheckUserExist(ctx, username)
 	if err != nil {
@@ -152,50 +175,104 @@ func (user *UseCase) UpdateUser(ctx *gin.Context, username string, request entit
 		return nil, err
 	}
 
-	if request.Email == userData.Email {
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "update user", "username": username, "payload": request}).
-			Errorf("failed update user due to email is same")
+	if request.FullName != "" {
+		// redundant spaces between the names are dropped
+		params.FullName = sql.NullString{String: strings.Join(strings.Fields(request.FullName), " "), Valid: true}
+	}
+
+	if request.Email != "" {
+		if request.Email == userData.Email {
+			logger.WithFields(logger.Fields{"component": "usecase", "action": "update user", "username": username, "payload": request}).
+				Errorf("failed update user due to email is same")
 
-		return nil, api_error.ErrEmailSameAsOld
+			return nil, api_error.ErrEmailSameAsOld
+		}
+
+		params.Email = sql.NullString{String: request.Email, Valid: true}
 	}
 
-	if err := utils.CheckHashedPassword(request.OldPassword, userData.HashedPassword); err != nil {
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "update user", "username": username, "payload": request}).
-			Errorf("failed update user due to password is not valid")
+	if request.NewPassword != "" {
+		if err := utils.CheckHashedPassword(userData.HashedPassword, request.OldPassword); err != nil {
+			logger.WithFields(logger.Fields{"component": "usecase", "action": "update user", "username": username}).
+				Errorf("failed update user due to password is not valid")
 
-		return nil, api_error.ErrPasswordWrong
+			return nil, api_error.ErrPasswordWrong
+		}
+
+		hashedPassword, err := utils.GenerateHashPassword(request.NewPassword)
+		if err != nil {
+			logger.WithFields(logger.Fields{"component": "usecase", "action": "update user", "username": username}).
+				Errorf("failed generate hash password, err : %v", err)
+
+			return nil, err
+		}
+
+		params.HashedPassword = sql.NullString{String: hashedPassword, Valid: true}
+	}
+
+	if !params.FullName.Valid && !params.Email.Valid && !params.HashedPassword.Valid {
+		return nil, api_error.ErrNothingToUpdate
 	}
 
-	hashedPassword, err := utils.GenerateHashPassword(request.NewPassword)
+	dbUser, err := user.db.UpdateUserTx(ctx, db.UpdateUserTxParam{
+		UpdateUserParams: params,
+		Audit:            user.auditor.UpdateUser(caller, *userData),
+	})
 	if err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "update user", "username": username, "payload": request}).
-			Errorf("failed generate hash password, err : %v", err)
+			Errorf("failed update user, err : %v", err)
 
 		return nil, err
 	}
 
-	params.HashedPassword = sql.NullString{String: hashedPassword, Valid: true}
+	return &dbUser, nil
+}
 
-	dbUser, err := user.db.UpdateUser(ctx, params)
+// GetLimits : the transfer limits of the tier of a user for each of their accounts, with what was already sent and
+// what is left in the current windows
+func (user *UseCase) GetLimits(ctx context.Context, username string) (*entities.UserLimitsResponse, error) {
+	userData, err := user.CheckUserExist(ctx, username)
 	if err != nil {
-		logger.WithFields(logger.Fields{"component": "usecase", "action": "update user", "username": username, "payload": request}).
-			Errorf("failed update user, err : %v", err)
+		return nil, err
+	}
+
+	accounts, err := user.db.GetAccounts(ctx, username)
+	if err != nil {
+		logger.WithFields(logger.Fields{"component": "usecase", "action": "get limits", "username": username}).
+			Errorf("failed get accounts, err : %v", err)
 
 		return nil, err
 	}
 
-	return &dbUser, nil
+	now := time.Now()
+	windows := limit.WindowsAt(now)
+	response := entities.UserLimitsResponse{Tier: userData.Tier, Accounts: []entities.AccountLimitsResponse{}}
+
+	for _, account := range accounts {
+		usage, err := transactionUsecase.AccountTransferUsage(ctx, user.db, account.ID, now)
+		if err != nil {
+			logger.WithFields(logger.Fields{"component": "usecase", "action": "get limits", "username": username}).
+				Errorf("failed get transfer usage of account [%d], err : %v", account.ID, err)
+
+			return nil, err
+		}
+
+		limits, limited := user.limits.Lookup(userData.Tier, account.Currency)
+		response.Accounts = append(response.Accounts, utils.MapAccountLimitsToResponse(account, limits, limited, usage, windows))
+	}
+
+	return &response, nil
 }
 
-// CheckUserExist : check if user is exist in database
-func (user *UseCase) CheckUserExist(ctx *gin.Context, username string) (*db.User, error) {
+// CheckUserExist : check if user is exist in database, a missing user is ErrUserNotFound
+func (user *UseCase) CheckUserExist(ctx context.Context, username string) (*db.User, error) {
 	userData, err := user.db.GetUser(ctx, username)
 	if err != nil {
 		logger.WithFields(logger.Fields{"component": "usecase", "action": "check user exist", "username": username}).
 			Errorf("failed check if user exist, err : %v", err)
 
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, err
+			return nil, api_error.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -1,7 +1,11 @@
 package entities
 
+import "time"
+
 type CreateAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	// Product is one of the configured account products, checking by default
+	Product string `json:"product" binding:"omitempty,max=50"`
 }
 
 type CreateUserRequest struct {
@@ -12,17 +16,26 @@ type CreateUserRequest struct {
 	PasswordConfirm string `json:"password_confirm" binding:"required,eqfield=Password"`
 }
 
+// UpdateUserRequest : only the fields that are set are changed, a new password needs the current one
 type UpdateUserRequest struct {
-	FullName    string `json:"full_name" binding:"alpha,required"`
-	Email       string `json:"email" binding:"email"`
-	OldPassword string `json:"old_password" binding:"min=6,max=16,required_with=NewPassword"`
-	NewPassword string `json:"new_password" binding:"min=6,max=16,require"`
+	FullName    string `json:"full_name" binding:"omitempty,max=255"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	OldPassword string `json:"old_password" binding:"required_with=NewPassword"`
+	NewPassword string `json:"new_password" binding:"omitempty,min=6,max=16"`
 }
 
 type CreateTransferRequest struct {
-	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64 `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64 `json:"amount" binding:"required,gte=1"`
+	FromAccountID  int64  `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID    int64  `json:"to_account_id" binding:"required,min=1"`
+	Amount         int64  `json:"amount" binding:"required,gte=1"`
+	QuoteID        string `json:"quote_id" binding:"omitempty,uuid"`
+	IdempotencyKey string `json:"-"`
+}
+
+// ClientInfo : where a request comes from, kept on the sessions it opens
+type ClientInfo struct {
+	UserAgent string
+	ClientIP  string
 }
 
 type LoginUserRequest struct {
@@ -38,10 +51,26 @@ type DeleteAccountRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
+// CloseAccountRequest : a positive balance is swept to SweepToAccountID, an account of another currency needs a
+// quote of the currencies in SweepQuoteID
+type CloseAccountRequest struct {
+	AccountID        int64  `json:"-"`
+	SweepToAccountID int64  `form:"sweep_to_account_id" binding:"omitempty,min=1"`
+	SweepQuoteID     string `form:"sweep_quote_id" binding:"omitempty,uuid"`
+	Reason           string `form:"reason" binding:"max=255"`
+}
+
 type RestoreAccountRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
+// WatchAccountRequest : LastEventID resumes the events after the one of that id, reconnecting event sources send it
+// in the Last-Event-ID header instead
+type WatchAccountRequest struct {
+	ID          int64 `uri:"id" binding:"required,min=1"`
+	LastEventID int64 `form:"last_event_id" binding:"min=0"`
+}
+
 type RenewAccessTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
@@ -49,3 +78,144 @@ type RenewAccessTokenRequest struct {
 type GetTransferRequest struct {
 	AccountID int64 `uri:"id" binding:"required,min=1"`
 }
+
+type TransferURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type ReverseTransferRequest struct {
+	TransferID int64  `json:"-"`
+	Amount     int64  `json:"amount" binding:"gte=0"`
+	Reason     string `json:"reason" binding:"required,max=255"`
+}
+
+type CreateScheduledTransferRequest struct {
+	FromAccountID       int64      `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID         int64      `json:"to_account_id" binding:"required,min=1"`
+	Amount              int64      `json:"amount" binding:"required,gte=1"`
+	Frequency           string     `json:"frequency" binding:"required,oneof=once daily weekly monthly"`
+	StartAt             time.Time  `json:"start_at" binding:"required"`
+	EndAt               *time.Time `json:"end_at"`
+	OnInsufficientFunds string     `json:"on_insufficient_funds" binding:"omitempty,oneof=skip retry"`
+	MaxRetries          *int32     `json:"max_retries" binding:"omitempty,min=0,max=10"`
+}
+
+type UpdateScheduledTransferRequest struct {
+	ID     int64      `json:"-"`
+	Amount *int64     `json:"amount" binding:"omitempty,gte=1"`
+	EndAt  *time.Time `json:"end_at"`
+	Status *string    `json:"status" binding:"omitempty,oneof=active paused"`
+}
+
+type GetScheduledTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type CreateFxQuoteRequest struct {
+	FromCurrency string `json:"from_currency" binding:"required,currency"`
+	ToCurrency   string `json:"to_currency" binding:"required,currency,nefield=FromCurrency"`
+	Amount       int64  `json:"amount" binding:"omitempty,gte=1"`
+}
+
+type GetStatementRequest struct {
+	AccountID int64     `form:"-"`
+	From      time.Time `form:"from" binding:"required"`
+	To        time.Time `form:"to" binding:"required"`
+}
+
+type ListTransfersFilter struct {
+	Direction             string     `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+	MinAmount             *int64     `form:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount             *int64     `form:"max_amount" binding:"omitempty,gte=0"`
+	From                  *time.Time `form:"from"`
+	To                    *time.Time `form:"to"`
+	CounterpartyAccountID *int64     `form:"counterparty_account_id" binding:"omitempty,min=1"`
+}
+
+type SessionURIRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type SearchUsersRequest struct {
+	// Query matches part of the username, email or full name
+	Query  string `form:"q"`
+	Role   string `form:"role" binding:"omitempty,oneof=customer support admin auditor"`
+	Cursor string `form:"cursor"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type UsernameURIRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type SetUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=customer support admin auditor"`
+}
+
+type SetUserTierRequest struct {
+	Tier string `json:"tier" binding:"required,max=50"`
+}
+
+type FreezeAccountRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// SetAccountStatusRequest : SweepToAccountID and SweepQuoteID are only used when closing an account with a balance
+type SetAccountStatusRequest struct {
+	Status           string `json:"status" binding:"required,oneof=active frozen dormant closed"`
+	Reason           string `json:"reason" binding:"required,max=500"`
+	SweepToAccountID int64  `json:"sweep_to_account_id" binding:"omitempty,min=1"`
+	SweepQuoteID     string `json:"sweep_quote_id" binding:"omitempty,uuid"`
+}
+
+type ListAdminActionsRequest struct {
+	Actor string `form:"actor"`
+}
+
+// CreateHoldRequest : ExpiresAt is optional, the hold expires after the default hold duration when it isn't set
+type CreateHoldRequest struct {
+	FromAccountID int64      `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64      `json:"to_account_id" binding:"required,min=1"`
+	Amount        int64      `json:"amount" binding:"required,gt=0"`
+	Description   string     `json:"description" binding:"max=255"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+type HoldURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// CaptureHoldRequest : zero captures the whole hold
+type CaptureHoldRequest struct {
+	HoldID int64 `json:"-"`
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+type ListHoldsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=active captured released expired"`
+}
+
+// CreateTransferBatchRequest : the items come from the JSON body, or from a CSV file uploaded with the other fields
+// as a multipart form
+type CreateTransferBatchRequest struct {
+	FromAccountID int64                      `json:"from_account_id" form:"from_account_id" binding:"required,min=1"`
+	Mode          string                     `json:"mode" form:"mode" binding:"required,oneof=atomic best_effort"`
+	Items         []TransferBatchItemRequest `json:"items" form:"-" binding:"dive"`
+}
+
+type TransferBatchItemRequest struct {
+	ToAccountID int64 `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"required,gte=1"`
+}
+
+type TransferBatchURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// PreviewFeeRequest : the transfer type is fx whenever the accounts have different currencies
+type PreviewFeeRequest struct {
+	FromAccountID int64  `form:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64  `form:"to_account_id" binding:"required,min=1"`
+	Amount        int64  `form:"amount" binding:"required,gte=1"`
+	TransferType  string `form:"transfer_type" binding:"omitempty,oneof=transfer scheduled batch"`
+}
@@ -1,16 +1,52 @@
 package entities
 
 import (
+	"encoding/json"
+	"errors"
 	db "github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
 	"github.com/google/uuid"
 	"time"
 )
 
 type AccountResponse struct {
-	ID        int64     `json:"id"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
-	CreatedAt time.Time `json:"created_at"`
+	ID      int64 `json:"id"`
+	Balance int64 `json:"balance"`
+	// BalanceDecimal is the balance in major units, e.g. "10.50" for 1050 USD cents
+	BalanceDecimal string `json:"balance_decimal"`
+	// AvailableBalance is the balance less the money on hold
+	AvailableBalance        int64     `json:"available_balance"`
+	AvailableBalanceDecimal string    `json:"available_balance_decimal"`
+	Currency                string    `json:"currency"`
+	Product                 string    `json:"product"`
+	Status                  string    `json:"status"`
+	StatusReason            string    `json:"status_reason,omitempty"`
+	CreatedAt               time.Time `json:"created_at"`
+}
+
+type CloseAccountResponse struct {
+	Account AccountResponse `json:"account"`
+	// Sweep is the transfer of the remaining balance, only set when there was a balance to sweep
+	Sweep *TransferResponse `json:"sweep,omitempty"`
+}
+
+type AccountStatusChangeResponse struct {
+	ID              int64     `json:"id"`
+	FromStatus      string    `json:"from_status"`
+	ToStatus        string    `json:"to_status"`
+	Reason          string    `json:"reason"`
+	ChangedBy       string    `json:"changed_by"`
+	SweepTransferID *int64    `json:"sweep_transfer_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AdminAccountResponse is the back-office view of an account, it holds the fields a customer doesn't see
+type AdminAccountResponse struct {
+	AccountResponse
+	Owner           string    `json:"owner"`
+	OverdraftLimit  int64     `json:"overdraft_limit"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
 }
 
 type TransferResponse struct {
@@ -19,7 +55,121 @@ type TransferResponse struct {
 	FromEntry   db.Entry   `json:"from_entry"`
 	ToAccountID int64      `json:"to_account_id"`
 	Amount      int64      `json:"amount"`
-	CreatedAt   time.Time  `json:"created_at"`
+	// AmountDecimal is only set when the currency of the source account is known
+	AmountDecimal string `json:"amount_decimal,omitempty"`
+	// ToAmount and ExchangeRate are only set for transfers between currencies
+	ToAmount        *int64 `json:"to_amount,omitempty"`
+	ToAmountDecimal string `json:"to_amount_decimal,omitempty"`
+	ExchangeRate    string `json:"exchange_rate,omitempty"`
+	// Fee is charged to the source account on top of Amount, FeeBreakdown is only set when the transfer is created
+	Fee          int64          `json:"fee,omitempty"`
+	FeeDecimal   string         `json:"fee_decimal,omitempty"`
+	FeeBreakdown *fee.Breakdown `json:"fee_breakdown,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type TransferReversalResponse struct {
+	ID                 int64            `json:"id"`
+	TransferID         int64            `json:"transfer_id"`
+	ReversalTransferID int64            `json:"reversal_transfer_id"`
+	Amount             int64            `json:"amount"`
+	RemainingAmount    int64            `json:"remaining_amount"`
+	Reason             string           `json:"reason"`
+	Transfer           TransferResponse `json:"transfer"`
+	CreatedAt          time.Time        `json:"created_at"`
+}
+
+type ScheduledTransferResponse struct {
+	ID                  int64      `json:"id"`
+	FromAccountID       int64      `json:"from_account_id"`
+	ToAccountID         int64      `json:"to_account_id"`
+	Amount              int64      `json:"amount"`
+	Frequency           string     `json:"frequency"`
+	StartAt             time.Time  `json:"start_at"`
+	EndAt               *time.Time `json:"end_at,omitempty"`
+	NextRunAt           time.Time  `json:"next_run_at"`
+	Occurrences         int32      `json:"occurrences"`
+	Status              string     `json:"status"`
+	OnInsufficientFunds string     `json:"on_insufficient_funds"`
+	MaxRetries          int32      `json:"max_retries"`
+	RetryCount          int32      `json:"retry_count"`
+	LastRunAt           *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type ScheduledTransferRunResponse struct {
+	ID         int64     `json:"id"`
+	Occurrence int32     `json:"occurrence"`
+	Attempt    int32     `json:"attempt"`
+	Status     string    `json:"status"`
+	TransferID *int64    `json:"transfer_id,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type FxRateResponse struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	Source        string    `json:"source"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type FxQuoteResponse struct {
+	ID              uuid.UUID `json:"id"`
+	FromCurrency    string    `json:"from_currency"`
+	ToCurrency      string    `json:"to_currency"`
+	Rate            string    `json:"rate"`
+	Amount          int64     `json:"amount,omitempty"`
+	AmountDecimal   string    `json:"amount_decimal,omitempty"`
+	ToAmount        int64     `json:"to_amount,omitempty"`
+	ToAmountDecimal string    `json:"to_amount_decimal,omitempty"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+type StatementResponse struct {
+	AccountID             int64                    `json:"account_id"`
+	Currency              string                   `json:"currency"`
+	From                  time.Time                `json:"from"`
+	To                    time.Time                `json:"to"`
+	OpeningBalance        int64                    `json:"opening_balance"`
+	OpeningBalanceDecimal string                   `json:"opening_balance_decimal"`
+	ClosingBalance        int64                    `json:"closing_balance"`
+	ClosingBalanceDecimal string                   `json:"closing_balance_decimal"`
+	Entries               []StatementEntryResponse `json:"entries"`
+	// NextCursor is only set when there are more entries in the period
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type StatementEntryResponse struct {
+	ID             int64  `json:"id"`
+	Amount         int64  `json:"amount"`
+	AmountDecimal  string `json:"amount_decimal"`
+	Balance        int64  `json:"balance"`
+	BalanceDecimal string `json:"balance_decimal"`
+	TransferID     *int64 `json:"transfer_id,omitempty"`
+	// Counterparty is the other account of the transfer that posted the entry
+	Counterparty *CounterpartyResponse `json:"counterparty,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+type CounterpartyResponse struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+}
+
+// AccountEventResponse is an entry posted to a watched account, ID is the id of the entry and orders the events of
+// an account, it's the id to resume watching after
+type AccountEventResponse struct {
+	ID            int64  `json:"id"`
+	AccountID     int64  `json:"account_id"`
+	Amount        int64  `json:"amount"`
+	AmountDecimal string `json:"amount_decimal"`
+	// Balance is the balance of the account after the entry
+	Balance        int64     `json:"balance"`
+	BalanceDecimal string    `json:"balance_decimal"`
+	TransferID     *int64    `json:"transfer_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type UserResponse struct {
@@ -28,6 +178,23 @@ type UserResponse struct {
 	Email             string    `json:"email"`
 	CreatedAt         time.Time `json:"created_at"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
+	Role              string    `json:"role"`
+	Tier              string    `json:"tier"`
+}
+
+type AdminUserResponse struct {
+	UserResponse
+	Accounts []AdminAccountResponse `json:"accounts"`
+}
+
+type AdminActionResponse struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Role      string          `json:"role"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Details   json.RawMessage `json:"details" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
 }
 
 type LoginUserResponse struct {
@@ -39,25 +206,137 @@ type LoginUserResponse struct {
 	User                  UserResponse `json:"user"`
 }
 
+type SessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	ClientIP  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Current is set on the session of the access token used for the request
+	Current bool `json:"current"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
 type RenewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_expires_at"`
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_expires_at"`
 }
 
 type JSON struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty" swaggerignore:"true"`
 	Error   *Error      `json:"error,omitempty" swaggerignore:"true"`
+	// NextCursor is set by list endpoints when there is a next page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type Error struct {
 	Error string `json:"error,omitempty"`
+	// Details are set for errors clients can act on, like the allowance left by an exceeded transfer limit
+	Details interface{} `json:"details,omitempty"`
+}
+
+// detailedError is implemented by errors carrying more than their message
+type detailedError interface {
+	Details() interface{}
 }
 
 func Success(data interface{}) JSON {
 	return JSON{Success: true, Data: data}
 }
 
+// SuccessPage wraps one page of a list endpoint, nextCursor is empty on the last page
+func SuccessPage(data interface{}, nextCursor string) JSON {
+	return JSON{Success: true, Data: data, NextCursor: nextCursor}
+}
+
 func Err(err error) JSON {
-	return JSON{Success: false, Error: &Error{Error: err.Error()}}
+	response := &Error{Error: err.Error()}
+
+	var detailed detailedError
+	if errors.As(err, &detailed) {
+		response.Details = detailed.Details()
+	}
+
+	return JSON{Success: false, Error: response}
+}
+
+type HoldResponse struct {
+	ID             int64     `json:"id"`
+	FromAccountID  int64     `json:"from_account_id"`
+	ToAccountID    int64     `json:"to_account_id"`
+	Amount         int64     `json:"amount"`
+	CapturedAmount int64     `json:"captured_amount"`
+	Status         string    `json:"status"`
+	Description    string    `json:"description,omitempty"`
+	TransferID     *int64    `json:"transfer_id,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CaptureHoldResponse struct {
+	Hold     HoldResponse     `json:"hold"`
+	Transfer TransferResponse `json:"transfer"`
+}
+
+type TransferBatchResponse struct {
+	ID             int64                       `json:"id"`
+	FromAccountID  int64                       `json:"from_account_id"`
+	Mode           string                      `json:"mode"`
+	Status         string                      `json:"status"`
+	TotalItems     int32                       `json:"total_items"`
+	SucceededItems int32                       `json:"succeeded_items"`
+	FailedItems    int32                       `json:"failed_items"`
+	Items          []TransferBatchItemResponse `json:"items,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at"`
+	UpdatedAt      time.Time                   `json:"updated_at"`
+}
+
+type TransferBatchItemResponse struct {
+	Line        int32  `json:"line"`
+	ToAccountID int64  `json:"to_account_id"`
+	Amount      int64  `json:"amount"`
+	Status      string `json:"status"`
+	TransferID  *int64 `json:"transfer_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type UserLimitsResponse struct {
+	Tier     string                  `json:"tier"`
+	Accounts []AccountLimitsResponse `json:"accounts"`
+}
+
+type AccountLimitsResponse struct {
+	AccountID int64  `json:"account_id"`
+	Currency  string `json:"currency"`
+	// Limits is nil when the tier doesn't limit transfers in the currency of the account
+	Limits *limit.Limits `json:"limits"`
+	Used   limit.Usage   `json:"used"`
+	// Remaining leaves out the limits that are off
+	Remaining       LimitsRemainingResponse `json:"remaining"`
+	DailyResetsAt   time.Time               `json:"daily_resets_at"`
+	MonthlyResetsAt time.Time               `json:"monthly_resets_at"`
+}
+
+type LimitsRemainingResponse struct {
+	DailyTotal   *int64 `json:"daily_total,omitempty"`
+	MonthlyTotal *int64 `json:"monthly_total,omitempty"`
+	HourlyCount  *int64 `json:"hourly_count,omitempty"`
+}
+
+type FeePreviewResponse struct {
+	Currency     string        `json:"currency"`
+	Amount       int64         `json:"amount"`
+	Fee          int64         `json:"fee"`
+	FeeDecimal   string        `json:"fee_decimal"`
+	Total        int64         `json:"total"`
+	TotalDecimal string        `json:"total_decimal"`
+	FeeBreakdown fee.Breakdown `json:"fee_breakdown"`
 }
@@ -0,0 +1,14 @@
+CREATE TABLE "idempotency_keys" (
+    "username" varchar NOT NULL,
+    "key" varchar NOT NULL,
+    "request_hash" varchar NOT NULL,
+    "transfer_id" bigint,
+    "response" jsonb NOT NULL DEFAULT ('{}'),
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY ("username", "key")
+);
+ALTER TABLE "idempotency_keys"
+ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+ALTER TABLE "idempotency_keys"
+ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+COMMENT ON COLUMN "idempotency_keys"."request_hash" IS 'sha256 of the request the key was first used with';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "overdraft_limit_non_negative";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "overdraft_limit";
\ No newline at end of file
@@ -0,0 +1,54 @@
+CREATE TABLE "scheduled_transfers" (
+    "id" bigserial PRIMARY KEY,
+    "owner" varchar NOT NULL,
+    "from_account_id" bigint NOT NULL,
+    "to_account_id" bigint NOT NULL,
+    "amount" bigint NOT NULL,
+    "frequency" varchar NOT NULL DEFAULT 'once',
+    "start_at" timestamptz NOT NULL,
+    "end_at" timestamptz,
+    "next_run_at" timestamptz NOT NULL,
+    "occurrences" integer NOT NULL DEFAULT 0,
+    "status" varchar NOT NULL DEFAULT 'active',
+    "on_insufficient_funds" varchar NOT NULL DEFAULT 'skip',
+    "max_retries" integer NOT NULL DEFAULT 3,
+    "retry_count" integer NOT NULL DEFAULT 0,
+    "locked_until" timestamptz,
+    "last_run_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+CREATE TABLE "scheduled_transfer_runs" (
+    "id" bigserial PRIMARY KEY,
+    "scheduled_transfer_id" bigint NOT NULL,
+    "occurrence" integer NOT NULL,
+    "attempt" integer NOT NULL,
+    "status" varchar NOT NULL,
+    "transfer_id" bigint,
+    "error" varchar,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "scheduled_transfers"
+ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+ALTER TABLE "scheduled_transfers"
+ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "scheduled_transfers"
+ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "scheduled_transfers"
+ADD CONSTRAINT "scheduled_amount_positive" CHECK ("amount" > 0);
+ALTER TABLE "scheduled_transfers"
+ADD CONSTRAINT "scheduled_frequency_valid" CHECK ("frequency" IN ('once', 'daily', 'weekly', 'monthly'));
+ALTER TABLE "scheduled_transfers"
+ADD CONSTRAINT "scheduled_status_valid" CHECK ("status" IN ('active', 'paused', 'completed', 'cancelled', 'failed'));
+ALTER TABLE "scheduled_transfers"
+ADD CONSTRAINT "scheduled_policy_valid" CHECK ("on_insufficient_funds" IN ('skip', 'retry'));
+ALTER TABLE "scheduled_transfer_runs"
+ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+ALTER TABLE "scheduled_transfer_runs"
+ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+CREATE INDEX ON "scheduled_transfers" ("owner");
+CREATE INDEX ON "scheduled_transfers" ("status", "next_run_at");
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id");
+COMMENT ON COLUMN "scheduled_transfers"."occurrences" IS 'number of occurrences already settled, executed or skipped';
+COMMENT ON COLUMN "scheduled_transfers"."locked_until" IS 'set while a scheduler instance is executing the schedule';
+COMMENT ON COLUMN "scheduled_transfer_runs"."status" IS 'succeeded, failed or skipped';
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "exchange_rate";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "to_amount";
+DROP TABLE IF EXISTS "fx_quotes";
+DROP TABLE IF EXISTS "fx_rates";
\ No newline at end of file
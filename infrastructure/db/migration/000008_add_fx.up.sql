@@ -0,0 +1,32 @@
+CREATE TABLE "fx_rates" (
+    "id" bigserial PRIMARY KEY,
+    "base_currency" varchar NOT NULL,
+    "quote_currency" varchar NOT NULL,
+    "rate" numeric(24, 12) NOT NULL,
+    "source" varchar NOT NULL DEFAULT 'manual',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+CREATE TABLE "fx_quotes" (
+    "id" uuid PRIMARY KEY,
+    "username" varchar NOT NULL,
+    "from_currency" varchar NOT NULL,
+    "to_currency" varchar NOT NULL,
+    "rate" numeric(24, 12) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "fx_rates"
+ADD CONSTRAINT "fx_rate_positive" CHECK ("rate" > 0);
+ALTER TABLE "fx_rates"
+ADD CONSTRAINT "fx_rate_pair" CHECK ("base_currency" <> "quote_currency");
+ALTER TABLE "fx_quotes"
+ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+ALTER TABLE "transfers"
+ADD COLUMN "to_amount" bigint;
+ALTER TABLE "transfers"
+ADD COLUMN "exchange_rate" numeric(24, 12);
+CREATE INDEX ON "fx_rates" ("base_currency", "quote_currency", "created_at");
+COMMENT ON COLUMN "fx_rates"."rate" IS 'units of quote_currency for one unit of base_currency';
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited in the target currency, null when both accounts share a currency';
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'rate applied to a transfer between currencies';
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "entries_account_id_created_at_id_idx";
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "transfer_id";
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE "entries"
+ADD COLUMN "transfer_id" bigint;
+ALTER TABLE "entries"
+ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+-- entries of a transfer were written in the same transaction, so they share its created_at
+UPDATE "entries" e
+SET "transfer_id" = t."id"
+FROM "transfers" t
+WHERE e."transfer_id" IS NULL
+  AND e."created_at" = t."created_at"
+  AND (
+    (e."account_id" = t."from_account_id" AND e."amount" = -t."amount")
+    OR (e."account_id" = t."to_account_id" AND e."amount" = COALESCE(t."to_amount", t."amount"))
+  );
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that posted the entry, null for entries not made by a transfer';
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "scheduled_transfer_runs_scheduled_transfer_id_created_at_id_idx";
+DROP INDEX IF EXISTS "transfers_to_account_id_created_at_id_idx";
+DROP INDEX IF EXISTS "transfers_from_account_id_created_at_id_idx";
\ No newline at end of file
@@ -0,0 +1,3 @@
+CREATE INDEX ON "transfers" ("from_account_id", "created_at", "id");
+CREATE INDEX ON "transfers" ("to_account_id", "created_at", "id");
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "created_at", "id");
\ No newline at end of file
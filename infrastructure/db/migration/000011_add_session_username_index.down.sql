@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "sessions_username_created_at_idx";
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "consumed_at";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "family_id";
\ No newline at end of file
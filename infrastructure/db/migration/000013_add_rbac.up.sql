@@ -0,0 +1,20 @@
+ALTER TABLE "users"
+ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer';
+ALTER TABLE "users"
+ADD CONSTRAINT "users_role_check" CHECK (
+    "role" IN ('customer', 'support', 'admin', 'auditor')
+  );
+ALTER TABLE "accounts"
+ADD COLUMN "is_frozen" boolean NOT NULL DEFAULT false;
+CREATE TABLE "admin_actions" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "target" varchar NOT NULL,
+  "details" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+CREATE INDEX ON "admin_actions" ("created_at", "id");
+CREATE INDEX ON "admin_actions" ("actor", "created_at", "id");
+COMMENT ON COLUMN "admin_actions"."actor" IS 'username of the staff member, kept without a foreign key so the log outlives the user';
\ No newline at end of file
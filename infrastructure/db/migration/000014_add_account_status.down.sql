@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS "account_status_changes";
+ALTER TABLE "accounts"
+ADD COLUMN "is_deleted" boolean NOT NULL DEFAULT false;
+ALTER TABLE "accounts"
+ADD COLUMN "is_frozen" boolean NOT NULL DEFAULT false;
+UPDATE "accounts"
+SET "is_deleted" = "status" = 'closed',
+  "is_frozen" = "status" = 'frozen';
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "status_changed_at";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "status_reason";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "status";
\ No newline at end of file
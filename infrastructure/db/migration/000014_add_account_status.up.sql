@@ -0,0 +1,36 @@
+ALTER TABLE "accounts"
+ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+ALTER TABLE "accounts"
+ADD COLUMN "status_reason" varchar NOT NULL DEFAULT '';
+ALTER TABLE "accounts"
+ADD COLUMN "status_changed_at" timestamptz NOT NULL DEFAULT (now());
+UPDATE "accounts"
+SET "status" = 'closed'
+WHERE "is_deleted";
+UPDATE "accounts"
+SET "status" = 'frozen'
+WHERE "is_frozen"
+  AND NOT "is_deleted";
+ALTER TABLE "accounts"
+ADD CONSTRAINT "accounts_status_check" CHECK (
+    "status" IN ('active', 'frozen', 'dormant', 'closed')
+  );
+ALTER TABLE "accounts" DROP COLUMN "is_deleted";
+ALTER TABLE "accounts" DROP COLUMN "is_frozen";
+CREATE INDEX ON "accounts" ("owner", "status");
+CREATE TABLE "account_status_changes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "from_status" varchar NOT NULL,
+  "to_status" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "changed_by" varchar NOT NULL,
+  "sweep_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "account_status_changes"
+ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "account_status_changes"
+ADD FOREIGN KEY ("sweep_transfer_id") REFERENCES "transfers" ("id");
+CREATE INDEX ON "account_status_changes" ("account_id", "created_at", "id");
+COMMENT ON COLUMN "account_status_changes"."sweep_transfer_id" IS 'transfer that moved the remaining balance out of a closed account';
\ No newline at end of file
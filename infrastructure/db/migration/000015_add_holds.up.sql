@@ -0,0 +1,38 @@
+ALTER TABLE "accounts"
+ADD COLUMN "held_amount" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "accounts"
+ADD CONSTRAINT "accounts_held_amount_check" CHECK ("held_amount" >= 0);
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "description" varchar NOT NULL DEFAULT '',
+  "created_by" varchar NOT NULL,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "holds_amount_check" CHECK ("amount" > 0),
+  CONSTRAINT "holds_captured_amount_check" CHECK (
+    "captured_amount" >= 0
+    AND "captured_amount" <= "amount"
+  ),
+  CONSTRAINT "holds_status_check" CHECK (
+    "status" IN ('active', 'captured', 'released', 'expired')
+  )
+);
+ALTER TABLE "holds"
+ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "holds"
+ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "holds"
+ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+CREATE INDEX ON "holds" ("account_id", "created_at", "id");
+CREATE INDEX ON "holds" ("to_account_id", "created_at", "id");
+CREATE INDEX ON "holds" ("expires_at")
+WHERE "status" = 'active';
+COMMENT ON COLUMN "accounts"."held_amount" IS 'sum of the active holds on the account, the available balance is balance - held_amount';
+COMMENT ON COLUMN "holds"."transfer_id" IS 'transfer created by the capture of the hold';
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "transfer_batch_items";
+DROP TABLE IF EXISTS "transfer_batches";
\ No newline at end of file
@@ -0,0 +1,50 @@
+CREATE TABLE "transfer_batches" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "mode" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'processing',
+  "total_items" integer NOT NULL,
+  "succeeded_items" integer NOT NULL DEFAULT 0,
+  "failed_items" integer NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "transfer_batches_mode_check" CHECK ("mode" IN ('atomic', 'best_effort')),
+  CONSTRAINT "transfer_batches_status_check" CHECK (
+    "status" IN (
+      'processing',
+      'completed',
+      'partially_completed',
+      'failed'
+    )
+  )
+);
+CREATE TABLE "transfer_batch_items" (
+  "id" bigserial PRIMARY KEY,
+  "batch_id" bigint NOT NULL,
+  "line" integer NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "transfer_batch_items_status_check" CHECK (
+    "status" IN ('pending', 'succeeded', 'failed', 'aborted')
+  )
+);
+ALTER TABLE "transfer_batches"
+ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+ALTER TABLE "transfer_batches"
+ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "transfer_batch_items"
+ADD FOREIGN KEY ("batch_id") REFERENCES "transfer_batches" ("id");
+ALTER TABLE "transfer_batch_items"
+ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+CREATE INDEX ON "transfer_batches" ("owner", "created_at", "id");
+CREATE UNIQUE INDEX ON "transfer_batch_items" ("batch_id", "line");
+COMMENT ON COLUMN "transfer_batches"."mode" IS 'atomic batches run every item in one transaction, best_effort batches run each item on its own';
+COMMENT ON COLUMN "transfer_batch_items"."line" IS 'position of the item in the request, starting at 1';
+COMMENT ON COLUMN "transfer_batch_items"."to_account_id" IS 'not a foreign key, items to unknown accounts are recorded as failed';
+COMMENT ON COLUMN "transfer_batch_items"."error" IS 'why the item failed, empty unless status is failed';
\ No newline at end of file
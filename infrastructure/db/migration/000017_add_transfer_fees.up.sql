@@ -0,0 +1,5 @@
+ALTER TABLE "transfers"
+ADD COLUMN "fee" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "transfers"
+ADD CONSTRAINT "transfers_fee_check" CHECK ("fee" >= 0);
+COMMENT ON COLUMN "transfers"."fee" IS 'charged to the source account on top of amount, in its currency, and credited to the fee revenue account';
\ No newline at end of file
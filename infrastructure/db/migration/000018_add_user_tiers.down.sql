@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "transfers_from_account_id_created_at_idx";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "tier";
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE "users"
+ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard';
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+COMMENT ON COLUMN "users"."tier" IS 'transfer limits tier of the user, the tiers are configured in limits.tiers';
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "interest_accruals";
+DROP TABLE IF EXISTS "interest_postings";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "product";
\ No newline at end of file
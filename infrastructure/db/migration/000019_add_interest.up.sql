@@ -0,0 +1,41 @@
+ALTER TABLE "accounts"
+ADD COLUMN "product" varchar NOT NULL DEFAULT 'checking';
+CREATE TABLE "interest_postings" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "period" date NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "carry_micros" bigint NOT NULL DEFAULT 0,
+  CONSTRAINT "interest_postings_amount_check" CHECK ("amount" > 0)
+);
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "product" varchar NOT NULL,
+  "balance" bigint NOT NULL,
+  "annual_rate_basis_points" bigint NOT NULL,
+  "day_count" varchar NOT NULL,
+  "amount_micros" bigint NOT NULL,
+  "posting_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "interest_postings"
+ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "interest_postings"
+ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+ALTER TABLE "interest_accruals"
+ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "interest_accruals"
+ADD FOREIGN KEY ("posting_id") REFERENCES "interest_postings" ("id");
+CREATE UNIQUE INDEX ON "interest_postings" ("account_id", "period");
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "accrual_date");
+CREATE INDEX ON "interest_accruals" ("accrual_date") WHERE "posting_id" IS NULL;
+COMMENT ON COLUMN "accounts"."product" IS 'account product, the interest of the products is configured in interest.products';
+COMMENT ON COLUMN "interest_postings"."period" IS 'first day of the month the interest was posted for';
+COMMENT ON COLUMN "interest_postings"."carry_micros" IS 'accrued micros below a minor unit left over by the posting, they are paid by the next posting of the account';
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end of day balance of accrual_date, derived from entries';
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'interest earned on accrual_date in millionths of minor units';
+COMMENT ON COLUMN "interest_accruals"."posting_id" IS 'posting that paid the accrual, accruals are paid at the end of their month';
\ No newline at end of file
@@ -0,0 +1,23 @@
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "target" varchar NOT NULL,
+  "before" json NOT NULL DEFAULT 'null',
+  "after" json NOT NULL DEFAULT 'null',
+  "prev_hash" varchar NOT NULL,
+  "hash" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+CREATE INDEX ON "audit_events" ("target", "id");
+COMMENT ON COLUMN "audit_events"."actor" IS 'username from the access token, kept without a foreign key so the log outlives the user';
+COMMENT ON COLUMN "audit_events"."before" IS 'json and not jsonb, the payload is kept as it was hashed';
+COMMENT ON COLUMN "audit_events"."hash" IS 'SHA-256 of the event chained to prev_hash, the hash of the previous event';
+CREATE FUNCTION "audit_events_append_only"() RETURNS trigger AS $$ BEGIN RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER "audit_events_no_update" BEFORE
+UPDATE
+  OR DELETE ON "audit_events" FOR EACH ROW EXECUTE FUNCTION "audit_events_append_only"();
+CREATE TRIGGER "audit_events_no_truncate" BEFORE TRUNCATE ON "audit_events" FOR EACH STATEMENT EXECUTE FUNCTION "audit_events_append_only"();
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE FUNCTION "entries_notify"() RETURNS trigger AS $$ BEGIN PERFORM pg_notify('account_entries', NEW.account_id::text);
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+COMMENT ON FUNCTION "entries_notify"() IS 'notifies the account_entries channel with the account of every entry, delivered when the transaction commits';
+CREATE TRIGGER "entries_notify" AFTER
+INSERT ON "entries" FOR EACH ROW EXECUTE FUNCTION "entries_notify"();
\ No newline at end of file
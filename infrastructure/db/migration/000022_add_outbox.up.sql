@@ -0,0 +1,14 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz
+);
+CREATE INDEX ON "outbox" ("id")
+WHERE "published_at" IS NULL;
+COMMENT ON COLUMN "outbox"."aggregate_id" IS 'id of the transfer or account, or the username, the events of an aggregate are published in id order';
+COMMENT ON COLUMN "outbox"."event_type" IS 'transfer.created, transfer.reversed, account.opened, account.frozen, account.dormant, account.closed, account.activated or user.registered';
+COMMENT ON COLUMN "outbox"."published_at" IS 'null until the relay has handed the event to its sink';
\ No newline at end of file
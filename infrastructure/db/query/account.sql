@@ -1,32 +1,42 @@
 -- name: CreateAccount :one
-INSERT INTO accounts (owner, balance, currency)
-VALUES ($1, $2, $3)
+INSERT INTO accounts (owner, balance, currency, product)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 -- name: GetAccount :one
 SELECT *
 FROM accounts
 WHERE id = $1
 LIMIT 1;
+-- name: GetAccountForUpdate :one
+SELECT *
+FROM accounts
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE;
 -- name: GetAccounts :many
 SELECT *
 FROM accounts
 WHERE owner = $1
-  AND is_deleted = false;
+  AND status <> 'closed';
 -- name: GetDeletedAccounts :many
 SELECT *
 FROM accounts
 WHERE owner = $1
-  AND is_deleted = true;
+  AND status = 'closed';
 -- name: UpdateAccountBalance :one
 UPDATE accounts
 SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
--- name: DeleteAccount :exec
+-- name: UpdateAccountStatus :one
 UPDATE accounts
-SET is_deleted = true
-WHERE id = $1;
--- name: RestoreAccount :exec
+SET status = $2,
+  status_reason = $3,
+  status_changed_at = now()
+WHERE id = $1
+RETURNING *;
+-- name: AddAccountHeldAmount :one
 UPDATE accounts
-SET is_deleted = false
-WHERE id = $1;
\ No newline at end of file
+SET held_amount = held_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- name: CreateAdminAction :one
+INSERT INTO "admin_actions" (actor, role, action, target, details)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+-- name: ListAdminActions :many
+SELECT *
+FROM "admin_actions"
+WHERE (
+    sqlc.narg(actor)::varchar IS NULL
+    OR actor = sqlc.narg(actor)
+  )
+  AND (
+    sqlc.narg(after_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id))
+  )
+ORDER BY created_at DESC,
+  id DESC
+LIMIT sqlc.arg(page_size);
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- name: LockAuditChain :exec
+SELECT pg_advisory_xact_lock(hashtext('audit_events'));
+-- name: GetLastAuditEvent :one
+SELECT *
+FROM "audit_events"
+ORDER BY id DESC
+LIMIT 1;
+-- name: CreateAuditEvent :one
+INSERT INTO "audit_events" (
+    actor,
+    role,
+    action,
+    target,
+    before,
+    after,
+    prev_hash,
+    hash,
+    created_at
+  )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+-- name: ListAuditEvents :many
+SELECT *
+FROM "audit_events"
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_size);
\ No newline at end of file
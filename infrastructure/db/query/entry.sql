@@ -1,6 +1,6 @@
 -- name: CreateEntry :one
-INSERT INTO entries (account_id, amount)
-VALUES ($1, $2)
+INSERT INTO entries (account_id, amount, transfer_id)
+VALUES ($1, $2, $3)
 RETURNING *;
 -- name: GetEntry :one
 SELECT *
@@ -12,4 +12,68 @@ SELECT *
 FROM entries
 WHERE account_id = $1
 ORDER BY id
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+LIMIT $2 OFFSET $3;
+-- name: ListStatementEntries :many
+SELECT e.id,
+  e.account_id,
+  e.amount,
+  e.created_at,
+  e.transfer_id,
+  c.id AS counterparty_account_id,
+  c.owner AS counterparty_owner
+FROM entries e
+  LEFT JOIN transfers t ON t.id = e.transfer_id
+  LEFT JOIN accounts c ON c.id = CASE
+    WHEN t.from_account_id = e.account_id THEN t.to_account_id
+    ELSE t.from_account_id
+  END
+WHERE e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)
+  AND e.created_at < sqlc.arg(to_time)
+  AND (e.created_at, e.id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY e.created_at,
+  e.id
+LIMIT sqlc.arg(page_size);
+-- name: GetStatementTotals :one
+SELECT COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(from_time)), 0)::bigint AS since_from,
+  COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(to_time)), 0)::bigint AS since_to
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time);
+-- name: SumEntriesAfter :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint);
+-- name: ListAccountEvents :many
+SELECT id,
+  account_id,
+  amount,
+  balance,
+  transfer_id,
+  created_at
+FROM (
+    SELECT e.id,
+      e.account_id,
+      e.amount,
+      (
+        a.balance - COALESCE(
+          SUM(e.amount) OVER (
+            ORDER BY e.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+          ),
+          0
+        )
+      )::bigint AS balance,
+      e.transfer_id,
+      e.created_at
+    FROM entries e
+      JOIN accounts a ON a.id = e.account_id
+    WHERE e.account_id = sqlc.arg(account_id)
+      AND e.id > sqlc.arg(after_id)
+  ) events
+ORDER BY id
+LIMIT sqlc.arg(page_size);
+-- name: GetLatestEntryID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS id
+FROM entries
+WHERE account_id = $1;
\ No newline at end of file
@@ -0,0 +1,40 @@
+-- name: CreateFxRate :one
+INSERT INTO fx_rates (base_currency, quote_currency, rate, source)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+-- name: GetLatestFxRate :one
+SELECT *
+FROM fx_rates
+WHERE base_currency = $1
+  AND quote_currency = $2
+ORDER BY created_at DESC,
+  id DESC
+LIMIT 1;
+-- name: ListLatestFxRates :many
+SELECT DISTINCT ON (base_currency, quote_currency) *
+FROM fx_rates
+ORDER BY base_currency,
+  quote_currency,
+  created_at DESC,
+  id DESC;
+-- name: CreateFxQuote :one
+INSERT INTO fx_quotes (
+    id,
+    username,
+    from_currency,
+    to_currency,
+    rate,
+    expires_at
+  )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+-- name: GetFxQuoteForUpdate :one
+SELECT *
+FROM fx_quotes
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE;
+-- name: UseFxQuote :exec
+UPDATE fx_quotes
+SET used_at = now()
+WHERE id = $1;
\ No newline at end of file
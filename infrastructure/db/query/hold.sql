@@ -0,0 +1,55 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    to_account_id,
+    amount,
+    description,
+    created_by,
+    expires_at
+  )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+-- name: GetHold :one
+SELECT *
+FROM holds
+WHERE id = $1
+LIMIT 1;
+-- name: GetHoldForUpdate :one
+SELECT *
+FROM holds
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE;
+-- name: ListAccountHolds :many
+SELECT *
+FROM holds
+WHERE (
+    account_id = sqlc.arg(account_id)
+    OR to_account_id = sqlc.arg(account_id)
+  )
+  AND (
+    sqlc.narg(status)::varchar IS NULL
+    OR status = sqlc.narg(status)
+  )
+  AND (
+    sqlc.narg(after_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id))
+  )
+ORDER BY created_at DESC,
+  id DESC
+LIMIT sqlc.arg(page_size);
+-- name: SettleHold :one
+UPDATE holds
+SET status = $2,
+  captured_amount = $3,
+  transfer_id = $4,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+-- name: ListExpiredHolds :many
+SELECT id
+FROM holds
+WHERE status = 'active'
+  AND expires_at <= $1
+ORDER BY expires_at
+LIMIT $2;
\ No newline at end of file
@@ -0,0 +1,102 @@
+-- name: ListEndOfDayBalances :many
+SELECT a.id,
+  a.currency,
+  a.product,
+  (
+    a.balance - COALESCE(
+      (
+        SELECT SUM(e.amount)
+        FROM entries e
+        WHERE e.account_id = a.id
+          AND e.created_at >= sqlc.arg(day_end)
+      ),
+      0
+    )
+  )::bigint AS balance
+FROM accounts a
+WHERE a.product = ANY(sqlc.arg(products)::varchar [])
+  AND a.status <> 'closed'
+  AND a.created_at < sqlc.arg(day_end)
+  AND a.id > sqlc.arg(after_id)
+ORDER BY a.id
+LIMIT sqlc.arg(page_size);
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    product,
+    balance,
+    annual_rate_basis_points,
+    day_count,
+    amount_micros
+  )
+VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+-- name: ListUnpostedInterest :many
+SELECT i.account_id,
+  a.currency,
+  (
+    SUM(i.amount_micros) + COALESCE(
+      (
+        SELECT p.carry_micros
+        FROM interest_postings p
+        WHERE p.account_id = i.account_id
+        ORDER BY p.id DESC
+        LIMIT 1
+      ),
+      0
+    )
+  )::bigint AS amount_micros
+FROM interest_accruals i
+  JOIN accounts a ON a.id = i.account_id
+WHERE i.posting_id IS NULL
+  AND i.accrual_date < sqlc.arg(period_end)
+  AND i.account_id > sqlc.arg(after_id)
+GROUP BY i.account_id,
+  a.currency
+ORDER BY i.account_id
+LIMIT sqlc.arg(page_size);
+-- name: SumUnpostedInterest :one
+SELECT (
+    COALESCE(
+      (
+        SELECT SUM(amount_micros)
+        FROM interest_accruals
+        WHERE account_id = sqlc.arg(account_id)
+          AND posting_id IS NULL
+          AND accrual_date < sqlc.arg(period_end)
+      ),
+      0
+    ) + COALESCE(
+      (
+        SELECT carry_micros
+        FROM interest_postings
+        WHERE account_id = sqlc.arg(account_id)
+        ORDER BY id DESC
+        LIMIT 1
+      ),
+      0
+    )
+  )::bigint AS amount_micros;
+-- name: MarkInterestAccrualsPosted :execrows
+UPDATE interest_accruals
+SET posting_id = sqlc.arg(posting_id)
+WHERE account_id = sqlc.arg(account_id)
+  AND posting_id IS NULL
+  AND accrual_date < sqlc.arg(period_end);
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+    account_id,
+    period,
+    amount,
+    transfer_id,
+    carry_micros
+  )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+-- name: GetInterestPosting :one
+SELECT *
+FROM interest_postings
+WHERE account_id = $1
+  AND period = $2
+LIMIT 1;
\ No newline at end of file
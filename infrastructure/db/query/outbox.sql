@@ -0,0 +1,14 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO "outbox" (aggregate_type, aggregate_id, event_type, payload)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+-- name: ListPendingOutboxEventsForUpdate :many
+SELECT *
+FROM "outbox"
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1 FOR UPDATE;
+-- name: MarkOutboxEventsPublished :exec
+UPDATE "outbox"
+SET published_at = now()
+WHERE id = ANY(sqlc.arg(ids)::bigint []);
\ No newline at end of file
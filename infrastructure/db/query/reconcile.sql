@@ -0,0 +1,103 @@
+-- name: ListBalanceDrift :many
+SELECT a.id,
+  a.owner,
+  a.currency,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+  LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > sqlc.arg(after_id)
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id
+LIMIT sqlc.arg(page_size);
+-- name: ListUnbalancedTransfers :many
+SELECT t.id,
+  t.from_account_id,
+  t.to_account_id,
+  t.amount,
+  t.fee,
+  t.to_amount,
+  COUNT(e.id) FILTER (
+    WHERE e.account_id = t.from_account_id
+      AND e.amount < 0
+  ) AS debit_entries,
+  COALESCE(
+    SUM(e.amount) FILTER (
+      WHERE e.account_id = t.from_account_id
+        AND e.amount < 0
+    ),
+    0
+  )::bigint AS debited,
+  COUNT(e.id) FILTER (
+    WHERE e.account_id = t.to_account_id
+      AND e.amount > 0
+  ) AS credit_entries,
+  COALESCE(
+    SUM(e.amount) FILTER (
+      WHERE e.account_id = t.to_account_id
+        AND e.amount > 0
+    ),
+    0
+  )::bigint AS credited,
+  NOT EXISTS (
+    SELECT 1
+    FROM entries ce
+      JOIN accounts a ON a.id = ce.account_id
+    WHERE ce.transfer_id = t.id
+    GROUP BY a.currency
+    HAVING SUM(ce.amount) <> 0
+  ) AS zero_sum
+FROM transfers t
+  LEFT JOIN entries e ON e.transfer_id = t.id
+WHERE t.id > sqlc.arg(after_id)
+GROUP BY t.id
+HAVING COUNT(e.id) FILTER (
+    WHERE e.account_id = t.from_account_id
+      AND e.amount < 0
+  ) <> 1
+  OR COUNT(e.id) FILTER (
+    WHERE e.account_id = t.to_account_id
+      AND e.amount > 0
+  ) <> 1
+  OR COALESCE(
+    SUM(e.amount) FILTER (
+      WHERE e.account_id = t.from_account_id
+        AND e.amount < 0
+    ),
+    0
+  ) <> -(t.amount + t.fee)
+  OR COALESCE(
+    SUM(e.amount) FILTER (
+      WHERE e.account_id = t.to_account_id
+        AND e.amount > 0
+    ),
+    0
+  ) <> COALESCE(t.to_amount, t.amount)
+  OR EXISTS (
+    SELECT 1
+    FROM entries ce
+      JOIN accounts a ON a.id = ce.account_id
+    WHERE ce.transfer_id = t.id
+    GROUP BY a.currency
+    HAVING SUM(ce.amount) <> 0
+  )
+ORDER BY t.id
+LIMIT sqlc.arg(page_size);
+-- name: ListCurrencyTotals :many
+SELECT currency,
+  COALESCE(
+    SUM(balance) FILTER (
+      WHERE id = ANY(sqlc.arg(house_account_ids)::bigint [])
+    ),
+    0
+  )::bigint AS house_total,
+  COALESCE(
+    SUM(balance) FILTER (
+      WHERE NOT id = ANY(sqlc.arg(house_account_ids)::bigint [])
+    ),
+    0
+  )::bigint AS customer_total
+FROM accounts
+GROUP BY currency
+ORDER BY currency;
\ No newline at end of file
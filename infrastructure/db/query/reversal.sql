@@ -0,0 +1,19 @@
+-- name: CreateTransferReversal :one
+INSERT INTO transfer_reversals (
+    transfer_id,
+    reversal_transfer_id,
+    amount,
+    reason,
+    initiated_by
+  )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+-- name: ListTransferReversals :many
+SELECT *
+FROM transfer_reversals
+WHERE transfer_id = $1
+ORDER BY id;
+-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS reversed_amount
+FROM transfer_reversals
+WHERE transfer_id = $1;
\ No newline at end of file
@@ -0,0 +1,86 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    frequency,
+    start_at,
+    end_at,
+    next_run_at,
+    on_insufficient_funds,
+    max_retries
+  )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+-- name: GetScheduledTransfer :one
+SELECT *
+FROM scheduled_transfers
+WHERE id = $1
+LIMIT 1;
+-- name: ListScheduledTransfers :many
+SELECT *
+FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY id;
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET amount = COALESCE(sqlc.narg(amount), amount),
+  end_at = COALESCE(sqlc.narg(end_at), end_at),
+  status = COALESCE(sqlc.narg(status), status),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: ClaimDueScheduledTransfers :many
+UPDATE scheduled_transfers
+SET locked_until = sqlc.arg(locked_until)::timestamptz
+WHERE id IN (
+    SELECT id
+    FROM scheduled_transfers
+    WHERE status = 'active'
+      AND next_run_at <= sqlc.arg(now)
+      AND (
+        locked_until IS NULL
+        OR locked_until < sqlc.arg(now)
+      )
+    ORDER BY next_run_at
+    LIMIT sqlc.arg(batch_size) FOR
+    UPDATE SKIP LOCKED
+  )
+RETURNING *;
+-- name: AdvanceScheduledTransfer :one
+UPDATE scheduled_transfers
+SET next_run_at = sqlc.arg(next_run_at),
+  occurrences = sqlc.arg(occurrences),
+  retry_count = sqlc.arg(retry_count),
+  status = CASE
+    WHEN status = 'active' THEN sqlc.arg(status)::varchar
+    ELSE status
+  END,
+  last_run_at = now(),
+  locked_until = NULL,
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+    scheduled_transfer_id,
+    occurrence,
+    attempt,
+    status,
+    transfer_id,
+    error
+  )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+-- name: ListScheduledTransferRuns :many
+SELECT *
+FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = sqlc.arg(scheduled_transfer_id)
+  AND (
+    sqlc.narg(after_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id))
+  )
+ORDER BY created_at DESC,
+  id DESC
+LIMIT sqlc.arg(page_size);
\ No newline at end of file
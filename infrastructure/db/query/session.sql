@@ -1,16 +1,55 @@
 -- name: CreateSession :one
 INSERT INTO "sessions" (
     id,
+    family_id,
     username,
     refresh_token,
     user_agent,
     client_ip,
     expires_at
   )
-VALUES ($1, $2, $3, $4, $5, $6)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 -- name: GetSession :one
 SELECT *
 FROM "sessions"
 WHERE id = $1
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+-- name: ListActiveSessions :many
+SELECT *
+FROM "sessions"
+WHERE username = $1
+  AND NOT is_blocked
+  AND consumed_at IS NULL
+  AND expires_at > now()
+ORDER BY created_at DESC;
+-- name: ConsumeSession :one
+UPDATE "sessions"
+SET consumed_at = now()
+WHERE id = $1
+  AND consumed_at IS NULL
+RETURNING *;
+-- name: BlockSession :execrows
+UPDATE "sessions"
+SET is_blocked = true
+WHERE username = $2
+  AND family_id = (
+    SELECT family_id
+    FROM "sessions"
+    WHERE id = $1
+  );
+-- name: BlockOtherSessions :one
+WITH blocked AS (
+  UPDATE "sessions"
+  SET is_blocked = true
+  WHERE username = sqlc.arg(username)
+    AND family_id <> (
+      SELECT family_id
+      FROM "sessions"
+      WHERE id = sqlc.arg(keep_id)
+    )
+    AND NOT is_blocked
+  RETURNING family_id
+)
+SELECT count(DISTINCT family_id)
+FROM blocked;
\ No newline at end of file
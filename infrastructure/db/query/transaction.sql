@@ -2,19 +2,92 @@
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    fee
   )
-VALUES ($1, $2, $3)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 -- name: GetTransfer :one
 SELECT *
 FROM transfers
 WHERE id = $1
 LIMIT 1;
+-- name: GetTransferForUpdate :one
+SELECT *
+FROM transfers
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE;
 -- name: ListTransfers :many
 SELECT *
 FROM transfers
-WHERE from_account_id = sqlc.arg(account_id)
-  OR to_account_id = sqlc.arg(account_id)
-ORDER BY id
-LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_id);
\ No newline at end of file
+WHERE (
+    (
+      sqlc.arg(direction)::text IN ('', 'outgoing')
+      AND from_account_id = sqlc.arg(account_id)
+    )
+    OR (
+      sqlc.arg(direction)::text IN ('', 'incoming')
+      AND to_account_id = sqlc.arg(account_id)
+    )
+  )
+  AND (
+    sqlc.narg(counterparty_account_id)::bigint IS NULL
+    OR from_account_id = sqlc.narg(counterparty_account_id)
+    OR to_account_id = sqlc.narg(counterparty_account_id)
+  )
+  AND (
+    sqlc.narg(min_amount)::bigint IS NULL
+    OR CASE
+      WHEN to_account_id = sqlc.arg(account_id) THEN COALESCE(to_amount, amount)
+      ELSE amount
+    END >= sqlc.narg(min_amount)
+  )
+  AND (
+    sqlc.narg(max_amount)::bigint IS NULL
+    OR CASE
+      WHEN to_account_id = sqlc.arg(account_id) THEN COALESCE(to_amount, amount)
+      ELSE amount
+    END <= sqlc.narg(max_amount)
+  )
+  AND (
+    sqlc.narg(from_time)::timestamptz IS NULL
+    OR created_at >= sqlc.narg(from_time)
+  )
+  AND (
+    sqlc.narg(to_time)::timestamptz IS NULL
+    OR created_at < sqlc.narg(to_time)
+  )
+  AND (
+    sqlc.narg(after_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id))
+  )
+ORDER BY created_at DESC,
+  id DESC
+LIMIT sqlc.arg(page_size);
+-- name: GetTransferUsage :one
+SELECT COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(day_start)::timestamptz), 0)::bigint AS daily_total,
+  COALESCE(SUM(amount), 0)::bigint AS monthly_total,
+  COUNT(*) FILTER (WHERE created_at >= sqlc.arg(hour_start)::timestamptz) AS hourly_count,
+  MIN(created_at) FILTER (WHERE created_at >= sqlc.arg(hour_start)::timestamptz) AS hourly_oldest
+FROM (
+    SELECT t.amount,
+      t.created_at
+    FROM transfers t
+    WHERE t.from_account_id = sqlc.arg(account_id)
+      AND t.created_at >= sqlc.arg(month_start)::timestamptz
+      AND NOT EXISTS (
+        SELECT 1
+        FROM transfer_reversals
+        WHERE reversal_transfer_id = t.id
+      )
+    UNION ALL
+    SELECT h.amount,
+      h.created_at
+    FROM holds h
+    WHERE h.account_id = sqlc.arg(account_id)
+      AND h.status = 'active'
+      AND h.created_at >= sqlc.arg(month_start)::timestamptz
+  ) AS usage;
\ No newline at end of file
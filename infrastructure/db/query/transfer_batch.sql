@@ -0,0 +1,52 @@
+-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches (owner, from_account_id, mode, total_items)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+-- name: GetTransferBatch :one
+SELECT *
+FROM transfer_batches
+WHERE id = $1
+LIMIT 1;
+-- name: ListTransferBatches :many
+SELECT *
+FROM transfer_batches
+WHERE owner = sqlc.arg(owner)
+  AND (
+    sqlc.narg(after_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id))
+  )
+ORDER BY created_at DESC,
+  id DESC
+LIMIT sqlc.arg(page_size);
+-- name: FinishTransferBatch :one
+UPDATE transfer_batches
+SET status = $2,
+  succeeded_items = $3,
+  failed_items = $4,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+-- name: CreateTransferBatchItem :one
+INSERT INTO transfer_batch_items (
+    batch_id,
+    line,
+    to_account_id,
+    amount,
+    status,
+    error
+  )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+-- name: SettleTransferBatchItem :one
+UPDATE transfer_batch_items
+SET status = $2,
+  transfer_id = $3,
+  error = $4,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+-- name: ListTransferBatchItems :many
+SELECT *
+FROM transfer_batch_items
+WHERE batch_id = $1
+ORDER BY line;
\ No newline at end of file
@@ -7,6 +7,25 @@ SELECT *
 FROM "users"
 WHERE username = $1
 LIMIT 1;
+-- name: SearchUsers :many
+SELECT *
+FROM "users"
+WHERE (
+    sqlc.narg(query)::varchar IS NULL
+    OR username ILIKE '%' || sqlc.narg(query) || '%'
+    OR email ILIKE '%' || sqlc.narg(query) || '%'
+    OR full_name ILIKE '%' || sqlc.narg(query) || '%'
+  )
+  AND (
+    sqlc.narg(role)::varchar IS NULL
+    OR role = sqlc.narg(role)
+  )
+  AND (
+    sqlc.narg(after_username)::varchar IS NULL
+    OR username > sqlc.narg(after_username)
+  )
+ORDER BY username
+LIMIT sqlc.arg(page_size);
 -- name: UpdateUser :one
 UPDATE "users"
 SET hashed_password = coalesce(sqlc.narg('hashed_password'), hashed_password),
@@ -14,4 +33,14 @@ SET hashed_password = coalesce(sqlc.narg('hashed_password'), hashed_password),
   email = coalesce(sqlc.narg('email'), email)
 WHERE username = sqlc.arg('username')
   AND coalesce(@hashed_password, @full_name, @email) IS NOT NULL
+RETURNING *;
+-- name: UpdateUserRole :one
+UPDATE "users"
+SET role = $2
+WHERE username = $1
+RETURNING *;
+-- name: UpdateUserTier :one
+UPDATE "users"
+SET tier = $2
+WHERE username = $1
 RETURNING *;
\ No newline at end of file
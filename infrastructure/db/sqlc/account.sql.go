@@ -9,20 +9,57 @@ import (
 	"context"
 )
 
+const addAccountHeldAmount = `-- name: AddAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
+`
+
+type AddAccountHeldAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountHeldAmount, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.HeldAmount,
+		&i.Product,
+	)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO accounts (owner, balance, currency)
-VALUES ($1, $2, $3)
-RETURNING id, owner, balance, currency, created_at, is_deleted
+INSERT INTO accounts (owner, balance, currency, product)
+VALUES ($1, $2, $3, $4)
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Product  string `json:"product"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Product,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -30,31 +67,52 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
-		&i.IsDeleted,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.HeldAmount,
+		&i.Product,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-UPDATE accounts
-SET is_deleted = true
+const getAccount = `-- name: GetAccount :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
+FROM accounts
 WHERE id = $1
+LIMIT 1
 `
 
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
-	return err
+func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.HeldAmount,
+		&i.Product,
+	)
+	return i, err
 }
 
-const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at, is_deleted
+const getAccountForUpdate = `-- name: GetAccountForUpdate :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
 FROM accounts
 WHERE id = $1
-LIMIT 1
+LIMIT 1 FOR NO KEY
+UPDATE
 `
 
-func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
-	row := q.db.QueryRowContext(ctx, getAccount, id)
+func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountForUpdate, id)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -62,16 +120,21 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
-		&i.IsDeleted,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.HeldAmount,
+		&i.Product,
 	)
 	return i, err
 }
 
 const getAccounts = `-- name: GetAccounts :many
-SELECT id, owner, balance, currency, created_at, is_deleted
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
 FROM accounts
 WHERE owner = $1
-  AND is_deleted = false
+  AND status <> 'closed'
 `
 
 func (q *Queries) GetAccounts(ctx context.Context, owner string) ([]Account, error) {
@@ -89,7 +152,12 @@ func (q *Queries) GetAccounts(ctx context.Context, owner string) ([]Account, err
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
-			&i.IsDeleted,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.HeldAmount,
+			&i.Product,
 		); err != nil {
 			return nil, err
 		}
@@ -105,10 +173,10 @@ func (q *Queries) GetAccounts(ctx context.Context, owner string) ([]Account, err
 }
 
 const getDeletedAccounts = `-- name: GetDeletedAccounts :many
-SELECT id, owner, balance, currency, created_at, is_deleted
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
 FROM accounts
 WHERE owner = $1
-  AND is_deleted = true
+  AND status = 'closed'
 `
 
 func (q *Queries) GetDeletedAccounts(ctx context.Context, owner string) ([]Account, error) {
@@ -126,7 +194,12 @@ func (q *Queries) GetDeletedAccounts(ctx context.Context, owner string) ([]Accou
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
-			&i.IsDeleted,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.HeldAmount,
+			&i.Product,
 		); err != nil {
 			return nil, err
 		}
@@ -141,22 +214,11 @@ func (q *Queries) GetDeletedAccounts(ctx context.Context, owner string) ([]Accou
 	return items, nil
 }
 
-const restoreAccount = `-- name: RestoreAccount :exec
-UPDATE accounts
-SET is_deleted = false
-WHERE id = $1
-`
-
-func (q *Queries) RestoreAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, restoreAccount, id)
-	return err
-}
-
 const updateAccountBalance = `-- name: UpdateAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at, is_deleted
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
 `
 
 type UpdateAccountBalanceParams struct {
@@ -173,7 +235,46 @@ func (q *Queries) UpdateAccountBalance(ctx context.Context, arg UpdateAccountBal
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
-		&i.IsDeleted,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.HeldAmount,
+		&i.Product,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2,
+  status_reason = $3,
+  status_changed_at = now()
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, status_reason, status_changed_at, held_amount, product
+`
+
+type UpdateAccountStatusParams struct {
+	ID           int64  `json:"id"`
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.ID, arg.Status, arg.StatusReason)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.HeldAmount,
+		&i.Product,
 	)
 	return i, err
 }
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"github.com/dhiemaz/bank-api/utils/api_error"
+)
+
+const (
+	AccountStatusActive  = "active"
+	AccountStatusFrozen  = "frozen"
+	AccountStatusDormant = "dormant"
+	AccountStatusClosed  = "closed"
+)
+
+// accountTransitions lists the statuses an account can move to from each status
+var accountTransitions = map[string][]string{
+	AccountStatusActive:  {AccountStatusFrozen, AccountStatusDormant, AccountStatusClosed},
+	AccountStatusFrozen:  {AccountStatusActive, AccountStatusClosed},
+	AccountStatusDormant: {AccountStatusActive, AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusClosed:  {AccountStatusActive},
+}
+
+// ValidAccountStatus reports whether status is one of the account statuses
+func ValidAccountStatus(status string) bool {
+	_, ok := accountTransitions[status]
+	return ok
+}
+
+// CanTransitionAccount reports whether an account in status from can be moved to status to
+func CanTransitionAccount(from, to string) bool {
+	for _, allowed := range accountTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckDebit reports why money can't be taken out of the account, nil means it can
+func (account Account) CheckDebit() error {
+	switch account.Status {
+	case AccountStatusFrozen:
+		return api_error.ErrAccountFrozen
+	case AccountStatusDormant:
+		return api_error.ErrAccountDormant
+	case AccountStatusClosed:
+		return api_error.ErrAccountDeleted
+	default:
+		return nil
+	}
+}
+
+// CheckCredit reports why money can't be paid into the account, nil means it can. Dormant accounts always accept
+// credits, frozen accounts only when frozenAcceptsCredits is set.
+func (account Account) CheckCredit(frozenAcceptsCredits bool) error {
+	switch {
+	case account.Status == AccountStatusFrozen && !frozenAcceptsCredits:
+		return api_error.ErrAccountFrozen
+	case account.Status == AccountStatusClosed:
+		return api_error.ErrAccountDeleted
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+)
+
+type ChangeAccountStatusTxParam struct {
+	AccountID int64  `json:"account_id"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	ChangedBy string `json:"changed_by"`
+
+	// SweepToAccountID is optional, when closing an account with a positive balance the balance is moved to this
+	// account of the same owner. An account of another currency needs SweepQuoteID, a quote of ChangedBy.
+	SweepToAccountID int64            `json:"sweep_to_account_id"`
+	SweepQuoteID     uuid.UUID        `json:"sweep_quote_id"`
+	HouseAccounts    map[string]int64 `json:"-"`
+
+	// Audit builds the audit event of the change, appended in the same transaction. nil audits nothing.
+	Audit func(result ChangeAccountStatusTxResult) (audit.Event, error) `json:"-"`
+	// AdminAction is the admin action recorded in the same transaction once the status is changed, nil records nothing
+	AdminAction *CreateAdminActionParams `json:"-"`
+}
+
+type ChangeAccountStatusTxResult struct {
+	Account Account             `json:"account"`
+	Change  AccountStatusChange `json:"change"`
+	// Sweep is the transfer of the remaining balance, nil when nothing was swept
+	Sweep *TransferTxResult `json:"sweep,omitempty"`
+}
+
+// ChangeAccountStatusTx moves an account to another status and records the change. An account can only be closed
+// with a zero balance and no active holds, a positive balance is swept to SweepToAccountID first when it is set.
+func (store *SQLStore) ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParam) (ChangeAccountStatusTxResult, error) {
+	var result ChangeAccountStatusTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if !CanTransitionAccount(account.Status, arg.Status) {
+			return api_error.ErrInvalidStatusTransition(account.Status, arg.Status)
+		}
+
+		if arg.Status == AccountStatusClosed && account.HeldAmount != 0 {
+			return api_error.ErrAccountHasHolds
+		}
+
+		var sweepTransferID sql.NullInt64
+		if arg.Status == AccountStatusClosed && account.Balance != 0 {
+			if account.Balance < 0 || arg.SweepToAccountID == 0 {
+				return api_error.ErrAccountBalanceNotZero
+			}
+
+			sweep, err := sweepAccount(ctx, q, account, arg)
+			if err != nil {
+				return err
+			}
+
+			result.Sweep = &sweep
+			sweepTransferID = sql.NullInt64{Int64: sweep.Transfer.ID, Valid: true}
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:           arg.AccountID,
+			Status:       arg.Status,
+			StatusReason: arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Change, err = q.CreateAccountStatusChange(ctx, CreateAccountStatusChangeParams{
+			AccountID:       arg.AccountID,
+			FromStatus:      account.Status,
+			ToStatus:        arg.Status,
+			Reason:          arg.Reason,
+			ChangedBy:       arg.ChangedBy,
+			SweepTransferID: sweepTransferID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = appendOutbox(ctx, q, outbox.AggregateAccount, strconv.FormatInt(arg.AccountID, 10), accountStatusEvents[arg.Status], result.Change)
+		if err != nil {
+			return err
+		}
+
+		if err = recordAdminAction(ctx, q, arg.AdminAction); err != nil || arg.Audit == nil {
+			return err
+		}
+
+		event, err := arg.Audit(result)
+		return appendAudit(ctx, q, event, err)
+	})
+
+	return result, err
+}
+
+// sweepAccount moves the whole balance of account to the sweep account of arg, converting it at the rate of the
+// sweep quote when the currencies differ
+func sweepAccount(ctx context.Context, q *Queries, account Account, arg ChangeAccountStatusTxParam) (TransferTxResult, error) {
+	target, err := q.GetAccountForUpdate(ctx, arg.SweepToAccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TransferTxResult{}, api_error.ErrInvalidSweepAccount
+		}
+		return TransferTxResult{}, err
+	}
+
+	if target.ID == account.ID || target.Owner != account.Owner || target.CheckCredit(false) != nil {
+		return TransferTxResult{}, api_error.ErrInvalidSweepAccount
+	}
+
+	// the account is swept out of whatever status it's closed from
+	param := TransferTxParam{
+		FromAccountID:   account.ID,
+		ToAccountID:     target.ID,
+		Amount:          account.Balance,
+		Username:        arg.ChangedBy,
+		debitAuthorized: true,
+	}
+
+	if target.Currency == account.Currency {
+		return transfer(ctx, q, param)
+	}
+
+	if arg.SweepQuoteID == uuid.Nil {
+		return TransferTxResult{}, api_error.ErrCurrencyMismatch(account.Currency, target.Currency)
+	}
+
+	return fxTransfer(ctx, q, FxTransferTxParam{
+		TransferTxParam: param,
+		QuoteID:         arg.SweepQuoteID,
+		HouseAccounts:   arg.HouseAccounts,
+	})
+}
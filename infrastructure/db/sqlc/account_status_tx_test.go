@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionAccount(t *testing.T) {
+	require.True(t, CanTransitionAccount(AccountStatusActive, AccountStatusFrozen))
+	require.True(t, CanTransitionAccount(AccountStatusDormant, AccountStatusActive))
+	require.True(t, CanTransitionAccount(AccountStatusClosed, AccountStatusActive))
+	require.False(t, CanTransitionAccount(AccountStatusFrozen, AccountStatusDormant))
+	require.False(t, CanTransitionAccount(AccountStatusClosed, AccountStatusFrozen))
+	require.False(t, CanTransitionAccount(AccountStatusActive, AccountStatusActive))
+	require.False(t, CanTransitionAccount("unknown", AccountStatusActive))
+}
+
+func TestAccountCheckDebitAndCredit(t *testing.T) {
+	frozen := Account{ID: 1, Status: AccountStatusFrozen}
+	require.ErrorIs(t, frozen.CheckDebit(), api_error.ErrAccountFrozen)
+	require.ErrorIs(t, frozen.CheckCredit(false), api_error.ErrAccountFrozen)
+	require.NoError(t, frozen.CheckCredit(true))
+
+	dormant := Account{ID: 2, Status: AccountStatusDormant}
+	require.ErrorIs(t, dormant.CheckDebit(), api_error.ErrAccountDormant)
+	require.NoError(t, dormant.CheckCredit(false))
+
+	closed := Account{ID: 3, Status: AccountStatusClosed}
+	require.Error(t, closed.CheckDebit())
+	require.Error(t, closed.CheckCredit(true))
+
+	active := Account{ID: 4, Status: AccountStatusActive}
+	require.NoError(t, active.CheckDebit())
+	require.NoError(t, active.CheckCredit(false))
+}
+
+func TestChangeAccountStatusTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	result, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID: account.ID,
+		Status:    AccountStatusFrozen,
+		Reason:    "fraud investigation",
+		ChangedBy: "support1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, result.Account.Status)
+	require.Equal(t, "fraud investigation", result.Account.StatusReason)
+	require.Equal(t, AccountStatusActive, result.Change.FromStatus)
+	require.Equal(t, AccountStatusFrozen, result.Change.ToStatus)
+	require.Equal(t, "support1", result.Change.ChangedBy)
+	require.False(t, result.Change.SweepTransferID.Valid)
+	require.Nil(t, result.Sweep)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID: account.ID,
+		Status:    AccountStatusDormant,
+		ChangedBy: "support1",
+	})
+	require.True(t, api_error.IsInvalidStatusTransition(err))
+
+	// the balance isn't zero and there is nowhere to sweep it
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID: account.ID,
+		Status:    AccountStatusClosed,
+		ChangedBy: "support1",
+	})
+	require.ErrorIs(t, err, api_error.ErrAccountBalanceNotZero)
+
+	changes, err := store.ListAccountStatusChanges(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, result.Change.ID, changes[0].ID)
+}
+
+func TestChangeAccountStatusTxSweep(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createCurrencyAccount(t, currency.USD, 0)
+	target, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Balance:  0,
+		Currency: currency.IDR,
+		Product:  "checking",
+	})
+	require.NoError(t, err)
+
+	houseUSD := createCurrencyAccount(t, currency.USD, 0)
+	houseIDR := createCurrencyAccount(t, currency.IDR, 0)
+	houseAccounts := map[string]int64{currency.USD: houseUSD.ID, currency.IDR: houseIDR.ID}
+
+	// another owner's account can't take the balance
+	other := createCurrencyAccount(t, currency.IDR, 0)
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID:        account.ID,
+		Status:           AccountStatusClosed,
+		ChangedBy:        account.Owner,
+		SweepToAccountID: other.ID,
+		HouseAccounts:    houseAccounts,
+	})
+	require.ErrorIs(t, err, api_error.ErrInvalidSweepAccount)
+
+	// an account of another currency needs a quote
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID:        account.ID,
+		Status:           AccountStatusClosed,
+		ChangedBy:        account.Owner,
+		SweepToAccountID: target.ID,
+		HouseAccounts:    houseAccounts,
+	})
+	require.True(t, api_error.IsCurrencyMismatch(err))
+
+	quote := createQuote(t, account.Owner, currency.USD, currency.IDR, "15500", time.Now().Add(time.Minute))
+	result, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID:        account.ID,
+		Status:           AccountStatusClosed,
+		Reason:           "customer request",
+		ChangedBy:        account.Owner,
+		SweepToAccountID: target.ID,
+		SweepQuoteID:     quote.ID,
+		HouseAccounts:    houseAccounts,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, AccountStatusClosed, result.Account.Status)
+	require.Zero(t, result.Account.Balance)
+	require.NotNil(t, result.Sweep)
+	require.Equal(t, account.Balance, result.Sweep.Transfer.Amount)
+	require.Equal(t, account.Balance*15500, result.Sweep.ToAccount.Balance)
+	require.Equal(t, result.Sweep.Transfer.ID, result.Change.SweepTransferID.Int64)
+
+	// closed accounts can be reopened
+	result, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID: account.ID,
+		Status:    AccountStatusActive,
+		ChangedBy: account.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusActive, result.Account.Status)
+}
+
+func TestTransferTxChecksStatusUnderLock(t *testing.T) {
+	store := NewStore(testDB)
+	account := createCurrencyAccount(t, currency.USD, 100)
+	other := createCurrencyAccount(t, currency.USD, 100)
+
+	// taken while the account was active
+	hold := createActiveHold(t, store, account, other, 10)
+
+	// frozen after the transfer would have been validated
+	_, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID: account.ID,
+		Status:    AccountStatusFrozen,
+		ChangedBy: "support1",
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: account.ID, ToAccountID: other.ID, Amount: 1})
+	require.ErrorIs(t, err, api_error.ErrAccountFrozen)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: other.ID, ToAccountID: account.ID, Amount: 1})
+	require.ErrorIs(t, err, api_error.ErrAccountFrozen)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID:        other.ID,
+		ToAccountID:          account.ID,
+		Amount:               1,
+		FrozenAcceptsCredits: true,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CreateHoldTx(context.Background(), CreateHoldTxParam{
+		CreateHoldParams: CreateHoldParams{
+			AccountID:   account.ID,
+			ToAccountID: other.ID,
+			Amount:      1,
+			CreatedBy:   account.Owner,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+	})
+	require.ErrorIs(t, err, api_error.ErrAccountFrozen)
+
+	// the hold was authorized before the freeze
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParam{HoldID: hold.ID})
+	require.NoError(t, err)
+
+	// a closed account takes no credits, whatever the policy
+	closed := createCurrencyAccount(t, currency.USD, 0)
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID: closed.ID,
+		Status:    AccountStatusClosed,
+		ChangedBy: closed.Owner,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID:        other.ID,
+		ToAccountID:          closed.ID,
+		Amount:               1,
+		FrozenAcceptsCredits: true,
+	})
+	require.Error(t, err)
+
+	updated, err := testQueries.GetAccount(context.Background(), closed.ID)
+	require.NoError(t, err)
+	require.Zero(t, updated.Balance)
+}
@@ -2,11 +2,10 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 	"time"
 
-	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/random"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,8 +20,9 @@ func createRandomAccount(t *testing.T) Account {
 
 	arg := CreateAccountParams{
 		Owner:    user.Username,
-		Balance:  utils.RandomMoney(),
-		Currency: utils.RandomCurrency(),
+		Balance:  random.Money(),
+		Currency: random.Currency(),
+		Product:  "checking",
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -33,6 +33,7 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, account.Owner, arg.Owner)
 	require.Equal(t, account.Balance, arg.Balance)
 	require.Equal(t, account.Currency, arg.Currency)
+	require.Equal(t, account.Product, arg.Product)
 
 	return account
 }
@@ -59,7 +60,7 @@ func TestUpdateAccount(t *testing.T) {
 
 	arg := UpdateAccountBalanceParams{
 		ID:     account1.ID,
-		Amount: utils.RandomMoney(),
+		Amount: random.Money(),
 	}
 
 	account2, err := testQueries.UpdateAccountBalance(context.Background(), arg)
@@ -90,14 +91,30 @@ func TestListAccount(t *testing.T) {
 	}
 }
 
-func TestDeleteAccount(t *testing.T) {
+func TestUpdateAccountStatus(t *testing.T) {
 	account1 := createRandomAccount(t)
+	require.Equal(t, AccountStatusActive, account1.Status)
 
-	err := testQueries.DeleteAccount(context.Background(), account1.ID)
+	account2, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:           account1.ID,
+		Status:       AccountStatusClosed,
+		StatusReason: "moved abroad",
+	})
 	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, account2.Status)
+	require.Equal(t, "moved abroad", account2.StatusReason)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.False(t, account2.StatusChangedAt.Before(account1.StatusChangedAt))
 
-	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	accounts, err := testQueries.GetAccounts(context.Background(), account1.Owner)
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+
+	closed, err := testQueries.GetDeletedAccounts(context.Background(), account1.Owner)
+	require.NoError(t, err)
+	require.Len(t, closed, 1)
+	require.Equal(t, account1.ID, closed[0].ID)
+
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{ID: account1.ID, Status: "unknown"})
 	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, account2)
 }
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAdminAction(t *testing.T, actor string) AdminAction {
+	arg := CreateAdminActionParams{
+		Actor:   actor,
+		Role:    "admin",
+		Action:  "freeze_account",
+		Target:  "account:1",
+		Details: json.RawMessage(`{"reason": "fraud"}`),
+	}
+
+	action, err := testQueries.CreateAdminAction(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, action.ID)
+	require.Equal(t, arg.Actor, action.Actor)
+	require.Equal(t, arg.Action, action.Action)
+	require.JSONEq(t, string(arg.Details), string(action.Details))
+	require.NotZero(t, action.CreatedAt)
+
+	return action
+}
+
+func TestListAdminActions(t *testing.T) {
+	user := createRandomUser(t)
+	var created []AdminAction
+	for i := 0; i < 3; i++ {
+		created = append(created, createRandomAdminAction(t, user.Username))
+	}
+
+	arg := ListAdminActionsParams{
+		Actor:    sql.NullString{String: user.Username, Valid: true},
+		PageSize: 2,
+	}
+	page1, err := testQueries.ListAdminActions(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+	require.Equal(t, created[2].ID, page1[0].ID)
+	require.Equal(t, created[1].ID, page1[1].ID)
+
+	arg.AfterCreatedAt = sql.NullTime{Time: page1[1].CreatedAt, Valid: true}
+	arg.AfterID = sql.NullInt64{Int64: page1[1].ID, Valid: true}
+	page2, err := testQueries.ListAdminActions(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	require.Equal(t, created[0].ID, page2[0].ID)
+}
+
+func TestSetUserRoleTxRecordsAction(t *testing.T) {
+	store := NewStore(testDB)
+	admin, user := createRandomUser(t), createRandomUser(t)
+
+	action := CreateAdminActionParams{
+		Actor:   admin.Username,
+		Role:    "admin",
+		Action:  "set_user_role",
+		Target:  "user:" + user.Username,
+		Details: json.RawMessage(`{"role": "support"}`),
+	}
+
+	updated, err := store.SetUserRoleTx(context.Background(), SetUserRoleTxParam{
+		UpdateUserRoleParams: UpdateUserRoleParams{Username: user.Username, Role: "support"},
+		Action:               action,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "support", updated.Role)
+
+	// a change that fails has no action recorded
+	action.Target = "user:nobody"
+	_, err = store.SetUserRoleTx(context.Background(), SetUserRoleTxParam{
+		UpdateUserRoleParams: UpdateUserRoleParams{Username: "nobody", Role: "support"},
+		Action:               action,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	actions, err := testQueries.ListAdminActions(context.Background(), ListAdminActionsParams{
+		Actor:    sql.NullString{String: admin.Username, Valid: true},
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	require.Equal(t, "user:"+user.Username, actions[0].Target)
+}
@@ -0,0 +1,62 @@
+package db
+
+import "context"
+
+type SetUserRoleTxParam struct {
+	UpdateUserRoleParams
+	// Action is the admin action recorded in the same transaction once the role is changed
+	Action CreateAdminActionParams `json:"-"`
+}
+
+// SetUserRoleTx changes the role of a user and records the admin action that changed it, a role that isn't changed
+// has no action recorded
+func (store *SQLStore) SetUserRoleTx(ctx context.Context, arg SetUserRoleTxParam) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.UpdateUserRole(ctx, arg.UpdateUserRoleParams)
+		if err != nil {
+			return err
+		}
+
+		return recordAdminAction(ctx, q, &arg.Action)
+	})
+
+	return user, err
+}
+
+type SetUserTierTxParam struct {
+	UpdateUserTierParams
+	// Action is the admin action recorded in the same transaction once the tier is changed
+	Action CreateAdminActionParams `json:"-"`
+}
+
+// SetUserTierTx moves a user to another tier and records the admin action that moved it, a tier that isn't changed
+// has no action recorded
+func (store *SQLStore) SetUserTierTx(ctx context.Context, arg SetUserTierTxParam) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.UpdateUserTier(ctx, arg.UpdateUserTierParams)
+		if err != nil {
+			return err
+		}
+
+		return recordAdminAction(ctx, q, &arg.Action)
+	})
+
+	return user, err
+}
+
+// recordAdminAction records an admin action inside the transaction of the change it made, so that an action is
+// recorded if and only if its change is committed. A nil action records nothing.
+func recordAdminAction(ctx context.Context, q *Queries, action *CreateAdminActionParams) error {
+	if action == nil {
+		return nil
+	}
+
+	_, err := q.CreateAdminAction(ctx, *action)
+	return err
+}
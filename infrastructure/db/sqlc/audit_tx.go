@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dhiemaz/bank-api/utils/audit"
+)
+
+// AppendAuditEventTx appends an event to the audit chain in a transaction of its own
+func (store *SQLStore) AppendAuditEventTx(ctx context.Context, event audit.Event) (AuditEvent, error) {
+	var result AuditEvent
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = appendAuditEvent(ctx, q, event)
+		return err
+	})
+
+	return result, err
+}
+
+// appendAudit appends the event of a change inside the transaction of the change, so that a change is audited if and
+// only if it's committed. err is the error of building the event, an event without an action isn't appended.
+func appendAudit(ctx context.Context, q *Queries, event audit.Event, err error) error {
+	if err != nil || event.Action == "" {
+		return err
+	}
+
+	_, err = appendAuditEvent(ctx, q, event)
+	return err
+}
+
+// appendAuditEvent appends an event to the audit chain, hashed with the hash of the last event. Appends are
+// serialized by an advisory lock held until the transaction ends, so that two events never chain to the same previous
+// event.
+func appendAuditEvent(ctx context.Context, q *Queries, event audit.Event) (AuditEvent, error) {
+	if err := q.LockAuditChain(ctx); err != nil {
+		return AuditEvent{}, err
+	}
+
+	prevHash := audit.GenesisHash
+	last, err := q.GetLastAuditEvent(ctx)
+	switch {
+	case err == nil:
+		prevHash = last.Hash
+	case !errors.Is(err, sql.ErrNoRows):
+		return AuditEvent{}, err
+	}
+
+	event.Before, event.After = audit.Payload(event.Before), audit.Payload(event.After)
+	event.CreatedAt = audit.Timestamp(event.CreatedAt)
+
+	hash, err := audit.Hash(prevHash, event)
+	if err != nil {
+		return AuditEvent{}, err
+	}
+
+	return q.CreateAuditEvent(ctx, CreateAuditEventParams{
+		Actor:     event.Actor,
+		Role:      event.Role,
+		Action:    event.Action,
+		Target:    event.Target,
+		Before:    event.Before,
+		After:     event.After,
+		PrevHash:  prevHash,
+		Hash:      hash,
+		CreatedAt: event.CreatedAt,
+	})
+}
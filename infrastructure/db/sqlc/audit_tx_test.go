@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendAuditEventTx(t *testing.T) {
+	store := NewStore(testDB)
+	actor := random.Owner()
+
+	first, err := store.AppendAuditEventTx(context.Background(), audit.Event{
+		Actor:     actor,
+		Role:      "customer",
+		Action:    "open_account",
+		Target:    "account:1",
+		After:     json.RawMessage(`{"balance":1000,"currency":"USD"}`),
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage("null"), first.Before)
+
+	second, err := store.AppendAuditEventTx(context.Background(), audit.Event{
+		Actor:     actor,
+		Role:      "customer",
+		Action:    "close_account",
+		Target:    "account:1",
+		Before:    json.RawMessage(`{"balance":1000,"currency":"USD"}`),
+		After:     json.RawMessage(`{"balance":0,"currency":"USD"}`),
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	require.Greater(t, second.ID, first.ID)
+
+	// the events read back hash the same
+	events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{AfterID: first.ID - 1, PageSize: 1000})
+	require.NoError(t, err)
+
+	prevHash := first.PrevHash
+	for _, event := range events {
+		require.Equal(t, prevHash, event.PrevHash)
+
+		hash, err := audit.Hash(event.PrevHash, audit.Event{
+			Actor:     event.Actor,
+			Role:      event.Role,
+			Action:    event.Action,
+			Target:    event.Target,
+			Before:    event.Before,
+			After:     event.After,
+			CreatedAt: event.CreatedAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, event.Hash, hash)
+
+		prevHash = event.Hash
+	}
+}
+
+func TestAuditEventsAppendOnly(t *testing.T) {
+	store := NewStore(testDB)
+
+	event, err := store.AppendAuditEventTx(context.Background(), audit.Event{
+		Actor:     random.Owner(),
+		Role:      "customer",
+		Action:    "register_user",
+		Target:    "user:someone",
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.Exec(`UPDATE audit_events SET actor = 'mallory' WHERE id = $1`, event.ID)
+	require.ErrorContains(t, err, "append-only")
+
+	_, err = testDB.Exec(`DELETE FROM audit_events WHERE id = $1`, event.ID)
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestTransferTxAudit(t *testing.T) {
+	store := NewStore(testDB)
+	from, to := createFundedAccount(t, 100), createFundedAccount(t, 0)
+
+	arg := TransferTxParam{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        10,
+		Audit: func(result TransferTxResult) (audit.Event, error) {
+			return audit.Event{Actor: from.Owner, Action: "create_transfer", Target: "transfer:" + strconv.FormatInt(result.Transfer.ID, 10), CreatedAt: time.Now()}, nil
+		},
+	}
+
+	result, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	last, err := testQueries.GetLastAuditEvent(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "transfer:"+strconv.FormatInt(result.Transfer.ID, 10), last.Target)
+
+	// a transfer that can't be audited isn't made
+	arg.Audit = func(result TransferTxResult) (audit.Event, error) {
+		return audit.Event{}, errors.New("unsupported payload")
+	}
+
+	_, err = store.TransferTx(context.Background(), arg)
+	require.EqualError(t, err, "unsupported payload")
+
+	updated, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.FromAccount.Balance, updated.Balance)
+}
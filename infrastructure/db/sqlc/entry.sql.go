@@ -7,33 +7,37 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
-INSERT INTO entries (account_id, amount)
-VALUES ($1, $2)
-RETURNING id, account_id, amount, created_at
+INSERT INTO entries (account_id, amount, transfer_id)
+VALUES ($1, $2, $3)
+RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at
+SELECT id, account_id, amount, created_at, transfer_id
 FROM entries
 WHERE id = $1
 LIMIT 1
@@ -47,12 +51,101 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const getLatestEntryID = `-- name: GetLatestEntryID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS id
+FROM entries
+WHERE account_id = $1
+`
+
+func (q *Queries) GetLatestEntryID(ctx context.Context, accountID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLatestEntryID, accountID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listAccountEvents = `-- name: ListAccountEvents :many
+SELECT id,
+  account_id,
+  amount,
+  balance,
+  transfer_id,
+  created_at
+FROM (
+    SELECT e.id,
+      e.account_id,
+      e.amount,
+      (
+        a.balance - COALESCE(
+          SUM(e.amount) OVER (
+            ORDER BY e.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+          ),
+          0
+        )
+      )::bigint AS balance,
+      e.transfer_id,
+      e.created_at
+    FROM entries e
+      JOIN accounts a ON a.id = e.account_id
+    WHERE e.account_id = $1
+      AND e.id > $2
+  ) events
+ORDER BY id
+LIMIT $3
+`
+
+type ListAccountEventsParams struct {
+	AccountID int64 `json:"account_id"`
+	AfterID   int64 `json:"after_id"`
+	PageSize  int32 `json:"page_size"`
+}
+
+type ListAccountEventsRow struct {
+	ID         int64         `json:"id"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	Balance    int64         `json:"balance"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+func (q *Queries) ListAccountEvents(ctx context.Context, arg ListAccountEventsParams) ([]ListAccountEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountEvents, arg.AccountID, arg.AfterID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountEventsRow{}
+	for rows.Next() {
+		var i ListAccountEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.Balance,
+			&i.TransferID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at
+SELECT id, account_id, amount, created_at, transfer_id
 FROM entries
 WHERE account_id = $1
 ORDER BY id
@@ -79,6 +172,113 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStatementTotals = `-- name: GetStatementTotals :one
+SELECT COALESCE(SUM(amount) FILTER (WHERE created_at >= $1), 0)::bigint AS since_from,
+  COALESCE(SUM(amount) FILTER (WHERE created_at >= $2), 0)::bigint AS since_to
+FROM entries
+WHERE account_id = $3
+  AND created_at >= $1
+`
+
+type GetStatementTotalsParams struct {
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+	AccountID int64     `json:"account_id"`
+}
+
+type GetStatementTotalsRow struct {
+	SinceFrom int64 `json:"since_from"`
+	SinceTo   int64 `json:"since_to"`
+}
+
+func (q *Queries) GetStatementTotals(ctx context.Context, arg GetStatementTotalsParams) (GetStatementTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getStatementTotals, arg.FromTime, arg.ToTime, arg.AccountID)
+	var i GetStatementTotalsRow
+	err := row.Scan(&i.SinceFrom, &i.SinceTo)
+	return i, err
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT e.id,
+  e.account_id,
+  e.amount,
+  e.created_at,
+  e.transfer_id,
+  c.id AS counterparty_account_id,
+  c.owner AS counterparty_owner
+FROM entries e
+  LEFT JOIN transfers t ON t.id = e.transfer_id
+  LEFT JOIN accounts c ON c.id = CASE
+    WHEN t.from_account_id = e.account_id THEN t.to_account_id
+    ELSE t.from_account_id
+  END
+WHERE e.account_id = $1
+  AND e.created_at >= $2
+  AND e.created_at < $3
+  AND (e.created_at, e.id) > ($4::timestamptz, $5::bigint)
+ORDER BY e.created_at,
+  e.id
+LIMIT $6
+`
+
+type ListStatementEntriesParams struct {
+	AccountID      int64     `json:"account_id"`
+	FromTime       time.Time `json:"from_time"`
+	ToTime         time.Time `json:"to_time"`
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+	PageSize       int32     `json:"page_size"`
+}
+
+type ListStatementEntriesRow struct {
+	ID                    int64          `json:"id"`
+	AccountID             int64          `json:"account_id"`
+	Amount                int64          `json:"amount"`
+	CreatedAt             time.Time      `json:"created_at"`
+	TransferID            sql.NullInt64  `json:"transfer_id"`
+	CounterpartyAccountID sql.NullInt64  `json:"counterparty_account_id"`
+	CounterpartyOwner     sql.NullString `json:"counterparty_owner"`
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyOwner,
 		); err != nil {
 			return nil, err
 		}
@@ -92,3 +292,23 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	}
 	return items, nil
 }
+
+const sumEntriesAfter = `-- name: SumEntriesAfter :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = $1
+  AND (created_at, id) > ($2::timestamptz, $3::bigint)
+`
+
+type SumEntriesAfterParams struct {
+	AccountID      int64     `json:"account_id"`
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+}
+
+func (q *Queries) SumEntriesAfter(ctx context.Context, arg SumEntriesAfterParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumEntriesAfter, arg.AccountID, arg.AfterCreatedAt, arg.AfterID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
@@ -0,0 +1,206 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/google/uuid"
+)
+
+type FxTransferTxParam struct {
+	TransferTxParam
+	QuoteID uuid.UUID `json:"quote_id"`
+
+	// HouseAccounts maps a currency to the bank account taking the other side of conversions in that currency
+	HouseAccounts map[string]int64 `json:"-"`
+}
+
+// FxTransferTx moves money between accounts of different currencies at the rate locked by a quote.
+// The source account is debited in its currency and the target account credited in its own, the bank's house
+// account of each currency takes the other side so that every currency stays balanced.
+func (store *SQLStore) FxTransferTx(ctx context.Context, arg FxTransferTxParam) (TransferTxResult, error) {
+	return store.idempotentTransferTx(ctx, arg.Username, arg.IdempotencyKey, arg, arg.Audit, func(q *Queries) (TransferTxResult, error) {
+		return fxTransfer(ctx, q, arg)
+	})
+}
+
+func fxTransfer(ctx context.Context, q *Queries, arg FxTransferTxParam) (result TransferTxResult, err error) {
+	quote, err := q.GetFxQuoteForUpdate(ctx, arg.QuoteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, api_error.ErrFxQuoteNotFound
+		}
+		return result, err
+	}
+
+	switch {
+	case quote.Username != arg.Username:
+		return result, api_error.ErrFxQuoteNotFound
+	case quote.UsedAt.Valid:
+		return result, api_error.ErrFxQuoteUsed
+	case time.Now().After(quote.ExpiresAt):
+		return result, api_error.ErrFxQuoteExpired
+	}
+
+	sourceHouseID, ok := arg.HouseAccounts[quote.FromCurrency]
+	if !ok {
+		return result, api_error.ErrFxHouseAccountMissing(quote.FromCurrency)
+	}
+
+	targetHouseID, ok := arg.HouseAccounts[quote.ToCurrency]
+	if !ok {
+		return result, api_error.ErrFxHouseAccountMissing(quote.ToCurrency)
+	}
+
+	locked, err := lockAccounts(ctx, q, feeAccountIDs(arg.Fee, arg.FromAccountID, arg.ToAccountID, sourceHouseID, targetHouseID)...)
+	if err != nil {
+		return result, err
+	}
+
+	fromAccount, toAccount := locked[arg.FromAccountID], locked[arg.ToAccountID]
+	if fromAccount.Currency != quote.FromCurrency || toAccount.Currency != quote.ToCurrency {
+		return result, api_error.ErrFxQuoteMismatch
+	}
+
+	if err = checkTransferStatus(fromAccount, toAccount, arg.TransferTxParam); err != nil {
+		return result, err
+	}
+
+	if err = checkTransferLimits(ctx, q, arg.Limits, fromAccount, arg.Amount); err != nil {
+		return result, err
+	}
+
+	if err = checkFee(arg.Fee, fromAccount, locked); err != nil {
+		return result, err
+	}
+
+	if err = CheckSufficientFunds(fromAccount, arg.Amount+arg.Fee.Amount); err != nil {
+		return result, err
+	}
+
+	toAmount, err := ConvertAmount(arg.Amount, quote.Rate, quote.FromCurrency, quote.ToCurrency)
+	if err != nil {
+		return result, err
+	}
+
+	result.Transfer, err = insertTransfer(ctx, q, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		ToAmount:      sql.NullInt64{Int64: toAmount, Valid: true},
+		ExchangeRate:  sql.NullString{String: quote.Rate, Valid: true},
+		Fee:           arg.Fee.Amount,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	type fxPosting struct {
+		accountID int64
+		amount    int64
+		entry     *Entry
+	}
+
+	postings := []fxPosting{
+		{arg.FromAccountID, -(arg.Amount + arg.Fee.Amount), &result.FromEntry},
+		{sourceHouseID, arg.Amount, nil},
+		{targetHouseID, -toAmount, nil},
+		{arg.ToAccountID, toAmount, &result.ToEntry},
+	}
+
+	if arg.Fee.Amount > 0 {
+		postings = append(postings, fxPosting{arg.Fee.RevenueAccountID, arg.Fee.Amount, nil})
+		result.Fee = &arg.Fee
+	}
+
+	for _, posting := range postings {
+		entry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:  posting.accountID,
+			Amount:     posting.amount,
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return result, err
+		}
+
+		if posting.entry != nil {
+			*posting.entry = entry
+		} else {
+			result.HouseEntries = append(result.HouseEntries, entry)
+		}
+
+		// the accounts are locked already, so the order of the updates doesn't matter
+		account, err := q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{ID: posting.accountID, Amount: posting.amount})
+		if err != nil {
+			return result, err
+		}
+
+		switch posting.accountID {
+		case arg.FromAccountID:
+			result.FromAccount = account
+		case arg.ToAccountID:
+			result.ToAccount = account
+		}
+	}
+
+	return result, q.UseFxQuote(ctx, quote.ID)
+}
+
+// ConvertAmount converts minor units of the from currency into minor units of the to currency. The rate is
+// quoted between major units, so the decimals of both currencies are taken into account. The result is rounded
+// down, the rounding remainder stays with the bank.
+func ConvertAmount(amount int64, rate, from, to string) (int64, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+
+	fromCurrency, ok := currency.Lookup(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", from)
+	}
+
+	toCurrency, ok := currency.Lookup(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", to)
+	}
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), r)
+	converted.Mul(converted, big.NewRat(toCurrency.Scale(), fromCurrency.Scale()))
+	result := new(big.Int).Quo(converted.Num(), converted.Denom())
+
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("converted amount of %d at rate %s overflows", amount, rate)
+	}
+
+	if result.Sign() <= 0 {
+		return 0, api_error.ErrFxAmountTooSmall
+	}
+
+	return result.Int64(), nil
+}
+
+// LoadFxRatesTx inserts a set of rates, either all of them are stored or none
+func (store *SQLStore) LoadFxRatesTx(ctx context.Context, rates []CreateFxRateParams) ([]FxRate, error) {
+	var result []FxRate
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, rate := range rates {
+			created, err := q.CreateFxRate(ctx, rate)
+			if err != nil {
+				return fmt.Errorf("rate %s/%s: %w", rate.BaseCurrency, rate.QuoteCurrency, err)
+			}
+			result = append(result, created)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createQuote(t *testing.T, username, from, to, rate string, expiresAt time.Time) FxQuote {
+	quote, err := testQueries.CreateFxQuote(context.Background(), CreateFxQuoteParams{
+		ID:           uuid.New(),
+		Username:     username,
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         rate,
+		ExpiresAt:    expiresAt,
+	})
+	require.NoError(t, err)
+	return quote
+}
+
+func TestConvertAmount(t *testing.T) {
+	converted, err := ConvertAmount(150, "15500.5", currency.USD, currency.IDR)
+	require.NoError(t, err)
+	require.Equal(t, int64(2325075), converted)
+
+	// rounded down
+	converted, err = ConvertAmount(155000, "0.000064516129", currency.IDR, currency.USD)
+	require.NoError(t, err)
+	require.Equal(t, int64(9), converted)
+
+	// 1.00 USD to JPY at 150.5 is 150 yen, JPY has no decimals
+	converted, err = ConvertAmount(100, "150.5", currency.USD, "JPY")
+	require.NoError(t, err)
+	require.Equal(t, int64(150), converted)
+
+	// 1.234 KWD to USD at 3.25 is 4.01 USD
+	converted, err = ConvertAmount(1234, "3.25", "KWD", currency.USD)
+	require.NoError(t, err)
+	require.Equal(t, int64(401), converted)
+
+	_, err = ConvertAmount(1, "0.000064516129", currency.IDR, currency.USD)
+	require.ErrorIs(t, err, api_error.ErrFxAmountTooSmall)
+
+	_, err = ConvertAmount(1, "abc", currency.USD, currency.IDR)
+	require.Error(t, err)
+
+	_, err = ConvertAmount(100, "150.5", currency.USD, "XXX")
+	require.Error(t, err)
+}
+
+func TestFxTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createCurrencyAccount(t, currency.USD, 1000)
+	to := createCurrencyAccount(t, currency.IDR, 0)
+	houseUSD := createCurrencyAccount(t, currency.USD, 0)
+	houseIDR := createCurrencyAccount(t, currency.IDR, 0)
+	houseAccounts := map[string]int64{currency.USD: houseUSD.ID, currency.IDR: houseIDR.ID}
+
+	quote := createQuote(t, from.Owner, currency.USD, currency.IDR, "15500", time.Now().Add(time.Minute))
+
+	arg := FxTransferTxParam{
+		TransferTxParam: TransferTxParam{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        10,
+			Username:      from.Owner,
+		},
+		QuoteID:       quote.ID,
+		HouseAccounts: houseAccounts,
+	}
+
+	result, err := store.FxTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10), result.Transfer.Amount)
+	require.Equal(t, int64(155000), result.Transfer.ToAmount.Int64)
+	require.True(t, result.Transfer.ExchangeRate.Valid)
+	require.Equal(t, int64(-10), result.FromEntry.Amount)
+	require.Equal(t, int64(155000), result.ToEntry.Amount)
+	require.Len(t, result.HouseEntries, 2)
+	require.Equal(t, from.Balance-10, result.FromAccount.Balance)
+	require.Equal(t, to.Balance+155000, result.ToAccount.Balance)
+
+	// every currency stays balanced thanks to the house accounts
+	updatedHouseUSD, err := store.GetAccount(context.Background(), houseUSD.ID)
+	require.NoError(t, err)
+	require.Equal(t, houseUSD.Balance+10, updatedHouseUSD.Balance)
+
+	updatedHouseIDR, err := store.GetAccount(context.Background(), houseIDR.ID)
+	require.NoError(t, err)
+	require.Equal(t, houseIDR.Balance-155000, updatedHouseIDR.Balance)
+
+	// a quote is used once
+	_, err = store.FxTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, api_error.ErrFxQuoteUsed)
+
+	// an expired quote is refused
+	arg.QuoteID = createQuote(t, from.Owner, currency.USD, currency.IDR, "15500", time.Now().Add(-time.Second)).ID
+	_, err = store.FxTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, api_error.ErrFxQuoteExpired)
+
+	// the quote must match the currencies of the accounts
+	arg.QuoteID = createQuote(t, from.Owner, currency.IDR, currency.USD, "0.000064516129", time.Now().Add(time.Minute)).ID
+	_, err = store.FxTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, api_error.ErrFxQuoteMismatch)
+
+	// plain transfers refuse accounts of different currencies
+	_, err = store.TransferTx(context.Background(), arg.TransferTxParam)
+	require.True(t, api_error.IsCurrencyMismatch(err))
+}
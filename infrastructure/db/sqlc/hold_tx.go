@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/limit"
+)
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+type CreateHoldTxParam struct {
+	CreateHoldParams
+	// FrozenAcceptsCredits lets the hold be taken for a frozen account, see TransferTxParam
+	FrozenAcceptsCredits bool `json:"-"`
+	// Limits bounds the hold like a transfer, active holds count towards the usage of the account until they are
+	// captured, released or expire
+	Limits *limit.Policy `json:"-"`
+}
+
+type CreateHoldTxResult struct {
+	Hold Hold `json:"hold"`
+	// Account is the account the money is held on, with its new held amount
+	Account Account `json:"account"`
+}
+
+// CreateHoldTx reserves money of an account for a later transfer to ToAccountID. The money stays on the account but
+// is no longer part of its available balance until the hold is captured, released or expires. Both accounts are
+// checked like for a transfer while they are locked.
+func (store *SQLStore) CreateHoldTx(ctx context.Context, arg CreateHoldTxParam) (CreateHoldTxResult, error) {
+	var result CreateHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		locked, err := lockAccounts(ctx, q, arg.AccountID, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		account, toAccount := locked[arg.AccountID], locked[arg.ToAccountID]
+		if account.Currency != toAccount.Currency {
+			return api_error.ErrCurrencyMismatch(account.Currency, toAccount.Currency)
+		}
+
+		err = checkTransferStatus(account, toAccount, TransferTxParam{FrozenAcceptsCredits: arg.FrozenAcceptsCredits})
+		if err != nil {
+			return err
+		}
+
+		if err = checkTransferLimits(ctx, q, arg.Limits, account, arg.Amount); err != nil {
+			return err
+		}
+
+		if err = CheckSufficientFunds(account, arg.Amount); err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CreateHold(ctx, arg.CreateHoldParams)
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{ID: arg.AccountID, Amount: arg.Amount})
+		return err
+	})
+
+	return result, err
+}
+
+type CaptureHoldTxParam struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount to capture, zero captures the whole hold. The rest of a partial capture is released.
+	Amount int64 `json:"amount"`
+}
+
+type CaptureHoldTxResult struct {
+	Hold Hold `json:"hold"`
+	TransferTxResult
+}
+
+// CaptureHoldTx settles an active hold with a transfer of the captured amount, the whole hold is taken off the
+// held amount of the account so that the rest of a partial capture is available again
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParam) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := getActiveHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+
+		if amount > hold.Amount {
+			return api_error.ErrCaptureExceedsHold(amount, hold.Amount)
+		}
+
+		if _, err = lockAccounts(ctx, q, hold.AccountID, hold.ToAccountID); err != nil {
+			return err
+		}
+
+		_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{ID: hold.AccountID, Amount: -hold.Amount})
+		if err != nil {
+			return err
+		}
+
+		// the capture was authorized when the hold was created, only a closed account can't receive it anymore
+		result.TransferTxResult, err = transfer(ctx, q, TransferTxParam{
+			FromAccountID:        hold.AccountID,
+			ToAccountID:          hold.ToAccountID,
+			Amount:               amount,
+			FrozenAcceptsCredits: true,
+			debitAuthorized:      true,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.SettleHold(ctx, SettleHoldParams{
+			ID:             hold.ID,
+			Status:         HoldStatusCaptured,
+			CapturedAmount: amount,
+			TransferID:     sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type ReleaseHoldTxParam struct {
+	HoldID int64 `json:"hold_id"`
+	// Status is HoldStatusReleased, or HoldStatusExpired for a hold past its expiry
+	Status string `json:"status"`
+}
+
+// ReleaseHoldTx settles an active hold without moving money, the held amount is available again
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParam) (Hold, error) {
+	var hold Hold
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		hold, err = q.GetHoldForUpdate(ctx, arg.HoldID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return api_error.ErrHoldNotFound
+			}
+			return err
+		}
+
+		if hold.Status != HoldStatusActive {
+			return api_error.ErrHoldNotActive(hold.Status)
+		}
+
+		_, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{ID: hold.AccountID, Amount: -hold.Amount})
+		if err != nil {
+			return err
+		}
+
+		hold, err = q.SettleHold(ctx, SettleHoldParams{ID: hold.ID, Status: arg.Status})
+		return err
+	})
+
+	return hold, err
+}
+
+// getActiveHold locks a hold that can still be captured
+func getActiveHold(ctx context.Context, q *Queries, holdID int64) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return hold, api_error.ErrHoldNotFound
+		}
+		return hold, err
+	}
+
+	switch {
+	case hold.Status != HoldStatusActive:
+		return hold, api_error.ErrHoldNotActive(hold.Status)
+	case time.Now().After(hold.ExpiresAt):
+		return hold, api_error.ErrHoldExpired
+	}
+
+	return hold, nil
+}
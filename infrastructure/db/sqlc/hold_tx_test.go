@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/stretchr/testify/require"
+)
+
+func createActiveHold(t *testing.T, store Store, from, to Account, amount int64) Hold {
+	result, err := store.CreateHoldTx(context.Background(), CreateHoldTxParam{
+		CreateHoldParams: CreateHoldParams{
+			AccountID:   from.ID,
+			ToAccountID: to.ID,
+			Amount:      amount,
+			Description: "hotel deposit",
+			CreatedBy:   from.Owner,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldStatusActive, result.Hold.Status)
+	require.Equal(t, amount, result.Hold.Amount)
+	require.Equal(t, from.HeldAmount+amount, result.Account.HeldAmount)
+	require.Equal(t, from.Balance, result.Account.Balance)
+
+	return result.Hold
+}
+
+func TestCreateHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+	from := createCurrencyAccount(t, currency.USD, 0)
+	to := createCurrencyAccount(t, currency.USD, 0)
+
+	createActiveHold(t, store, from, to, from.Balance)
+
+	// the whole balance is held, nothing is available for another hold or a transfer
+	_, err := store.CreateHoldTx(context.Background(), CreateHoldTxParam{
+		CreateHoldParams: CreateHoldParams{
+			AccountID:   from.ID,
+			ToAccountID: to.ID,
+			Amount:      1,
+			CreatedBy:   from.Owner,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+	})
+	require.ErrorIs(t, err, api_error.ErrInsufficientFunds)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 1})
+	require.ErrorIs(t, err, api_error.ErrInsufficientFunds)
+
+	other := createCurrencyAccount(t, currency.IDR, 0)
+	_, err = store.CreateHoldTx(context.Background(), CreateHoldTxParam{
+		CreateHoldParams: CreateHoldParams{
+			AccountID:   from.ID,
+			ToAccountID: other.ID,
+			Amount:      1,
+			CreatedBy:   from.Owner,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+	})
+	require.True(t, api_error.IsCurrencyMismatch(err))
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+	from := createCurrencyAccount(t, currency.USD, 100)
+	to := createCurrencyAccount(t, currency.USD, 0)
+
+	hold := createActiveHold(t, store, from, to, 100)
+
+	_, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParam{HoldID: hold.ID, Amount: 101})
+	require.True(t, api_error.IsCaptureExceedsHold(err))
+
+	// a partial capture releases the rest
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParam{HoldID: hold.ID, Amount: 60})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.Equal(t, int64(60), result.Hold.CapturedAmount)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+	require.Equal(t, int64(60), result.Transfer.Amount)
+	require.Equal(t, from.Balance-60, result.FromAccount.Balance)
+	require.Zero(t, result.FromAccount.HeldAmount)
+	require.Equal(t, to.Balance+60, result.ToAccount.Balance)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParam{HoldID: hold.ID})
+	require.True(t, api_error.IsHoldNotActive(err))
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParam{HoldID: -1})
+	require.ErrorIs(t, err, api_error.ErrHoldNotFound)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+	from := createCurrencyAccount(t, currency.USD, 0)
+	to := createCurrencyAccount(t, currency.USD, 0)
+
+	hold := createActiveHold(t, store, from, to, 10)
+
+	released, err := store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParam{HoldID: hold.ID, Status: HoldStatusExpired})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusExpired, released.Status)
+	require.Zero(t, released.CapturedAmount)
+	require.False(t, released.TransferID.Valid)
+
+	account, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.HeldAmount)
+	require.Equal(t, from.Balance, account.Balance)
+
+	_, err = store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParam{HoldID: hold.ID, Status: HoldStatusReleased})
+	require.True(t, api_error.IsHoldNotActive(err))
+}
+
+func TestListExpiredHolds(t *testing.T) {
+	store := NewStore(testDB)
+	from := createCurrencyAccount(t, currency.USD, 0)
+	to := createCurrencyAccount(t, currency.USD, 0)
+
+	hold := createActiveHold(t, store, from, to, 10)
+
+	ids, err := store.ListExpiredHolds(context.Background(), ListExpiredHoldsParams{ExpiresAt: time.Now(), Limit: 1000})
+	require.NoError(t, err)
+	require.NotContains(t, ids, hold.ID)
+
+	ids, err = store.ListExpiredHolds(context.Background(), ListExpiredHoldsParams{ExpiresAt: hold.ExpiresAt.Add(time.Second), Limit: 1000})
+	require.NoError(t, err)
+	require.Contains(t, ids, hold.ID)
+
+	holds, err := store.ListAccountHolds(context.Background(), ListAccountHoldsParams{AccountID: to.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, holds, 1)
+	require.Equal(t, hold.ID, holds[0].ID)
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/interest"
+)
+
+type PostInterestTxParam struct {
+	AccountID int64 `json:"account_id"`
+	// ExpenseAccountID is the house account paying the interest, in the currency of the account
+	ExpenseAccountID int64 `json:"expense_account_id"`
+	// Period is the first day of the month the interest is posted for, accruals before its end that weren't posted
+	// yet are paid
+	Period time.Time `json:"period"`
+}
+
+type PostInterestTxResult struct {
+	Posting        InterestPosting `json:"posting"`
+	Transfer       Transfer        `json:"transfer"`
+	Account        Account         `json:"account"`
+	ExpenseAccount Account         `json:"expense_account"`
+	// Replayed is true when the interest of the period was already posted, nothing was paid again
+	Replayed bool `json:"-"`
+}
+
+// PostInterestTx pays the accrued interest of an account up to the end of a month as a transfer from the house
+// interest expense account. The interest is posted at most once per account and month, the accrued micros and the
+// carry of the previous posting are rounded down to minor units and the accruals are marked as paid by the posting.
+// The micros below a minor unit are carried by the posting to the next one.
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParam) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		locked, err := lockAccounts(ctx, q, arg.AccountID, arg.ExpenseAccountID)
+		if err != nil {
+			return err
+		}
+
+		result.Account, result.ExpenseAccount = locked[arg.AccountID], locked[arg.ExpenseAccountID]
+
+		// the account lock serializes the postings of the account, a posting found here was committed already
+		result.Posting, err = q.GetInterestPosting(ctx, GetInterestPostingParams{AccountID: arg.AccountID, Period: arg.Period})
+		if err == nil {
+			result.Replayed = true
+			return nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if result.Account.Currency != result.ExpenseAccount.Currency {
+			return api_error.ErrCurrencyMismatch(result.ExpenseAccount.Currency, result.Account.Currency)
+		}
+
+		if err = result.Account.CheckCredit(true); err != nil {
+			return err
+		}
+
+		periodEnd := arg.Period.AddDate(0, 1, 0)
+		micros, err := q.SumUnpostedInterest(ctx, SumUnpostedInterestParams{AccountID: arg.AccountID, PeriodEnd: periodEnd})
+		if err != nil {
+			return err
+		}
+
+		amount := interest.ToMinorUnits(micros)
+		if amount <= 0 {
+			return api_error.ErrNoInterestToPost
+		}
+
+		result.Transfer, result.ExpenseAccount, result.Account, err = houseTransfer(ctx, q, arg.ExpenseAccountID, arg.AccountID, amount)
+		if err != nil {
+			return err
+		}
+
+		result.Posting, err = q.CreateInterestPosting(ctx, CreateInterestPostingParams{
+			AccountID:   arg.AccountID,
+			Period:      arg.Period,
+			Amount:      amount,
+			TransferID:  sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+			CarryMicros: micros - amount*interest.MicrosPerUnit,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.MarkInterestAccrualsPosted(ctx, MarkInterestAccrualsPostedParams{
+			PostingID: sql.NullInt64{Int64: result.Posting.ID, Valid: true},
+			AccountID: arg.AccountID,
+			PeriodEnd: periodEnd,
+		})
+		return err
+	})
+
+	return result, err
+}
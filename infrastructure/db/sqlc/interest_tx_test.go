@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/stretchr/testify/require"
+)
+
+// createSavingsAccount creates a savings account of a random user with balance
+func createSavingsAccount(t *testing.T, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  balance,
+		Currency: currency.USD,
+		Product:  "savings",
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
+func TestListEndOfDayBalances(t *testing.T) {
+	store := NewStore(testDB)
+	account := createSavingsAccount(t, 1000)
+	other := createCurrencyAccount(t, currency.USD, 0)
+
+	// moved today, after the end of yesterday
+	_, err := store.TransferTx(context.Background(), TransferTxParam{FromAccountID: account.ID, ToAccountID: other.ID, Amount: 300})
+	require.NoError(t, err)
+
+	arg := ListEndOfDayBalancesParams{
+		DayEnd:   time.Now().Add(time.Hour),
+		Products: []string{"savings"},
+		AfterID:  account.ID - 1,
+		PageSize: 1,
+	}
+	balances, err := testQueries.ListEndOfDayBalances(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	require.Equal(t, account.ID, balances[0].ID)
+	require.Equal(t, int64(700), balances[0].Balance)
+
+	// accounts opened after the end of the day had no balance yet
+	arg.DayEnd = account.CreatedAt
+	balances, err = testQueries.ListEndOfDayBalances(context.Background(), arg)
+	require.NoError(t, err)
+	for _, balance := range balances {
+		require.NotEqual(t, account.ID, balance.ID)
+	}
+}
+
+func TestPostInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createSavingsAccount(t, 1000000)
+	expense := createCurrencyAccount(t, currency.USD, 0)
+
+	period := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	for day := period; day.Month() == period.Month(); day = day.AddDate(0, 0, 1) {
+		_, err := testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+			AccountID:             account.ID,
+			AccrualDate:           day,
+			Product:               "savings",
+			Balance:               1000000,
+			AnnualRateBasisPoints: 365,
+			DayCount:              "actual/365",
+			AmountMicros:          100500000,
+		})
+		require.NoError(t, err)
+	}
+
+	// accruals are recorded once per day
+	_, err := testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+		AccountID:   account.ID,
+		AccrualDate: period,
+		Product:     "savings",
+		DayCount:    "actual/365",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	arg := PostInterestTxParam{AccountID: account.ID, ExpenseAccountID: expense.ID, Period: period}
+	result, err := store.PostInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.Replayed)
+
+	// 29 days of 100.5, rounded down
+	require.Equal(t, int64(2914), result.Posting.Amount)
+	require.Equal(t, result.Transfer.ID, result.Posting.TransferID.Int64)
+	require.Equal(t, expense.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account.Balance+2914, result.Account.Balance)
+	require.Equal(t, expense.Balance-2914, result.ExpenseAccount.Balance)
+
+	// the half unit left over is carried to the next posting
+	require.Equal(t, int64(500000), result.Posting.CarryMicros)
+
+	micros, err := testQueries.SumUnpostedInterest(context.Background(), SumUnpostedInterestParams{
+		AccountID: account.ID,
+		PeriodEnd: period.AddDate(0, 1, 0),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(500000), micros)
+
+	// posting the period again pays nothing
+	replayed, err := store.PostInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, replayed.Replayed)
+	require.Equal(t, result.Posting.ID, replayed.Posting.ID)
+
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Account.Balance, updated.Balance)
+
+	// nothing accrued in March, the carry alone is below a unit
+	_, err = store.PostInterestTx(context.Background(), PostInterestTxParam{
+		AccountID:        account.ID,
+		ExpenseAccountID: expense.ID,
+		Period:           period.AddDate(0, 1, 0),
+	})
+	require.ErrorIs(t, err, api_error.ErrNoInterestToPost)
+
+	// the carry is paid with the interest of April
+	april := period.AddDate(0, 2, 0)
+	_, err = testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+		AccountID:             account.ID,
+		AccrualDate:           april,
+		Product:               "savings",
+		Balance:               1000000,
+		AnnualRateBasisPoints: 365,
+		DayCount:              "actual/365",
+		AmountMicros:          100500000,
+	})
+	require.NoError(t, err)
+
+	unposted, err := testQueries.ListUnpostedInterest(context.Background(), ListUnpostedInterestParams{
+		PeriodEnd: april.AddDate(0, 1, 0),
+		AfterID:   account.ID - 1,
+		PageSize:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, unposted, 1)
+	require.Equal(t, int64(101000000), unposted[0].AmountMicros)
+
+	result, err = store.PostInterestTx(context.Background(), PostInterestTxParam{
+		AccountID:        account.ID,
+		ExpenseAccountID: expense.ID,
+		Period:           april,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(101), result.Posting.Amount)
+	require.Zero(t, result.Posting.CarryMicros)
+}
@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,7 +18,54 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
-	IsDeleted bool      `json:"is_deleted"`
+	// how far below zero the balance may go
+	OverdraftLimit  int64     `json:"overdraft_limit"`
+	Status          string    `json:"status"`
+	StatusReason    string    `json:"status_reason"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
+	// sum of the active holds on the account, the available balance is balance - held_amount
+	HeldAmount int64 `json:"held_amount"`
+	// account product, the interest of the products is configured in interest.products
+	Product string `json:"product"`
+}
+
+type AccountStatusChange struct {
+	ID         int64  `json:"id"`
+	AccountID  int64  `json:"account_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+	ChangedBy  string `json:"changed_by"`
+	// transfer that moved the remaining balance out of a closed account
+	SweepTransferID sql.NullInt64 `json:"sweep_transfer_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+type AdminAction struct {
+	ID int64 `json:"id"`
+	// username of the staff member, kept without a foreign key so the log outlives the user
+	Actor     string          `json:"actor"`
+	Role      string          `json:"role"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// username from the access token, kept without a foreign key so the log outlives the user
+	Actor  string `json:"actor"`
+	Role   string `json:"role"`
+	Action string `json:"action"`
+	Target string `json:"target"`
+	// json and not jsonb, the payload is kept as it was hashed
+	Before   json.RawMessage `json:"before"`
+	After    json.RawMessage `json:"after"`
+	PrevHash string          `json:"prev_hash"`
+	// SHA-256 of the event chained to prev_hash, the hash of the previous event
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Entry struct {
@@ -25,17 +74,144 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer that posted the entry, null for entries not made by a transfer
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type FxQuote struct {
+	ID           uuid.UUID    `json:"id"`
+	Username     string       `json:"username"`
+	FromCurrency string       `json:"from_currency"`
+	ToCurrency   string       `json:"to_currency"`
+	Rate         string       `json:"rate"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	UsedAt       sql.NullTime `json:"used_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type FxRate struct {
+	ID            int64  `json:"id"`
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	// units of quote_currency for one unit of base_currency
+	Rate      string    `json:"rate"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Hold struct {
+	ID             int64  `json:"id"`
+	AccountID      int64  `json:"account_id"`
+	ToAccountID    int64  `json:"to_account_id"`
+	Amount         int64  `json:"amount"`
+	CapturedAmount int64  `json:"captured_amount"`
+	Status         string `json:"status"`
+	Description    string `json:"description"`
+	CreatedBy      string `json:"created_by"`
+	// transfer created by the capture of the hold
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+type IdempotencyKey struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+	// sha256 of the request the key was first used with
+	RequestHash string          `json:"request_hash"`
+	TransferID  sql.NullInt64   `json:"transfer_id"`
+	Response    json.RawMessage `json:"response"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type InterestAccrual struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	Product     string    `json:"product"`
+	// end of day balance of accrual_date, derived from entries
+	Balance               int64  `json:"balance"`
+	AnnualRateBasisPoints int64  `json:"annual_rate_basis_points"`
+	DayCount              string `json:"day_count"`
+	// interest earned on accrual_date in millionths of minor units
+	AmountMicros int64 `json:"amount_micros"`
+	// posting that paid the accrual, accruals are paid at the end of their month
+	PostingID sql.NullInt64 `json:"posting_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type InterestPosting struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// first day of the month the interest was posted for
+	Period     time.Time     `json:"period"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	// accrued micros below a minor unit left over by the posting, they are paid by the next posting of the account
+	CarryMicros int64 `json:"carry_micros"`
+}
+
+type Outbox struct {
+	ID            int64  `json:"id"`
+	AggregateType string `json:"aggregate_type"`
+	// id of the transfer or account, or the username, the events of an aggregate are published in id order
+	AggregateID string `json:"aggregate_id"`
+	// transfer.created, transfer.reversed, account.opened, account.frozen, account.dormant, account.closed, account.activated or user.registered
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	// null until the relay has handed the event to its sink
+	PublishedAt sql.NullTime `json:"published_at"`
 }
 
 type Session struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	IsBlocked    bool      `json:"is_blocked"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uuid.UUID    `json:"id"`
+	Username     string       `json:"username"`
+	RefreshToken string       `json:"refresh_token"`
+	IsBlocked    bool         `json:"is_blocked"`
+	UserAgent    string       `json:"user_agent"`
+	ClientIp     string       `json:"client_ip"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+	FamilyID     uuid.UUID    `json:"family_id"`
+	ConsumedAt   sql.NullTime `json:"consumed_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64        `json:"id"`
+	Owner         string       `json:"owner"`
+	FromAccountID int64        `json:"from_account_id"`
+	ToAccountID   int64        `json:"to_account_id"`
+	Amount        int64        `json:"amount"`
+	Frequency     string       `json:"frequency"`
+	StartAt       time.Time    `json:"start_at"`
+	EndAt         sql.NullTime `json:"end_at"`
+	NextRunAt     time.Time    `json:"next_run_at"`
+	// number of occurrences already settled, executed or skipped
+	Occurrences         int32  `json:"occurrences"`
+	Status              string `json:"status"`
+	OnInsufficientFunds string `json:"on_insufficient_funds"`
+	MaxRetries          int32  `json:"max_retries"`
+	RetryCount          int32  `json:"retry_count"`
+	// set while a scheduler instance is executing the schedule
+	LockedUntil sql.NullTime `json:"locked_until"`
+	LastRunAt   sql.NullTime `json:"last_run_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64 `json:"id"`
+	ScheduledTransferID int64 `json:"scheduled_transfer_id"`
+	Occurrence          int32 `json:"occurrence"`
+	Attempt             int32 `json:"attempt"`
+	// succeeded, failed or skipped
+	Status     string         `json:"status"`
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	Error      sql.NullString `json:"error"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
 type Transfer struct {
@@ -45,6 +221,54 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// amount credited in the target currency, null when both accounts share a currency
+	ToAmount sql.NullInt64 `json:"to_amount"`
+	// rate applied to a transfer between currencies
+	ExchangeRate sql.NullString `json:"exchange_rate"`
+	// charged to the source account on top of amount, in its currency, and credited to the fee revenue account
+	Fee int64 `json:"fee"`
+}
+
+type TransferBatch struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	// atomic batches run every item in one transaction, best_effort batches run each item on its own
+	Mode           string    `json:"mode"`
+	Status         string    `json:"status"`
+	TotalItems     int32     `json:"total_items"`
+	SucceededItems int32     `json:"succeeded_items"`
+	FailedItems    int32     `json:"failed_items"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type TransferBatchItem struct {
+	ID      int64 `json:"id"`
+	BatchID int64 `json:"batch_id"`
+	// position of the item in the request, starting at 1
+	Line int32 `json:"line"`
+	// not a foreign key, items to unknown accounts are recorded as failed
+	ToAccountID int64         `json:"to_account_id"`
+	Amount      int64         `json:"amount"`
+	Status      string        `json:"status"`
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+	// why the item failed, empty unless status is failed
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TransferReversal struct {
+	ID int64 `json:"id"`
+	// the original transfer being reversed
+	TransferID int64 `json:"transfer_id"`
+	// the compensating transfer moving the money back
+	ReversalTransferID int64     `json:"reversal_transfer_id"`
+	Amount             int64     `json:"amount"`
+	Reason             string    `json:"reason"`
+	InitiatedBy        string    `json:"initiated_by"`
+	CreatedAt          time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -54,4 +278,7 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
+	// transfer limits tier of the user, the tiers are configured in limits.tiers
+	Tier string `json:"tier"`
 }
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+)
+
+type OpenAccountTxParam struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+	Product  string `json:"product"`
+	// OpeningBalance is paid by FundingAccountID, a house account in the currency of the account. The account opens
+	// empty when either is zero.
+	OpeningBalance   int64 `json:"opening_balance"`
+	FundingAccountID int64 `json:"funding_account_id"`
+	// Audit builds the audit event of the opening, appended in the same transaction. nil audits nothing.
+	Audit func(result OpenAccountTxResult) (audit.Event, error) `json:"-"`
+}
+
+type OpenAccountTxResult struct {
+	Account Account `json:"account"`
+	// Funding is the transfer of the opening balance, nil when the account opened empty
+	Funding *Transfer `json:"funding,omitempty"`
+}
+
+// OpenAccountTx creates an account and pays its opening balance from a house account, so that the opening balance
+// is backed by entries like every other balance change.
+func (store *SQLStore) OpenAccountTx(ctx context.Context, arg OpenAccountTxParam) (OpenAccountTxResult, error) {
+	var result OpenAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = openAccount(ctx, q, arg)
+		if err != nil || arg.Audit == nil {
+			return err
+		}
+
+		event, err := arg.Audit(result)
+		return appendAudit(ctx, q, event, err)
+	})
+
+	return result, err
+}
+
+// openAccount opens an account inside an already running transaction
+func openAccount(ctx context.Context, q *Queries, arg OpenAccountTxParam) (result OpenAccountTxResult, err error) {
+	result.Account, err = q.CreateAccount(ctx, CreateAccountParams{
+		Owner:    arg.Owner,
+		Currency: arg.Currency,
+		Product:  arg.Product,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = appendOutbox(ctx, q, outbox.AggregateAccount, strconv.FormatInt(result.Account.ID, 10), outbox.EventAccountOpened, result.Account)
+	if err != nil {
+		return result, err
+	}
+
+	if arg.OpeningBalance <= 0 || arg.FundingAccountID == 0 {
+		return result, nil
+	}
+
+	locked, err := lockAccounts(ctx, q, arg.FundingAccountID, result.Account.ID)
+	if err != nil {
+		return result, err
+	}
+
+	if funding := locked[arg.FundingAccountID]; funding.Currency != arg.Currency {
+		return result, api_error.ErrCurrencyMismatch(funding.Currency, arg.Currency)
+	}
+
+	transfer, _, account, err := houseTransfer(ctx, q, arg.FundingAccountID, result.Account.ID, arg.OpeningBalance)
+	if err != nil {
+		return result, err
+	}
+
+	result.Account, result.Funding = account, &transfer
+	return result, nil
+}
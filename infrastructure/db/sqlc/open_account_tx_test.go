@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	funding := createCurrencyAccount(t, currency.USD, 0)
+
+	result, err := store.OpenAccountTx(context.Background(), OpenAccountTxParam{
+		Owner:            createRandomUser(t).Username,
+		Currency:         currency.USD,
+		Product:          "checking",
+		OpeningBalance:   1000,
+		FundingAccountID: funding.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), result.Account.Balance)
+	require.NotNil(t, result.Funding)
+	require.Equal(t, funding.ID, result.Funding.FromAccountID)
+	require.Equal(t, result.Account.ID, result.Funding.ToAccountID)
+
+	entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{AccountID: result.Account.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, int64(1000), entries[0].Amount)
+
+	fundingAfter, err := testQueries.GetAccount(context.Background(), funding.ID)
+	require.NoError(t, err)
+	require.Equal(t, funding.Balance-1000, fundingAfter.Balance)
+
+	// without a funding account the account opens empty
+	result, err = store.OpenAccountTx(context.Background(), OpenAccountTxParam{
+		Owner:          createRandomUser(t).Username,
+		Currency:       currency.USD,
+		Product:        "checking",
+		OpeningBalance: 1000,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Account.Balance)
+	require.Nil(t, result.Funding)
+}
+
+func TestOpenAccountTxCurrencyMismatch(t *testing.T) {
+	store := NewStore(testDB)
+	funding := createCurrencyAccount(t, currency.IDR, 0)
+	owner := createRandomUser(t).Username
+
+	_, err := store.OpenAccountTx(context.Background(), OpenAccountTxParam{
+		Owner:            owner,
+		Currency:         currency.USD,
+		Product:          "checking",
+		OpeningBalance:   1000,
+		FundingAccountID: funding.ID,
+	})
+	require.True(t, api_error.IsCurrencyMismatch(err))
+
+	// the account wasn't opened
+	accounts, err := testQueries.GetAccounts(context.Background(), owner)
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
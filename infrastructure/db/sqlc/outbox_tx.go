@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/dhiemaz/bank-api/utils/outbox"
+)
+
+// appendOutbox writes an event to the outbox inside the running transaction, so it's published if and only if the
+// change it describes is committed
+func appendOutbox(ctx context.Context, q *Queries, aggregateType, aggregateID, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	})
+	return err
+}
+
+// insertTransfer creates a transfer with its transfer.created event, every transfer is created through it
+func insertTransfer(ctx context.Context, q *Queries, arg CreateTransferParams) (Transfer, error) {
+	transfer, err := q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return transfer, err
+	}
+
+	err = appendOutbox(ctx, q, outbox.AggregateTransfer, strconv.FormatInt(transfer.ID, 10), outbox.EventTransferCreated, transfer)
+	return transfer, err
+}
+
+// accountStatusEvents are the events of the account statuses, an account moved to a status has its event written
+var accountStatusEvents = map[string]string{
+	AccountStatusActive:  outbox.EventAccountActivated,
+	AccountStatusFrozen:  outbox.EventAccountFrozen,
+	AccountStatusDormant: outbox.EventAccountDormant,
+	AccountStatusClosed:  outbox.EventAccountClosed,
+}
+
+type RelayOutboxTxParam struct {
+	Limit int32 `json:"limit"`
+	// Publish is called with the pending events in order and stops at the first one it fails to publish
+	Publish func(event Outbox) error `json:"-"`
+}
+
+type RelayOutboxTxResult struct {
+	Published []Outbox `json:"published"`
+	// Pending is how many events were read, fewer than Limit when the outbox was drained
+	Pending int `json:"pending"`
+}
+
+// RelayOutboxTx publishes up to Limit pending events in order and marks the published ones. The pending events are
+// locked, so a concurrent relay waits for this one instead of publishing the same events out of order. When Publish
+// fails the events before it are marked published and its error is returned once they are, the failed event is
+// published again by the next relay: delivery is at least once.
+func (store *SQLStore) RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParam) (RelayOutboxTxResult, error) {
+	var result RelayOutboxTxResult
+	var publishErr error
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		events, err := q.ListPendingOutboxEventsForUpdate(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+
+		result.Pending = len(events)
+		ids := make([]int64, 0, len(events))
+		for _, event := range events {
+			if publishErr = arg.Publish(event); publishErr != nil {
+				break
+			}
+
+			result.Published = append(result.Published, event)
+			ids = append(ids, event.ID)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+		return q.MarkOutboxEventsPublished(ctx, ids)
+	})
+	if err != nil {
+		return RelayOutboxTxResult{}, err
+	}
+
+	return result, publishErr
+}
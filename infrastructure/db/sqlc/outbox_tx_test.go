@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+	"github.com/dhiemaz/bank-api/utils/random"
+	"github.com/stretchr/testify/require"
+)
+
+// relayAll drains the outbox and returns the events published of aggregate
+func relayAll(t *testing.T, store Store, aggregateType, aggregateID string) []Outbox {
+	var events []Outbox
+	for {
+		result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParam{
+			Limit: 100,
+			Publish: func(event Outbox) error {
+				if event.AggregateType == aggregateType && event.AggregateID == aggregateID {
+					events = append(events, event)
+				}
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		if result.Pending < 100 {
+			return events
+		}
+	}
+}
+
+func TestTransferTxOutbox(t *testing.T) {
+	store := NewStore(testDB)
+	account1, account2 := createFundedAccount(t, 1000), createFundedAccount(t, 0)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	reversal, err := store.ReverseTransfer(context.Background(), ReverseTransferTxParam{
+		TransferID:  result.Transfer.ID,
+		Amount:      4,
+		InitiatedBy: "support",
+	})
+	require.NoError(t, err)
+
+	events := relayAll(t, store, outbox.AggregateTransfer, strconv.FormatInt(result.Transfer.ID, 10))
+	require.Len(t, events, 2)
+	require.Equal(t, outbox.EventTransferCreated, events[0].EventType)
+	require.Equal(t, outbox.EventTransferReversed, events[1].EventType)
+
+	var transfer Transfer
+	require.NoError(t, json.Unmarshal(events[0].Payload, &transfer))
+	require.Equal(t, result.Transfer.ID, transfer.ID)
+	require.Equal(t, int64(10), transfer.Amount)
+
+	// the compensating transfer is a transfer of its own
+	events = relayAll(t, store, outbox.AggregateTransfer, strconv.FormatInt(reversal.Transfer.ID, 10))
+	require.Len(t, events, 1)
+	require.Equal(t, outbox.EventTransferCreated, events[0].EventType)
+}
+
+func TestAccountOutbox(t *testing.T) {
+	store := NewStore(testDB)
+
+	opened, err := store.OpenAccountTx(context.Background(), OpenAccountTxParam{
+		Owner:    createRandomUser(t).Username,
+		Currency: currency.USD,
+		Product:  "checking",
+	})
+	require.NoError(t, err)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParam{
+		AccountID: opened.Account.ID,
+		Status:    AccountStatusFrozen,
+		Reason:    "fraud review",
+		ChangedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	events := relayAll(t, store, outbox.AggregateAccount, strconv.FormatInt(opened.Account.ID, 10))
+	require.Len(t, events, 2)
+	require.Equal(t, outbox.EventAccountOpened, events[0].EventType)
+	require.Equal(t, outbox.EventAccountFrozen, events[1].EventType)
+}
+
+func TestCreateUserTxOutbox(t *testing.T) {
+	store := NewStore(testDB)
+	arg := CreateUserTxParam{CreateUserParams: CreateUserParams{
+		Username:       random.Owner(),
+		HashedPassword: "secret",
+		FullName:       random.Owner(),
+		Email:          random.Email(),
+	}}
+
+	user, err := store.CreateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	events := relayAll(t, store, outbox.AggregateUser, user.Username)
+	require.Len(t, events, 1)
+	require.Equal(t, outbox.EventUserRegistered, events[0].EventType)
+	require.NotContains(t, string(events[0].Payload), "secret")
+
+	// a user that isn't created has no event
+	_, err = store.CreateUserTx(context.Background(), arg)
+	require.Error(t, err)
+	require.Empty(t, relayAll(t, store, outbox.AggregateUser, user.Username))
+}
+
+func TestRelayOutboxTxPublishError(t *testing.T) {
+	store := NewStore(testDB)
+	relayAll(t, store, "", "")
+
+	_, err := store.OpenAccountTx(context.Background(), OpenAccountTxParam{
+		Owner:    createRandomUser(t).Username,
+		Currency: currency.USD,
+		Product:  "checking",
+	})
+	require.NoError(t, err)
+
+	errSink := errors.New("sink down")
+	result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParam{
+		Limit:   100,
+		Publish: func(Outbox) error { return errSink },
+	})
+	require.ErrorIs(t, err, errSink)
+	require.Empty(t, result.Published)
+
+	// the event is still pending
+	result, err = store.RelayOutboxTx(context.Background(), RelayOutboxTxParam{
+		Limit:   100,
+		Publish: func(Outbox) error { return nil },
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Published, 1)
+	require.Equal(t, outbox.EventAccountOpened, result.Published[0].EventType)
+}
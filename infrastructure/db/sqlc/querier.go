@@ -11,24 +11,93 @@ import (
 )
 
 type Querier interface {
+	AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error)
+	AdvanceScheduledTransfer(ctx context.Context, arg AdvanceScheduledTransferParams) (ScheduledTransfer, error)
+	BlockOtherSessions(ctx context.Context, arg BlockOtherSessionsParams) (int64, error)
+	BlockSession(ctx context.Context, arg BlockSessionParams) (int64, error)
+	ClaimDueScheduledTransfers(ctx context.Context, arg ClaimDueScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ConsumeSession(ctx context.Context, id uuid.UUID) (Session, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error)
+	CreateAdminAction(ctx context.Context, arg CreateAdminActionParams) (AdminAction, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateFxQuote(ctx context.Context, arg CreateFxQuoteParams) (FxQuote, error)
+	CreateFxRate(ctx context.Context, arg CreateFxRateParams) (FxRate, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error)
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (Outbox, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error)
+	CreateTransferBatchItem(ctx context.Context, arg CreateTransferBatchItemParams) (TransferBatchItem, error)
+	CreateTransferReversal(ctx context.Context, arg CreateTransferReversalParams) (TransferReversal, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	FinishTransferBatch(ctx context.Context, arg FinishTransferBatchParams) (TransferBatch, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
 	GetAccounts(ctx context.Context, owner string) ([]Account, error)
 	GetDeletedAccounts(ctx context.Context, owner string) ([]Account, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFxQuoteForUpdate(ctx context.Context, id uuid.UUID) (FxQuote, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetInterestPosting(ctx context.Context, arg GetInterestPostingParams) (InterestPosting, error)
+	GetLastAuditEvent(ctx context.Context) (AuditEvent, error)
+	GetLatestEntryID(ctx context.Context, accountID int64) (int64, error)
+	GetLatestFxRate(ctx context.Context, arg GetLatestFxRateParams) (FxRate, error)
+	GetReversedAmount(ctx context.Context, transferID int64) (int64, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetStatementTotals(ctx context.Context, arg GetStatementTotalsParams) (GetStatementTotalsRow, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferBatch(ctx context.Context, id int64) (TransferBatch, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	ListAccountEvents(ctx context.Context, arg ListAccountEventsParams) ([]ListAccountEventsRow, error)
+	ListAccountHolds(ctx context.Context, arg ListAccountHoldsParams) ([]Hold, error)
+	ListAccountStatusChanges(ctx context.Context, accountID int64) ([]AccountStatusChange, error)
+	ListActiveSessions(ctx context.Context, username string) ([]Session, error)
+	ListAdminActions(ctx context.Context, arg ListAdminActionsParams) ([]AdminAction, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListBalanceDrift(ctx context.Context, arg ListBalanceDriftParams) ([]ListBalanceDriftRow, error)
+	ListCurrencyTotals(ctx context.Context, houseAccountIds []int64) ([]ListCurrencyTotalsRow, error)
+	ListEndOfDayBalances(ctx context.Context, arg ListEndOfDayBalancesParams) ([]ListEndOfDayBalancesRow, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListExpiredHolds(ctx context.Context, arg ListExpiredHoldsParams) ([]int64, error)
+	ListLatestFxRates(ctx context.Context) ([]FxRate, error)
+	ListPendingOutboxEventsForUpdate(ctx context.Context, limit int32) ([]Outbox, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, owner string) ([]ScheduledTransfer, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListTransferBatchItems(ctx context.Context, batchID int64) ([]TransferBatchItem, error)
+	ListTransferBatches(ctx context.Context, arg ListTransferBatchesParams) ([]TransferBatch, error)
+	ListTransferReversals(ctx context.Context, transferID int64) ([]TransferReversal, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
-	RestoreAccount(ctx context.Context, id int64) error
+	ListUnbalancedTransfers(ctx context.Context, arg ListUnbalancedTransfersParams) ([]ListUnbalancedTransfersRow, error)
+	ListUnpostedInterest(ctx context.Context, arg ListUnpostedInterestParams) ([]ListUnpostedInterestRow, error)
+	LockAuditChain(ctx context.Context) error
+	MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) (int64, error)
+	MarkOutboxEventsPublished(ctx context.Context, ids []int64) error
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
+	SettleHold(ctx context.Context, arg SettleHoldParams) (Hold, error)
+	SettleTransferBatchItem(ctx context.Context, arg SettleTransferBatchItemParams) (TransferBatchItem, error)
+	SumEntriesAfter(ctx context.Context, arg SumEntriesAfterParams) (int64, error)
+	SumUnpostedInterest(ctx context.Context, arg SumUnpostedInterestParams) (int64, error)
 	UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateUserTier(ctx context.Context, arg UpdateUserTierParams) (User, error)
+	UseFxQuote(ctx context.Context, id uuid.UUID) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListBalanceDrift(t *testing.T) {
+	store := NewStore(testDB)
+	funding := createCurrencyAccount(t, currency.USD, 0)
+
+	// opened with a balance and no entry
+	drifted := createRandomAccount(t)
+
+	opened, err := store.OpenAccountTx(context.Background(), OpenAccountTxParam{
+		Owner:            createRandomUser(t).Username,
+		Currency:         currency.USD,
+		Product:          "checking",
+		OpeningBalance:   500,
+		FundingAccountID: funding.ID,
+	})
+	require.NoError(t, err)
+
+	drifts, err := testQueries.ListBalanceDrift(context.Background(), ListBalanceDriftParams{
+		AfterID:  drifted.ID - 1,
+		PageSize: math.MaxInt32,
+	})
+	require.NoError(t, err)
+
+	found := false
+	for _, drift := range drifts {
+		require.NotEqual(t, opened.Account.ID, drift.ID)
+		if drift.ID == drifted.ID {
+			found = true
+			require.Equal(t, drifted.Balance, drift.Balance)
+			require.Zero(t, drift.EntriesTotal)
+		}
+	}
+	require.True(t, found)
+}
+
+func TestListUnbalancedTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createCurrencyAccount(t, currency.USD, 100)
+	account2 := createCurrencyAccount(t, currency.USD, 0)
+
+	// a transfer without entries
+	unbalanced := createRandomTransfer(t, account1, account2)
+
+	balanced, err := store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	transfers, err := testQueries.ListUnbalancedTransfers(context.Background(), ListUnbalancedTransfersParams{
+		AfterID:  unbalanced.ID - 1,
+		PageSize: math.MaxInt32,
+	})
+	require.NoError(t, err)
+
+	found := false
+	for _, transfer := range transfers {
+		require.NotEqual(t, balanced.Transfer.ID, transfer.ID)
+		if transfer.ID == unbalanced.ID {
+			found = true
+			require.Zero(t, transfer.DebitEntries)
+			require.Zero(t, transfer.CreditEntries)
+			require.True(t, transfer.ZeroSum)
+		}
+	}
+	require.True(t, found)
+}
+
+func TestListCurrencyTotals(t *testing.T) {
+	house := createCurrencyAccount(t, currency.USD, 0)
+
+	totals, err := testQueries.ListCurrencyTotals(context.Background(), []int64{house.ID})
+	require.NoError(t, err)
+
+	found := false
+	for _, total := range totals {
+		if total.Currency == currency.USD {
+			found = true
+			require.Equal(t, house.Balance, total.HouseTotal)
+		}
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+)
+
+type ReverseTransferTxParam struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount to refund, zero reverses whatever has not been reversed yet
+	Amount      int64  `json:"amount"`
+	Reason      string `json:"reason"`
+	InitiatedBy string `json:"initiated_by"`
+	// FrozenAcceptsCredits lets the reversal pay back into a frozen account, see TransferTxParam
+	FrozenAcceptsCredits bool `json:"-"`
+	// Audit builds the audit event of the reversal, appended in the same transaction. nil audits nothing.
+	Audit func(result ReverseTransferTxResult) (audit.Event, error) `json:"-"`
+}
+
+type ReverseTransferTxResult struct {
+	Reversal         TransferReversal `json:"reversal"`
+	OriginalTransfer Transfer         `json:"original_transfer"`
+	RemainingAmount  int64            `json:"remaining_amount"`
+	TransferTxResult
+}
+
+// ReverseTransfer moves money of an earlier transfer back to its sender with a compensating transfer.
+// Several partial reversals are allowed as long as their sum doesn't exceed the original amount.
+func (store *SQLStore) ReverseTransfer(ctx context.Context, arg ReverseTransferTxParam) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+	var err error
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		// Locking the original transfer serializes concurrent reversals of it
+		result.OriginalTransfer, err = q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if result.OriginalTransfer.ExchangeRate.Valid {
+			return api_error.ErrFxReversalUnsupported
+		}
+
+		reversed, err := q.GetReversedAmount(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		remaining := result.OriginalTransfer.Amount - reversed
+		if remaining <= 0 {
+			return api_error.ErrTransferFullyReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+
+		if amount > remaining {
+			return api_error.ErrReversalExceedsRemaining(amount, remaining)
+		}
+
+		result.TransferTxResult, err = transfer(ctx, q, TransferTxParam{
+			FromAccountID:        result.OriginalTransfer.ToAccountID,
+			ToAccountID:          result.OriginalTransfer.FromAccountID,
+			Amount:               amount,
+			FrozenAcceptsCredits: arg.FrozenAcceptsCredits,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		result.Reversal, err = q.CreateTransferReversal(ctx, CreateTransferReversalParams{
+			TransferID:         arg.TransferID,
+			ReversalTransferID: result.Transfer.ID,
+			Amount:             amount,
+			Reason:             arg.Reason,
+			InitiatedBy:        arg.InitiatedBy,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		// an event of the original transfer, so it's published after the transfer.created of it
+		err = appendOutbox(ctx, q, outbox.AggregateTransfer, strconv.FormatInt(arg.TransferID, 10), outbox.EventTransferReversed, result.Reversal)
+		if err != nil {
+			return err
+		}
+
+		result.RemainingAmount = remaining - amount
+		if arg.Audit == nil {
+			return nil
+		}
+
+		event, err := arg.Audit(result)
+		return appendAudit(ctx, q, event, err)
+	})
+
+	return result, err
+}
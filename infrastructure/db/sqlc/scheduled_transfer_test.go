@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, nextRunAt time.Time) ScheduledTransfer {
+	account1, account2 := createRandomAccount(t), createRandomAccount(t)
+
+	arg := CreateScheduledTransferParams{
+		Owner:               account1.Owner,
+		FromAccountID:       account1.ID,
+		ToAccountID:         account2.ID,
+		Amount:              10,
+		Frequency:           "monthly",
+		StartAt:             nextRunAt,
+		NextRunAt:           nextRunAt,
+		OnInsufficientFunds: "skip",
+		MaxRetries:          3,
+	}
+
+	schedule, err := testQueries.CreateScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, schedule.ID)
+	require.Equal(t, arg.Owner, schedule.Owner)
+	require.Equal(t, arg.Amount, schedule.Amount)
+	require.Equal(t, "active", schedule.Status)
+	require.Zero(t, schedule.Occurrences)
+	require.False(t, schedule.LockedUntil.Valid)
+
+	return schedule
+}
+
+func TestClaimDueScheduledTransfers(t *testing.T) {
+	now := time.Now()
+	due := createRandomScheduledTransfer(t, now.Add(-time.Minute))
+	notDue := createRandomScheduledTransfer(t, now.Add(time.Hour))
+
+	claimed, err := testQueries.ClaimDueScheduledTransfers(context.Background(), ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+
+	ids := map[int64]bool{}
+	for _, schedule := range claimed {
+		ids[schedule.ID] = true
+		require.True(t, schedule.LockedUntil.Valid)
+	}
+	require.True(t, ids[due.ID])
+	require.False(t, ids[notDue.ID])
+
+	// A claimed schedule isn't handed out again until its lock expires
+	claimed, err = testQueries.ClaimDueScheduledTransfers(context.Background(), ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	for _, schedule := range claimed {
+		require.NotEqual(t, due.ID, schedule.ID)
+	}
+}
+
+func TestScheduledTransferRunTx(t *testing.T) {
+	store := NewStore(testDB)
+	schedule := createRandomScheduledTransfer(t, time.Now())
+	nextRunAt := schedule.NextRunAt.AddDate(0, 1, 0)
+
+	result, err := store.ScheduledTransferRunTx(context.Background(), ScheduledTransferRunTxParam{
+		Run: CreateScheduledTransferRunParams{
+			ScheduledTransferID: schedule.ID,
+			Occurrence:          0,
+			Attempt:             1,
+			Status:              "skipped",
+			Error:               sql.NullString{String: "insufficient funds", Valid: true},
+		},
+		Advance: AdvanceScheduledTransferParams{
+			ID:          schedule.ID,
+			NextRunAt:   nextRunAt,
+			Occurrences: 1,
+			Status:      "active",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.Schedule.Occurrences)
+	require.WithinDuration(t, nextRunAt, result.Schedule.NextRunAt, time.Second)
+	require.True(t, result.Schedule.LastRunAt.Valid)
+	require.False(t, result.Schedule.LockedUntil.Valid)
+
+	runs, err := store.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: schedule.ID,
+		PageSize:            10,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, result.Run.ID, runs[0].ID)
+
+	// A schedule paused meanwhile keeps its status
+	_, err = store.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+		ID:     schedule.ID,
+		Status: sql.NullString{String: "paused", Valid: true},
+	})
+	require.NoError(t, err)
+
+	result, err = store.ScheduledTransferRunTx(context.Background(), ScheduledTransferRunTxParam{
+		Run: CreateScheduledTransferRunParams{ScheduledTransferID: schedule.ID, Occurrence: 1, Attempt: 1, Status: "succeeded"},
+		Advance: AdvanceScheduledTransferParams{
+			ID:          schedule.ID,
+			NextRunAt:   nextRunAt.AddDate(0, 1, 0),
+			Occurrences: 2,
+			Status:      "completed",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "paused", result.Schedule.Status)
+}
@@ -0,0 +1,33 @@
+package db
+
+import "context"
+
+type ScheduledTransferRunTxParam struct {
+	Run     CreateScheduledTransferRunParams
+	Advance AdvanceScheduledTransferParams
+}
+
+type ScheduledTransferRunTxResult struct {
+	Run      ScheduledTransferRun `json:"run"`
+	Schedule ScheduledTransfer    `json:"schedule"`
+}
+
+// ScheduledTransferRunTx records the outcome of a schedule execution and moves the schedule to its next run,
+// releasing the lock taken by ClaimDueScheduledTransfers.
+func (store *SQLStore) ScheduledTransferRunTx(ctx context.Context, arg ScheduledTransferRunTxParam) (ScheduledTransferRunTxResult, error) {
+	var result ScheduledTransferRunTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, arg.Run)
+		if err != nil {
+			return err
+		}
+
+		result.Schedule, err = q.AdvanceScheduledTransfer(ctx, arg.Advance)
+		return err
+	})
+
+	return result, err
+}
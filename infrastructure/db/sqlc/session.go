@@ -0,0 +1,36 @@
+package db
+
+import (
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+)
+
+// CheckActive reports why tokens issued for the session can't be used anymore, nil means the session is active
+func (session Session) CheckActive(username string) error {
+	switch {
+	case session.IsBlocked:
+		return api_error.ErrSessionRevoked
+	case time.Now().After(session.ExpiresAt):
+		return api_error.ErrSessionExpired
+	case session.Username != username:
+		return api_error.ErrSessionNotFound
+	default:
+		return nil
+	}
+}
+
+// CheckRefresh reports why refreshToken of username can't be exchanged for new tokens of the session, nil means it
+// can. A consumed session is left to RotateSessionTx so that reuse revokes the whole family.
+func (session Session) CheckRefresh(username, refreshToken string) error {
+	switch {
+	case session.IsBlocked:
+		return api_error.ErrBlockedRefreshToken
+	case time.Now().After(session.ExpiresAt):
+		return api_error.ErrExpiredRefreshToken
+	case session.Username != username, session.RefreshToken != refreshToken:
+		return api_error.ErrMismatchedRefreshTokens
+	default:
+		return nil
+	}
+}
@@ -12,21 +12,102 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockOtherSessions = `-- name: BlockOtherSessions :one
+WITH blocked AS (
+  UPDATE "sessions"
+  SET is_blocked = true
+  WHERE username = $1
+    AND family_id <> (
+      SELECT family_id
+      FROM "sessions"
+      WHERE id = $2
+    )
+    AND NOT is_blocked
+  RETURNING family_id
+)
+SELECT count(DISTINCT family_id)
+FROM blocked
+`
+
+type BlockOtherSessionsParams struct {
+	Username string    `json:"username"`
+	KeepID   uuid.UUID `json:"keep_id"`
+}
+
+func (q *Queries) BlockOtherSessions(ctx context.Context, arg BlockOtherSessionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, blockOtherSessions, arg.Username, arg.KeepID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const blockSession = `-- name: BlockSession :execrows
+UPDATE "sessions"
+SET is_blocked = true
+WHERE username = $2
+  AND family_id = (
+    SELECT family_id
+    FROM "sessions"
+    WHERE id = $1
+  )
+`
+
+type BlockSessionParams struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+func (q *Queries) BlockSession(ctx context.Context, arg BlockSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, blockSession, arg.ID, arg.Username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const consumeSession = `-- name: ConsumeSession :one
+UPDATE "sessions"
+SET consumed_at = now()
+WHERE id = $1
+  AND consumed_at IS NULL
+RETURNING id, username, refresh_token, is_blocked, user_agent, client_ip, expires_at, created_at, family_id, consumed_at
+`
+
+func (q *Queries) ConsumeSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, consumeSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.IsBlocked,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ConsumedAt,
+	)
+	return i, err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO "sessions" (
     id,
+    family_id,
     username,
     refresh_token,
     user_agent,
     client_ip,
     expires_at
   )
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, username, refresh_token, is_blocked, user_agent, client_ip, expires_at, created_at
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, username, refresh_token, is_blocked, user_agent, client_ip, expires_at, created_at, family_id, consumed_at
 `
 
 type CreateSessionParams struct {
 	ID           uuid.UUID `json:"id"`
+	FamilyID     uuid.UUID `json:"family_id"`
 	Username     string    `json:"username"`
 	RefreshToken string    `json:"refresh_token"`
 	UserAgent    string    `json:"user_agent"`
@@ -37,6 +118,7 @@ type CreateSessionParams struct {
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
 	row := q.db.QueryRowContext(ctx, createSession,
 		arg.ID,
+		arg.FamilyID,
 		arg.Username,
 		arg.RefreshToken,
 		arg.UserAgent,
@@ -53,12 +135,14 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.ClientIp,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ConsumedAt,
 	)
 	return i, err
 }
 
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, is_blocked, user_agent, client_ip, expires_at, created_at
+SELECT id, username, refresh_token, is_blocked, user_agent, client_ip, expires_at, created_at, family_id, consumed_at
 FROM "sessions"
 WHERE id = $1
 LIMIT 1
@@ -76,6 +160,52 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.ClientIp,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ConsumedAt,
 	)
 	return i, err
 }
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, username, refresh_token, is_blocked, user_agent, client_ip, expires_at, created_at, family_id, consumed_at
+FROM "sessions"
+WHERE username = $1
+  AND NOT is_blocked
+  AND consumed_at IS NULL
+  AND expires_at > now()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListActiveSessions(ctx context.Context, username string) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.IsBlocked,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.ConsumedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/random"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, username string, expiresAt time.Time) Session {
+	id := uuid.New()
+	arg := CreateSessionParams{
+		ID:           id,
+		FamilyID:     id,
+		Username:     username,
+		RefreshToken: random.String(32),
+		UserAgent:    random.String(10),
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    expiresAt,
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.Equal(t, arg.Username, session.Username)
+	require.False(t, session.IsBlocked)
+	require.False(t, session.ConsumedAt.Valid)
+
+	return session
+}
+
+func TestListActiveSessions(t *testing.T) {
+	user := createRandomUser(t)
+
+	active1 := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	active2 := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	createRandomSession(t, user.Username, time.Now().Add(-time.Minute))
+	blocked := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+
+	_, err := testQueries.BlockSession(context.Background(), BlockSessionParams{ID: blocked.ID, Username: user.Username})
+	require.NoError(t, err)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, active2.ID, sessions[0].ID)
+	require.Equal(t, active1.ID, sessions[1].ID)
+}
+
+func TestBlockSession(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2 := createRandomUser(t)
+	session := createRandomSession(t, user1.Username, time.Now().Add(time.Hour))
+
+	// another user can't revoke the session
+	revoked, err := testQueries.BlockSession(context.Background(), BlockSessionParams{ID: session.ID, Username: user2.Username})
+	require.NoError(t, err)
+	require.Zero(t, revoked)
+
+	revoked, err = testQueries.BlockSession(context.Background(), BlockSessionParams{ID: session.ID, Username: user1.Username})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, revoked)
+
+	blocked, err := testQueries.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, blocked.IsBlocked)
+	require.ErrorIs(t, blocked.CheckActive(user1.Username), api_error.ErrSessionRevoked)
+}
+
+func TestBlockOtherSessions(t *testing.T) {
+	user := createRandomUser(t)
+	current := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+
+	revoked, err := testQueries.BlockOtherSessions(context.Background(), BlockOtherSessionsParams{
+		Username: user.Username,
+		KeepID:   current.ID,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, revoked)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, current.ID, sessions[0].ID)
+	require.NoError(t, sessions[0].CheckActive(user.Username))
+}
+
+func rotateSession(t *testing.T, session Session) (Session, error) {
+	return NewStore(testDB).RotateSessionTx(context.Background(), RotateSessionTxParam{
+		ConsumedID: session.ID,
+		Username:   session.Username,
+		Session: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     session.Username,
+			RefreshToken: random.String(32),
+			UserAgent:    session.UserAgent,
+			ClientIp:     session.ClientIp,
+			ExpiresAt:    time.Now().Add(time.Hour),
+		},
+	})
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+
+	session2, err := rotateSession(t, session1)
+	require.NoError(t, err)
+	require.NotEqual(t, session1.ID, session2.ID)
+	require.Equal(t, session1.FamilyID, session2.FamilyID)
+
+	consumed, err := testQueries.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, consumed.ConsumedAt.Valid)
+
+	// only the latest session of the family is listed
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, session2.ID, sessions[0].ID)
+}
+
+func TestRotateSessionTxReuse(t *testing.T) {
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	other := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+
+	session2, err := rotateSession(t, session1)
+	require.NoError(t, err)
+
+	// presenting the consumed refresh token again revokes the whole family
+	_, err = rotateSession(t, session1)
+	require.ErrorIs(t, err, api_error.ErrRefreshTokenReused)
+
+	for _, id := range []uuid.UUID{session1.ID, session2.ID} {
+		session, err := testQueries.GetSession(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, session.IsBlocked)
+	}
+
+	// sessions of other logins are left alone
+	session, err := testQueries.GetSession(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsBlocked)
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+)
+
+type RotateSessionTxParam struct {
+	// ConsumedID is the session of the refresh token being exchanged
+	ConsumedID uuid.UUID `json:"consumed_id"`
+	Username   string    `json:"username"`
+	// Session is the session of the new refresh token, its family is taken from the consumed session
+	Session CreateSessionParams `json:"session"`
+}
+
+// RotateSessionTx marks the session of a refresh token as consumed and creates the session of its replacement in the
+// same family. A session that was already consumed means the refresh token is presented a second time, the whole
+// family is revoked and api_error.ErrRefreshTokenReused is returned.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParam) (Session, error) {
+	var session Session
+	var reused bool
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// The conditional update lets only one of concurrent renewals with the same refresh token through
+		consumed, err := q.ConsumeSession(ctx, arg.ConsumedID)
+		if errors.Is(err, sql.ErrNoRows) {
+			reused = true
+			_, err = q.BlockSession(ctx, BlockSessionParams{ID: arg.ConsumedID, Username: arg.Username})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		params := arg.Session
+		params.FamilyID = consumed.FamilyID
+		session, err = q.CreateSession(ctx, params)
+		return err
+	})
+	if err != nil {
+		return Session{}, err
+	}
+
+	if reused {
+		return Session{}, api_error.ErrRefreshTokenReused
+	}
+
+	return session, nil
+}
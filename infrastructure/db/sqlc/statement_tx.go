@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type StatementTxParam struct {
+	AccountID int64
+	From      time.Time
+	To        time.Time
+	// AfterCreatedAt and AfterID point at the last entry of the previous page, a null AfterID starts at From
+	AfterCreatedAt sql.NullTime
+	AfterID        sql.NullInt64
+	// PageSize entries are read at most, callers read one more than the page to detect a next page
+	PageSize int32
+}
+
+type StatementEntry struct {
+	ListStatementEntriesRow
+	// Balance of the account right after the entry was posted
+	Balance int64 `json:"balance"`
+}
+
+type StatementTxResult struct {
+	Account        Account          `json:"account"`
+	OpeningBalance int64            `json:"opening_balance"`
+	ClosingBalance int64            `json:"closing_balance"`
+	Entries        []StatementEntry `json:"entries"`
+}
+
+// StatementTx reads one page of an account statement for the period [From, To). Balances are derived backwards
+// from the current balance, so everything is read from a single repeatable read snapshot to keep the opening
+// balance, the running balances and the closing balance consistent with each other.
+func (store *SQLStore) StatementTx(ctx context.Context, arg StatementTxParam) (StatementTxResult, error) {
+	var result StatementTxResult
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := store.execTxOptions(ctx, opts, func(q *Queries) error {
+		var err error
+
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		totals, err := q.GetStatementTotals(ctx, GetStatementTotalsParams{
+			FromTime:  arg.From,
+			ToTime:    arg.To,
+			AccountID: arg.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.OpeningBalance = result.Account.Balance - totals.SinceFrom
+		result.ClosingBalance = result.Account.Balance - totals.SinceTo
+
+		balance := result.OpeningBalance
+		after, afterID := arg.From, int64(0)
+		if arg.AfterID.Valid {
+			after, afterID = arg.AfterCreatedAt.Time, arg.AfterID.Int64
+
+			later, err := q.SumEntriesAfter(ctx, SumEntriesAfterParams{
+				AccountID:      arg.AccountID,
+				AfterCreatedAt: after,
+				AfterID:        afterID,
+			})
+			if err != nil {
+				return err
+			}
+
+			balance = result.Account.Balance - later
+		}
+
+		rows, err := q.ListStatementEntries(ctx, ListStatementEntriesParams{
+			AccountID:      arg.AccountID,
+			FromTime:       arg.From,
+			ToTime:         arg.To,
+			AfterCreatedAt: after,
+			AfterID:        afterID,
+			PageSize:       arg.PageSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entries = make([]StatementEntry, 0, len(rows))
+		for _, row := range rows {
+			balance += row.Amount
+			result.Entries = append(result.Entries, StatementEntry{ListStatementEntriesRow: row, Balance: balance})
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createFundedAccount(t, 1000)
+	account2 := createFundedAccount(t, 1000)
+
+	transfers := []struct {
+		from, to Account
+		amount   int64
+	}{
+		{account1, account2, 10},
+		{account1, account2, 20},
+		{account2, account1, 5},
+		{account1, account2, 30},
+	}
+
+	for _, transfer := range transfers {
+		_, err := store.TransferTx(context.Background(), TransferTxParam{
+			FromAccountID: transfer.from.ID,
+			ToAccountID:   transfer.to.ID,
+			Amount:        transfer.amount,
+		})
+		require.NoError(t, err)
+	}
+
+	from, to := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	arg := StatementTxParam{AccountID: account1.ID, From: from, To: to, PageSize: 3}
+
+	page1, err := store.StatementTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1.Entries, 3)
+	require.Equal(t, account1.Balance, page1.OpeningBalance)
+	require.Equal(t, account1.Balance-10-20+5-30, page1.ClosingBalance)
+
+	balance := page1.OpeningBalance
+	for i, entry := range page1.Entries {
+		balance += entry.Amount
+		require.Equal(t, balance, entry.Balance)
+		require.True(t, entry.TransferID.Valid)
+		require.Equal(t, account2.ID, entry.CounterpartyAccountID.Int64)
+		require.Equal(t, account2.Owner, entry.CounterpartyOwner.String)
+
+		if i > 0 {
+			require.False(t, entry.CreatedAt.Before(page1.Entries[i-1].CreatedAt))
+		}
+	}
+	require.Equal(t, []int64{-10, -20, 5}, []int64{page1.Entries[0].Amount, page1.Entries[1].Amount, page1.Entries[2].Amount})
+
+	last := page1.Entries[len(page1.Entries)-1]
+	arg.AfterCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+	arg.AfterID = sql.NullInt64{Int64: last.ID, Valid: true}
+
+	page2, err := store.StatementTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2.Entries, 1)
+	require.Equal(t, int64(-30), page2.Entries[0].Amount)
+	require.Equal(t, last.Balance-30, page2.Entries[0].Balance)
+	require.Equal(t, page2.ClosingBalance, page2.Entries[0].Balance)
+
+	// a period without entries opens and closes at the same balance
+	empty, err := store.StatementTx(context.Background(), StatementTxParam{
+		AccountID: account1.ID,
+		From:      from.Add(-time.Hour),
+		To:        from,
+		PageSize:  3,
+	})
+	require.NoError(t, err)
+	require.Empty(t, empty.Entries)
+	require.Equal(t, account1.Balance, empty.OpeningBalance)
+	require.Equal(t, account1.Balance, empty.ClosingBalance)
+}
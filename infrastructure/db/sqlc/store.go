@@ -2,13 +2,44 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
 )
 
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParam) (TransferTxResult, error)
+	ReverseTransfer(ctx context.Context, arg ReverseTransferTxParam) (ReverseTransferTxResult, error)
+	ScheduledTransferRunTx(ctx context.Context, arg ScheduledTransferRunTxParam) (ScheduledTransferRunTxResult, error)
+	FxTransferTx(ctx context.Context, arg FxTransferTxParam) (TransferTxResult, error)
+	LoadFxRatesTx(ctx context.Context, rates []CreateFxRateParams) ([]FxRate, error)
+	StatementTx(ctx context.Context, arg StatementTxParam) (StatementTxResult, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParam) (Session, error)
+	ChangeAccountStatusTx(ctx context.Context, arg ChangeAccountStatusTxParam) (ChangeAccountStatusTxResult, error)
+	CreateHoldTx(ctx context.Context, arg CreateHoldTxParam) (CreateHoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParam) (CaptureHoldTxResult, error)
+	ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParam) (Hold, error)
+	TransferBatchTx(ctx context.Context, arg TransferBatchTxParam) (TransferBatchTxResult, error)
+	SettleTransferBatchTx(ctx context.Context, arg TransferBatchTxParam, batch TransferBatchTxResult) (TransferBatchTxResult, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParam) (PostInterestTxResult, error)
+	OpenAccountTx(ctx context.Context, arg OpenAccountTxParam) (OpenAccountTxResult, error)
+	AppendAuditEventTx(ctx context.Context, event audit.Event) (AuditEvent, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParam) (User, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParam) (User, error)
+	SetUserRoleTx(ctx context.Context, arg SetUserRoleTxParam) (User, error)
+	SetUserTierTx(ctx context.Context, arg SetUserTierTxParam) (User, error)
+	RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParam) (RelayOutboxTxResult, error)
 }
 
 type SQLStore struct {
@@ -24,10 +55,14 @@ func NewStore(db *sql.DB) Store {
 }
 
 func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+	return store.execTxOptions(ctx, nil, fn)
+}
+
+func (store *SQLStore) execTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, opts)
 
 	if err != nil {
-		return nil
+		return err
 	}
 
 	err = fn(New(tx))
@@ -63,10 +98,64 @@ func transferMoney(ctx context.Context, q *Queries, accountID1, amount1, account
 	return
 }
 
+// houseTransfer pays amount from a house account to an account as a transfer and its two entries. The house
+// account isn't checked for funds, both accounts have to be locked by the running transaction.
+func houseTransfer(ctx context.Context, q *Queries, houseAccountID, accountID, amount int64) (transfer Transfer, house, account Account, err error) {
+	transfer, err = insertTransfer(ctx, q, CreateTransferParams{
+		FromAccountID: houseAccountID,
+		ToAccountID:   accountID,
+		Amount:        amount,
+	})
+	if err != nil {
+		return
+	}
+
+	for _, entry := range []CreateEntryParams{
+		{AccountID: houseAccountID, Amount: -amount},
+		{AccountID: accountID, Amount: amount},
+	} {
+		entry.TransferID = sql.NullInt64{Int64: transfer.ID, Valid: true}
+		if _, err = q.CreateEntry(ctx, entry); err != nil {
+			return
+		}
+	}
+
+	if houseAccountID < accountID {
+		house, account, err = transferMoney(ctx, q, houseAccountID, -amount, accountID, amount)
+	} else {
+		account, house, err = transferMoney(ctx, q, accountID, amount, houseAccountID, -amount)
+	}
+	return
+}
+
 type TransferTxParam struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+
+	// IdempotencyKey is optional, when set the transfer is executed at most once per Username and key.
+	Username       string `json:"-"`
+	IdempotencyKey string `json:"-"`
+
+	// Fee is charged to the source account on top of Amount and credited to Fee.RevenueAccountID. It is left out of
+	// the idempotency request hash, a retry is charged the fee of the first request.
+	Fee fee.Breakdown `json:"-"`
+
+	// FrozenAcceptsCredits lets the transfer pay into a frozen account, frozen, dormant and closed accounts are never
+	// debited. The statuses are checked while the accounts are locked.
+	FrozenAcceptsCredits bool `json:"-"`
+
+	// Limits bounds the transfer by the limits of the tier of the owner of the source account, nil limits nothing.
+	// The usage is counted while the account is locked.
+	Limits *limit.Policy `json:"-"`
+
+	// Audit builds the audit event of the transfer, appended in the same transaction unless the result is replayed.
+	// It's only called by TransferTx and FxTransferTx, nil audits nothing.
+	Audit func(result TransferTxResult) (audit.Event, error) `json:"-"`
+
+	// debitAuthorized skips the status check of the source account, for debits authorized before its status
+	// changed: the capture of a hold and the sweep of an account being closed
+	debitAuthorized bool
 }
 
 type TransferTxResult struct {
@@ -75,49 +164,353 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// HouseEntries are the entries on the bank's own accounts, set for transfers between currencies and for fees
+	HouseEntries []Entry `json:"house_entries,omitempty"`
+	// Fee is the fee charged for the transfer, nil when it was free
+	Fee *fee.Breakdown `json:"fee,omitempty"`
+
+	// Replayed is true when the result was loaded from a previous request with the same idempotency key.
+	Replayed bool `json:"-"`
 }
 
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParam) (TransferTxResult, error) {
+	return store.idempotentTransferTx(ctx, arg.Username, arg.IdempotencyKey, arg, arg.Audit, func(q *Queries) (TransferTxResult, error) {
+		return transfer(ctx, q, arg)
+	})
+}
+
+// idempotentTransferTx runs fn in a transaction. When key is set, fn runs at most once per username and key,
+// request is hashed to detect the key being reused for a different request. The event built by auditFn is appended
+// when fn ran.
+func (store *SQLStore) idempotentTransferTx(ctx context.Context, username, key string, request interface{},
+	auditFn func(result TransferTxResult) (audit.Event, error), fn func(q *Queries) (TransferTxResult, error)) (TransferTxResult, error) {
 	var results TransferTxResult
 	var err error
 
 	err = store.execTx(ctx, func(q *Queries) error {
-		results.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+		if key != "" {
+			replayed, err := claimIdempotencyKey(ctx, q, username, key, request, &results)
+			if err != nil || replayed {
+				return err
+			}
+		}
 
+		results, err = fn(q)
 		if err != nil {
 			return err
 		}
 
-		results.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+		if auditFn != nil {
+			event, err := auditFn(results)
+			if err = appendAudit(ctx, q, event, err); err != nil {
+				return err
+			}
+		}
+
+		if key != "" {
+			return saveIdempotencyKeyResponse(ctx, q, username, key, results)
+		}
+
+		return nil
+	})
+
+	return results, err
+}
+
+// transfer moves the money of a single transfer inside an already running transaction
+func transfer(ctx context.Context, q *Queries, arg TransferTxParam) (result TransferTxResult, err error) {
+	// Lock both accounts in a consistent order before checking the balance so that
+	// concurrent transfers between the same accounts can't deadlock or overdraw.
+	locked, err := lockAccounts(ctx, q, feeAccountIDs(arg.Fee, arg.FromAccountID, arg.ToAccountID)...)
+	if err != nil {
+		return result, err
+	}
+
+	fromAccount, toAccount := locked[arg.FromAccountID], locked[arg.ToAccountID]
+	if fromAccount.Currency != toAccount.Currency {
+		return result, api_error.ErrCurrencyMismatch(fromAccount.Currency, toAccount.Currency)
+	}
+
+	if err = checkTransferStatus(fromAccount, toAccount, arg); err != nil {
+		return result, err
+	}
+
+	if err = checkTransferLimits(ctx, q, arg.Limits, fromAccount, arg.Amount); err != nil {
+		return result, err
+	}
+
+	if err = checkFee(arg.Fee, fromAccount, locked); err != nil {
+		return result, err
+	}
+
+	debit := arg.Amount + arg.Fee.Amount
+	if err = CheckSufficientFunds(fromAccount, debit); err != nil {
+		return result, err
+	}
+
+	result.Transfer, err = insertTransfer(ctx, q, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Fee:           arg.Fee.Amount,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.FromAccountID,
+		Amount:     -debit,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.ToAccountID,
+		Amount:     arg.Amount,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = transferMoney(ctx, q, arg.FromAccountID, -debit, arg.ToAccountID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = transferMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -debit)
+	}
+
+	if err != nil {
+		return result, err
+	}
 
+	if arg.Fee.Amount > 0 {
+		entry, err := postFee(ctx, q, result.Transfer.ID, arg.Fee)
 		if err != nil {
+			return result, err
+		}
+
+		result.HouseEntries = append(result.HouseEntries, entry)
+		result.Fee = &arg.Fee
+	}
+
+	return result, nil
+}
+
+// checkTransferStatus checks that the locked accounts of a transfer can be debited and credited. A status change
+// waits for the lock, so it can't slip in between the check and the transfer.
+func checkTransferStatus(from, to Account, arg TransferTxParam) error {
+	if !arg.debitAuthorized {
+		if err := from.CheckDebit(); err != nil {
 			return err
 		}
+	}
 
-		results.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	return to.CheckCredit(arg.FrozenAcceptsCredits)
+}
 
+// checkTransferLimits checks that a transfer of amount from the locked account stays within the limits of the tier of
+// its owner. Concurrent transfers and holds from the account wait for the lock, so they can't spend the same
+// allowance twice.
+func checkTransferLimits(ctx context.Context, q *Queries, policy *limit.Policy, from Account, amount int64) error {
+	if policy == nil {
+		return nil
+	}
+
+	owner, err := q.GetUser(ctx, from.Owner)
+	if err != nil {
+		return err
+	}
+
+	limits, ok := policy.Lookup(owner.Tier, from.Currency)
+	if !ok {
+		return nil
+	}
+
+	windows := limit.WindowsAt(time.Now())
+	usage, err := q.GetTransferUsage(ctx, GetTransferUsageParams{
+		DayStart:   windows.DayStart,
+		HourStart:  windows.HourStart,
+		AccountID:  from.ID,
+		MonthStart: windows.MonthStart,
+	})
+	if err != nil {
+		return err
+	}
+
+	return limits.Check(limit.Usage{
+		DailyTotal:   usage.DailyTotal,
+		MonthlyTotal: usage.MonthlyTotal,
+		HourlyCount:  usage.HourlyCount,
+		HourlyOldest: usage.HourlyOldest.Time,
+	}, amount, windows)
+}
+
+// feeAccountIDs adds the revenue account of a charged fee to the accounts a transfer has to lock
+func feeAccountIDs(charged fee.Breakdown, accountIDs ...int64) []int64 {
+	if charged.Amount > 0 {
+		return append(accountIDs, charged.RevenueAccountID)
+	}
+	return accountIDs
+}
+
+// checkFee checks that the revenue account of a charged fee is locked and takes fees in the currency of the source
+// account, the fee is always charged in that currency
+func checkFee(charged fee.Breakdown, from Account, locked map[int64]Account) error {
+	if charged.Amount <= 0 {
+		return nil
+	}
+
+	revenue, ok := locked[charged.RevenueAccountID]
+	if !ok {
+		return api_error.ErrFeeRevenueAccountMissing(from.Currency)
+	}
+
+	if revenue.Currency != from.Currency {
+		return api_error.ErrCurrencyMismatch(from.Currency, revenue.Currency)
+	}
+
+	return nil
+}
+
+// postFee credits a fee to its revenue account, the source account is debited with the transfer
+func postFee(ctx context.Context, q *Queries, transferID int64, charged fee.Breakdown) (Entry, error) {
+	entry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  charged.RevenueAccountID,
+		Amount:     charged.Amount,
+		TransferID: sql.NullInt64{Int64: transferID, Valid: true},
+	})
+	if err != nil {
+		return entry, err
+	}
+
+	_, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{ID: charged.RevenueAccountID, Amount: charged.Amount})
+	return entry, err
+}
+
+// CheckSufficientFunds : check that the available balance of the account, its balance less the money on hold, can be
+// debited by amount without going past its overdraft limit
+func CheckSufficientFunds(account Account, amount int64) error {
+	if account.AvailableBalance()-amount < -account.OverdraftLimit {
+		return &api_error.InsufficientFundsError{
+			AccountID:      account.ID,
+			Balance:        account.Balance,
+			HeldAmount:     account.HeldAmount,
+			OverdraftLimit: account.OverdraftLimit,
+			Amount:         amount,
+		}
+	}
+	return nil
+}
+
+// AvailableBalance is the balance that can be spent, money on hold is reserved for the capture of its hold
+func (account Account) AvailableBalance() int64 {
+	return account.Balance - account.HeldAmount
+}
+
+// lockAccounts takes a row lock on the accounts, lowest id first so that concurrent transactions
+// locking overlapping accounts can't deadlock, and returns the locked accounts by id.
+func lockAccounts(ctx context.Context, q *Queries, accountIDs ...int64) (map[int64]Account, error) {
+	ids := make([]int64, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if !containsID(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	locked := make(map[int64]Account, len(ids))
+	for _, id := range ids {
+		account, err := q.GetAccountForUpdate(ctx, id)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		locked[id] = account
+	}
+
+	return locked, nil
+}
 
-		if arg.FromAccountID < arg.ToAccountID {
-			results.FromAccount, results.ToAccount, err = transferMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			results.ToAccount, results.FromAccount, err = transferMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+func containsID(ids []int64, id int64) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
 		}
+	}
+	return false
+}
 
-		return nil
+// claimIdempotencyKey inserts the key for the current transaction. When the key was already used, the stored
+// result is loaded into results and replayed is true. Concurrent requests with the same key block on the insert
+// until the first transaction finishes.
+func claimIdempotencyKey(ctx context.Context, q *Queries, username, key string, request interface{}, results *TransferTxResult) (replayed bool, err error) {
+	requestHash, err := hashTransferRequest(request)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username:    username,
+		Key:         key,
+		RequestHash: requestHash,
 	})
 
-	return results, err
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	stored, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: username,
+		Key:      key,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	if stored.RequestHash != requestHash {
+		return false, api_error.ErrIdempotencyKeyConflict
+	}
+
+	if err = json.Unmarshal(stored.Response, results); err != nil {
+		return false, err
+	}
+
+	results.Replayed = true
+	return true, nil
+}
+
+func saveIdempotencyKeyResponse(ctx context.Context, q *Queries, username, key string, results TransferTxResult) error {
+	response, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+		TransferID: sql.NullInt64{Int64: results.Transfer.ID, Valid: true},
+		Response:   response,
+		Username:   username,
+		Key:        key,
+	})
+
+	return err
+}
+
+func hashTransferRequest(request interface{}) (string, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
 }
@@ -3,14 +3,46 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
+	"github.com/dhiemaz/bank-api/utils/random"
 	"github.com/stretchr/testify/require"
 )
 
+// createFundedAccount creates a USD account of a random user holding at least balance
+func createFundedAccount(t *testing.T, balance int64) Account {
+	return createCurrencyAccount(t, currency.USD, balance)
+}
+
+// createCurrencyAccount creates an account of a random user in the currency of code holding at least balance
+func createCurrencyAccount(t *testing.T, code string, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  random.Money(),
+		Currency: code,
+		Product:  "checking",
+	})
+	require.NoError(t, err)
+
+	account, err = testQueries.UpdateAccountBalance(context.Background(), UpdateAccountBalanceParams{
+		ID:     account.ID,
+		Amount: balance,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1, account2 := createRandomAccount(t), createRandomAccount(t)
+	account1, account2 := createFundedAccount(t, 1000), createFundedAccount(t, 1000)
 	amount := int64(10)
 
 	results, errs := make(chan TransferTxResult), make(chan error)
@@ -101,7 +133,7 @@ func TestTransferTx(t *testing.T) {
 func TestTransferTxDeadLock(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1, account2 := createRandomAccount(t), createRandomAccount(t)
+	account1, account2 := createFundedAccount(t, 1000), createFundedAccount(t, 1000)
 	amount := int64(10)
 
 	errs := make(chan error)
@@ -141,3 +173,266 @@ func TestTransferTxDeadLock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedFromAccount.Balance)
 	require.Equal(t, account2.Balance, updatedToAccount.Balance)
 }
+
+func TestTransferTxIdempotent(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1, account2 := createFundedAccount(t, 1000), createFundedAccount(t, 1000)
+	amount := int64(10)
+
+	arg := TransferTxParam{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         amount,
+		Username:       account1.Owner,
+		IdempotencyKey: random.String(32),
+	}
+
+	first, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, first.Replayed)
+
+	second, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, second.Replayed)
+	require.Equal(t, first.Transfer.ID, second.Transfer.ID)
+	require.Equal(t, first.FromEntry.ID, second.FromEntry.ID)
+	require.Equal(t, first.ToEntry.ID, second.ToEntry.ID)
+
+	// The money must only move once
+	updatedFromAccount, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-amount, updatedFromAccount.Balance)
+
+	// Reusing the key for a different request is a conflict
+	arg.Amount = amount + 1
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, api_error.ErrIdempotencyKeyConflict)
+}
+
+func TestTransferTxIdempotentReplaysSpentBalance(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1, account2 := createFundedAccount(t, 100), createFundedAccount(t, 0)
+
+	// the first attempt spends the whole balance, its retry is replayed instead of being refused
+	arg := TransferTxParam{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         account1.Balance,
+		Username:       account1.Owner,
+		IdempotencyKey: random.String(32),
+	}
+
+	first, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	retry, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, retry.Replayed)
+	require.Equal(t, first.Transfer.ID, retry.Transfer.ID)
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1, account2 := createFundedAccount(t, 100), createFundedAccount(t, 0)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, api_error.ErrInsufficientFunds)
+
+	var fundsErr *api_error.InsufficientFundsError
+	require.ErrorAs(t, err, &fundsErr)
+	require.Equal(t, account1.ID, fundsErr.AccountID)
+	require.Equal(t, account1.Balance, fundsErr.Balance)
+
+	// Nothing must have been written
+	updatedFromAccount, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedFromAccount.Balance)
+
+	// The whole balance can be moved
+	_, err = store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance,
+	})
+	require.NoError(t, err)
+}
+
+func TestTransferTxFee(t *testing.T) {
+	store := NewStore(testDB)
+
+	from, to, revenue := createFundedAccount(t, 0), createFundedAccount(t, 0), createFundedAccount(t, 0)
+	charged := fee.Breakdown{Currency: currency.USD, TransferType: fee.TypeTransfer, Kind: fee.KindFlat, Flat: 3, Amount: 3, RevenueAccountID: revenue.ID}
+
+	result, err := store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        10,
+		Fee:           charged,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10), result.Transfer.Amount)
+	require.Equal(t, int64(3), result.Transfer.Fee)
+	require.Equal(t, int64(-13), result.FromEntry.Amount)
+	require.Equal(t, int64(10), result.ToEntry.Amount)
+	require.Equal(t, from.Balance-13, result.FromAccount.Balance)
+	require.Equal(t, to.Balance+10, result.ToAccount.Balance)
+	require.Equal(t, &charged, result.Fee)
+
+	require.Len(t, result.HouseEntries, 1)
+	require.Equal(t, revenue.ID, result.HouseEntries[0].AccountID)
+	require.Equal(t, int64(3), result.HouseEntries[0].Amount)
+
+	updatedRevenue, err := store.GetAccount(context.Background(), revenue.ID)
+	require.NoError(t, err)
+	require.Equal(t, revenue.Balance+3, updatedRevenue.Balance)
+
+	// the fee counts towards the funds the transfer needs
+	_, err = store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        result.FromAccount.Balance,
+		Fee:           charged,
+	})
+	require.ErrorIs(t, err, api_error.ErrInsufficientFunds)
+
+	// fees are credited in the currency of the source account
+	charged.RevenueAccountID = createCurrencyAccount(t, currency.IDR, 0).ID
+	_, err = store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        1,
+		Fee:           charged,
+	})
+	require.True(t, api_error.IsCurrencyMismatch(err))
+}
+
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+	policy, err := limit.NewPolicy([]limit.Limits{{Tier: limit.DefaultTier, Currency: currency.USD, DailyTotal: 100}})
+	require.NoError(t, err)
+
+	from, to := createFundedAccount(t, 1000), createFundedAccount(t, 0)
+
+	// an active hold counts towards the usage like a transfer
+	hold, err := store.CreateHoldTx(context.Background(), CreateHoldTxParam{
+		CreateHoldParams: CreateHoldParams{
+			AccountID:   from.ID,
+			ToAccountID: to.ID,
+			Amount:      60,
+			CreatedBy:   from.Owner,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+		Limits: policy,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 50, Limits: policy})
+	var exceeded *api_error.LimitExceededError
+	require.ErrorAs(t, err, &exceeded)
+	require.Equal(t, limit.DailyTotal, exceeded.Limit)
+	require.Equal(t, int64(60), exceeded.Used)
+
+	_, err = store.CreateHoldTx(context.Background(), CreateHoldTxParam{
+		CreateHoldParams: CreateHoldParams{
+			AccountID:   from.ID,
+			ToAccountID: to.ID,
+			Amount:      50,
+			CreatedBy:   from.Owner,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+		Limits: policy,
+	})
+	require.ErrorAs(t, err, &exceeded)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 40, Limits: policy})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 10, Limits: policy})
+	require.ErrorAs(t, err, &exceeded)
+
+	// a released hold doesn't count anymore
+	_, err = store.ReleaseHoldTx(context.Background(), ReleaseHoldTxParam{HoldID: hold.Hold.ID, Status: HoldStatusReleased})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 10, Limits: policy})
+	require.NoError(t, err)
+
+	// without a policy nothing is limited
+	_, err = store.TransferTx(context.Background(), TransferTxParam{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 500})
+	require.NoError(t, err)
+}
+
+func TestReverseTransfer(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1, account2 := createFundedAccount(t, 1000), createFundedAccount(t, 1000)
+	user := createRandomUser(t)
+
+	original, err := store.TransferTx(context.Background(), TransferTxParam{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// Partial refund
+	result, err := store.ReverseTransfer(context.Background(), ReverseTransferTxParam{
+		TransferID:  original.Transfer.ID,
+		Amount:      40,
+		Reason:      "partial refund",
+		InitiatedBy: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, original.Transfer.ID, result.Reversal.TransferID)
+	require.Equal(t, result.Transfer.ID, result.Reversal.ReversalTransferID)
+	require.Equal(t, account2.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, result.Transfer.ToAccountID)
+	require.Equal(t, int64(40), result.Transfer.Amount)
+	require.Equal(t, int64(60), result.RemainingAmount)
+
+	// More than what is left
+	_, err = store.ReverseTransfer(context.Background(), ReverseTransferTxParam{
+		TransferID:  original.Transfer.ID,
+		Amount:      61,
+		Reason:      "too much",
+		InitiatedBy: user.Username,
+	})
+	require.True(t, api_error.IsReversalExceedsRemaining(err))
+
+	// Zero amount reverses the rest
+	result, err = store.ReverseTransfer(context.Background(), ReverseTransferTxParam{
+		TransferID:  original.Transfer.ID,
+		Reason:      "full refund",
+		InitiatedBy: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(60), result.Transfer.Amount)
+	require.Zero(t, result.RemainingAmount)
+
+	_, err = store.ReverseTransfer(context.Background(), ReverseTransferTxParam{
+		TransferID:  original.Transfer.ID,
+		Reason:      "again",
+		InitiatedBy: user.Username,
+	})
+	require.ErrorIs(t, err, api_error.ErrTransferFullyReversed)
+
+	reversals, err := store.ListTransferReversals(context.Background(), original.Transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, reversals, 2)
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
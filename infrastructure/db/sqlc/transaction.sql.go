@@ -7,26 +7,41 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    fee
   )
-VALUES ($1, $2, $3)
-RETURNING id, from_account_id, to_account_id, amount, created_at
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, fee
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	Amount        int64          `json:"amount"`
+	ToAmount      sql.NullInt64  `json:"to_amount"`
+	ExchangeRate  sql.NullString `json:"exchange_rate"`
+	Fee           int64          `json:"fee"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.Fee,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +49,15 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.Fee,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, fee
 FROM transfers
 WHERE id = $1
 LIMIT 1
@@ -54,27 +72,169 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.Fee,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, fee
+FROM transfers
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.Fee,
+	)
+	return i, err
+}
+
+const getTransferUsage = `-- name: GetTransferUsage :one
+SELECT COALESCE(SUM(amount) FILTER (WHERE created_at >= $1::timestamptz), 0)::bigint AS daily_total,
+  COALESCE(SUM(amount), 0)::bigint AS monthly_total,
+  COUNT(*) FILTER (WHERE created_at >= $2::timestamptz) AS hourly_count,
+  MIN(created_at) FILTER (WHERE created_at >= $2::timestamptz) AS hourly_oldest
+FROM (
+    SELECT t.amount,
+      t.created_at
+    FROM transfers t
+    WHERE t.from_account_id = $3
+      AND t.created_at >= $4::timestamptz
+      AND NOT EXISTS (
+        SELECT 1
+        FROM transfer_reversals
+        WHERE reversal_transfer_id = t.id
+      )
+    UNION ALL
+    SELECT h.amount,
+      h.created_at
+    FROM holds h
+    WHERE h.account_id = $3
+      AND h.status = 'active'
+      AND h.created_at >= $4::timestamptz
+  ) AS usage
+`
+
+type GetTransferUsageParams struct {
+	DayStart   time.Time `json:"day_start"`
+	HourStart  time.Time `json:"hour_start"`
+	AccountID  int64     `json:"account_id"`
+	MonthStart time.Time `json:"month_start"`
+}
+
+type GetTransferUsageRow struct {
+	DailyTotal   int64        `json:"daily_total"`
+	MonthlyTotal int64        `json:"monthly_total"`
+	HourlyCount  int64        `json:"hourly_count"`
+	HourlyOldest sql.NullTime `json:"hourly_oldest"`
+}
+
+func (q *Queries) GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, getTransferUsage,
+		arg.DayStart,
+		arg.HourStart,
+		arg.AccountID,
+		arg.MonthStart,
+	)
+	var i GetTransferUsageRow
+	err := row.Scan(
+		&i.DailyTotal,
+		&i.MonthlyTotal,
+		&i.HourlyCount,
+		&i.HourlyOldest,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, fee
 FROM transfers
-WHERE from_account_id = $1
-  OR to_account_id = $1
-ORDER BY id
-LIMIT $3 OFFSET $2
+WHERE (
+    (
+      $1::text IN ('', 'outgoing')
+      AND from_account_id = $2
+    )
+    OR (
+      $1::text IN ('', 'incoming')
+      AND to_account_id = $2
+    )
+  )
+  AND (
+    $3::bigint IS NULL
+    OR from_account_id = $3
+    OR to_account_id = $3
+  )
+  AND (
+    $4::bigint IS NULL
+    OR CASE
+      WHEN to_account_id = $2 THEN COALESCE(to_amount, amount)
+      ELSE amount
+    END >= $4
+  )
+  AND (
+    $5::bigint IS NULL
+    OR CASE
+      WHEN to_account_id = $2 THEN COALESCE(to_amount, amount)
+      ELSE amount
+    END <= $5
+  )
+  AND (
+    $6::timestamptz IS NULL
+    OR created_at >= $6
+  )
+  AND (
+    $7::timestamptz IS NULL
+    OR created_at < $7
+  )
+  AND (
+    $8::bigint IS NULL
+    OR (created_at, id) < ($9::timestamptz, $8)
+  )
+ORDER BY created_at DESC,
+  id DESC
+LIMIT $10
 `
 
 type ListTransfersParams struct {
-	AccountID int64 `json:"account_id"`
-	PageID    int32 `json:"page_id"`
-	PageSize  int32 `json:"page_size"`
+	Direction             string        `json:"direction"`
+	AccountID             int64         `json:"account_id"`
+	CounterpartyAccountID sql.NullInt64 `json:"counterparty_account_id"`
+	MinAmount             sql.NullInt64 `json:"min_amount"`
+	MaxAmount             sql.NullInt64 `json:"max_amount"`
+	FromTime              sql.NullTime  `json:"from_time"`
+	ToTime                sql.NullTime  `json:"to_time"`
+	AfterID               sql.NullInt64 `json:"after_id"`
+	AfterCreatedAt        sql.NullTime  `json:"after_created_at"`
+	PageSize              int32         `json:"page_size"`
 }
 
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
-	rows, err := q.db.QueryContext(ctx, listTransfers, arg.AccountID, arg.PageID, arg.PageSize)
+	rows, err := q.db.QueryContext(ctx, listTransfers,
+		arg.Direction,
+		arg.AccountID,
+		arg.CounterpartyAccountID,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterID,
+		arg.AfterCreatedAt,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +248,9 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.Fee,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,295 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
+)
+
+const (
+	TransferBatchModeAtomic     = "atomic"
+	TransferBatchModeBestEffort = "best_effort"
+
+	TransferBatchStatusProcessing         = "processing"
+	TransferBatchStatusCompleted          = "completed"
+	TransferBatchStatusPartiallyCompleted = "partially_completed"
+	TransferBatchStatusFailed             = "failed"
+
+	TransferBatchItemStatusPending   = "pending"
+	TransferBatchItemStatusSucceeded = "succeeded"
+	TransferBatchItemStatusFailed    = "failed"
+	// TransferBatchItemStatusAborted is an item of an atomic batch that was rolled back because another item failed
+	TransferBatchItemStatusAborted = "aborted"
+)
+
+type TransferBatchItemParam struct {
+	ToAccountID int64 `json:"to_account_id"`
+	Amount      int64 `json:"amount"`
+	// Error is set for items that failed validation, they are recorded as failed without being executed
+	Error string `json:"error,omitempty"`
+	// Fee is charged to the source account for the item, see TransferTxParam
+	Fee fee.Breakdown `json:"-"`
+}
+
+type TransferBatchTxParam struct {
+	Owner         string                   `json:"owner"`
+	FromAccountID int64                    `json:"from_account_id"`
+	Mode          string                   `json:"mode"`
+	Items         []TransferBatchItemParam `json:"items"`
+	// FrozenAcceptsCredits lets the items pay into frozen accounts, see TransferTxParam
+	FrozenAcceptsCredits bool `json:"-"`
+	// Limits bounds every item, the earlier items of the batch count towards the usage, see TransferTxParam
+	Limits *limit.Policy `json:"-"`
+	// Audit builds the audit event of the batch, appended in the transaction that finishes it. nil audits nothing.
+	Audit func(result TransferBatchTxResult) (audit.Event, error) `json:"-"`
+}
+
+type TransferBatchTxResult struct {
+	Batch TransferBatch       `json:"batch"`
+	Items []TransferBatchItem `json:"items"`
+}
+
+// TransferBatchTx records a batch of transfers from one account. An atomic batch executes all of its items in a
+// single transaction, when one of them fails nothing is transferred and the batch is recorded as failed. A best
+// effort batch is only recorded as processing with its items pending, SettleTransferBatchTx executes them.
+func (store *SQLStore) TransferBatchTx(ctx context.Context, arg TransferBatchTxParam) (TransferBatchTxResult, error) {
+	if arg.Mode == TransferBatchModeAtomic {
+		return store.atomicTransferBatch(ctx, arg)
+	}
+
+	var result TransferBatchTxResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = recordTransferBatch(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func (store *SQLStore) atomicTransferBatch(ctx context.Context, arg TransferBatchTxParam) (TransferBatchTxResult, error) {
+	var result TransferBatchTxResult
+
+	failedItem, failure := -1, ""
+	for i, item := range arg.Items {
+		if item.Error != "" {
+			failedItem, failure = i, item.Error
+			break
+		}
+	}
+
+	if failedItem < 0 {
+		err := store.execTx(ctx, func(q *Queries) error {
+			var err error
+			result, err = recordTransferBatch(ctx, q, arg)
+			if err != nil {
+				return err
+			}
+
+			for i, item := range result.Items {
+				transferResult, err := transfer(ctx, q, TransferTxParam{
+					FromAccountID:        arg.FromAccountID,
+					ToAccountID:          item.ToAccountID,
+					Amount:               item.Amount,
+					Fee:                  arg.Items[i].Fee,
+					FrozenAcceptsCredits: arg.FrozenAcceptsCredits,
+					Limits:               arg.Limits,
+				})
+				if err != nil {
+					failedItem, failure = i, err.Error()
+					return err
+				}
+
+				result.Items[i], err = q.SettleTransferBatchItem(ctx, SettleTransferBatchItemParams{
+					ID:         item.ID,
+					Status:     TransferBatchItemStatusSucceeded,
+					TransferID: sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true},
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			result.Batch, err = q.FinishTransferBatch(ctx, FinishTransferBatchParams{
+				ID:             result.Batch.ID,
+				Status:         TransferBatchStatusCompleted,
+				SucceededItems: int32(len(result.Items)),
+			})
+			if err != nil {
+				return err
+			}
+
+			return auditTransferBatch(ctx, q, arg, result)
+		})
+
+		if err == nil || failedItem < 0 {
+			return result, err
+		}
+	}
+
+	// the transaction was rolled back, record the batch again with the item that failed it and the aborted others
+	result = TransferBatchTxResult{}
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = recordTransferBatch(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+
+		for i, item := range result.Items {
+			settle := SettleTransferBatchItemParams{ID: item.ID, Status: TransferBatchItemStatusAborted}
+			if i == failedItem {
+				settle.Status, settle.Error = TransferBatchItemStatusFailed, failure
+			}
+
+			result.Items[i], err = q.SettleTransferBatchItem(ctx, settle)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Batch, err = q.FinishTransferBatch(ctx, FinishTransferBatchParams{
+			ID:          result.Batch.ID,
+			Status:      TransferBatchStatusFailed,
+			FailedItems: 1,
+		})
+		if err != nil {
+			return err
+		}
+
+		return auditTransferBatch(ctx, q, arg, result)
+	})
+
+	return result, err
+}
+
+// SettleTransferBatchTx executes the pending items of a best effort batch recorded by TransferBatchTx, every item
+// in its own transaction, and finishes the batch. It completes partially when some of the items fail. arg is the
+// param the batch was recorded with.
+func (store *SQLStore) SettleTransferBatchTx(ctx context.Context, arg TransferBatchTxParam, batch TransferBatchTxResult) (TransferBatchTxResult, error) {
+	// the recorded batch may still be read by the caller, the items are settled in a copy
+	result := TransferBatchTxResult{Batch: batch.Batch, Items: append([]TransferBatchItem(nil), batch.Items...)}
+
+	// the batch is visible as processing while its items run, a poller sees them settle one by one
+	var succeeded, failed int32
+	var err error
+	for i, item := range result.Items {
+		if item.Status == TransferBatchItemStatusFailed {
+			failed++
+			continue
+		}
+
+		err = store.execTx(ctx, func(q *Queries) error {
+			transferResult, err := transfer(ctx, q, TransferTxParam{
+				FromAccountID:        arg.FromAccountID,
+				ToAccountID:          item.ToAccountID,
+				Amount:               item.Amount,
+				Fee:                  arg.Items[i].Fee,
+				FrozenAcceptsCredits: arg.FrozenAcceptsCredits,
+				Limits:               arg.Limits,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Items[i], err = q.SettleTransferBatchItem(ctx, SettleTransferBatchItemParams{
+				ID:         item.ID,
+				Status:     TransferBatchItemStatusSucceeded,
+				TransferID: sql.NullInt64{Int64: transferResult.Transfer.ID, Valid: true},
+			})
+			return err
+		})
+
+		if err == nil {
+			succeeded++
+			continue
+		}
+
+		failed++
+		result.Items[i], err = store.SettleTransferBatchItem(ctx, SettleTransferBatchItemParams{
+			ID:     item.ID,
+			Status: TransferBatchItemStatusFailed,
+			Error:  err.Error(),
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	status := TransferBatchStatusPartiallyCompleted
+	switch {
+	case failed == 0:
+		status = TransferBatchStatusCompleted
+	case succeeded == 0:
+		status = TransferBatchStatusFailed
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Batch, err = q.FinishTransferBatch(ctx, FinishTransferBatchParams{
+			ID:             result.Batch.ID,
+			Status:         status,
+			SucceededItems: succeeded,
+			FailedItems:    failed,
+		})
+		if err != nil {
+			return err
+		}
+
+		return auditTransferBatch(ctx, q, arg, result)
+	})
+
+	return result, err
+}
+
+// auditTransferBatch appends the audit event of a finished batch inside the transaction that finished it
+func auditTransferBatch(ctx context.Context, q *Queries, arg TransferBatchTxParam, result TransferBatchTxResult) error {
+	if arg.Audit == nil {
+		return nil
+	}
+
+	event, err := arg.Audit(result)
+	return appendAudit(ctx, q, event, err)
+}
+
+// recordTransferBatch records a batch and its items, items that failed validation are recorded as failed and the
+// others as pending
+func recordTransferBatch(ctx context.Context, q *Queries, arg TransferBatchTxParam) (TransferBatchTxResult, error) {
+	var result TransferBatchTxResult
+	var err error
+
+	result.Batch, err = q.CreateTransferBatch(ctx, CreateTransferBatchParams{
+		Owner:         arg.Owner,
+		FromAccountID: arg.FromAccountID,
+		Mode:          arg.Mode,
+		TotalItems:    int32(len(arg.Items)),
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Items = make([]TransferBatchItem, len(arg.Items))
+	for i, item := range arg.Items {
+		status := TransferBatchItemStatusPending
+		if item.Error != "" {
+			status = TransferBatchItemStatusFailed
+		}
+
+		result.Items[i], err = q.CreateTransferBatchItem(ctx, CreateTransferBatchItemParams{
+			BatchID:     result.Batch.ID,
+			Line:        int32(i + 1),
+			ToAccountID: item.ToAccountID,
+			Amount:      item.Amount,
+			Status:      status,
+			Error:       item.Error,
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferBatchTxAtomic(t *testing.T) {
+	store := NewStore(testDB)
+	from := createCurrencyAccount(t, currency.USD, 0)
+	to1 := createCurrencyAccount(t, currency.USD, 0)
+	to2 := createCurrencyAccount(t, currency.USD, 0)
+
+	result, err := store.TransferBatchTx(context.Background(), TransferBatchTxParam{
+		Owner:         from.Owner,
+		FromAccountID: from.ID,
+		Mode:          TransferBatchModeAtomic,
+		Items:         []TransferBatchItemParam{{ToAccountID: to1.ID, Amount: 10}, {ToAccountID: to2.ID, Amount: 20}},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferBatchStatusCompleted, result.Batch.Status)
+	require.Equal(t, int32(2), result.Batch.TotalItems)
+	require.Equal(t, int32(2), result.Batch.SucceededItems)
+	require.Len(t, result.Items, 2)
+	for i, item := range result.Items {
+		require.Equal(t, int32(i+1), item.Line)
+		require.Equal(t, TransferBatchItemStatusSucceeded, item.Status)
+		require.True(t, item.TransferID.Valid)
+	}
+
+	account, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-30, account.Balance)
+
+	// the second item overdraws the account, the first one is rolled back
+	result, err = store.TransferBatchTx(context.Background(), TransferBatchTxParam{
+		Owner:         from.Owner,
+		FromAccountID: from.ID,
+		Mode:          TransferBatchModeAtomic,
+		Items:         []TransferBatchItemParam{{ToAccountID: to1.ID, Amount: 10}, {ToAccountID: to2.ID, Amount: account.Balance}},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferBatchStatusFailed, result.Batch.Status)
+	require.Equal(t, int32(0), result.Batch.SucceededItems)
+	require.Equal(t, int32(1), result.Batch.FailedItems)
+	require.Equal(t, TransferBatchItemStatusAborted, result.Items[0].Status)
+	require.Equal(t, TransferBatchItemStatusFailed, result.Items[1].Status)
+	require.Contains(t, result.Items[1].Error, "insufficient funds")
+	require.False(t, result.Items[0].TransferID.Valid)
+
+	unchanged, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, unchanged.Balance)
+
+	items, err := store.ListTransferBatchItems(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Items, items)
+}
+
+func TestTransferBatchTxBestEffort(t *testing.T) {
+	store := NewStore(testDB)
+	from := createCurrencyAccount(t, currency.USD, 0)
+	to := createCurrencyAccount(t, currency.USD, 0)
+	other := createCurrencyAccount(t, currency.IDR, 0)
+
+	arg := TransferBatchTxParam{
+		Owner:         from.Owner,
+		FromAccountID: from.ID,
+		Mode:          TransferBatchModeBestEffort,
+		Items: []TransferBatchItemParam{
+			{ToAccountID: to.ID, Amount: 10},
+			{ToAccountID: other.ID, Amount: 10},
+			{ToAccountID: to.ID, Amount: 5, Error: "account is frozen"},
+		},
+	}
+
+	recorded, err := store.TransferBatchTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// nothing is transferred until the batch is settled
+	require.Equal(t, TransferBatchStatusProcessing, recorded.Batch.Status)
+	require.Equal(t, TransferBatchItemStatusPending, recorded.Items[0].Status)
+	require.Equal(t, TransferBatchItemStatusPending, recorded.Items[1].Status)
+	require.Equal(t, TransferBatchItemStatusFailed, recorded.Items[2].Status)
+
+	account, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, account.Balance)
+
+	result, err := store.SettleTransferBatchTx(context.Background(), arg, recorded)
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchItemStatusPending, recorded.Items[0].Status)
+
+	require.Equal(t, TransferBatchStatusPartiallyCompleted, result.Batch.Status)
+	require.Equal(t, int32(1), result.Batch.SucceededItems)
+	require.Equal(t, int32(2), result.Batch.FailedItems)
+
+	require.Equal(t, TransferBatchItemStatusSucceeded, result.Items[0].Status)
+	require.Equal(t, TransferBatchItemStatusFailed, result.Items[1].Status)
+	require.Contains(t, result.Items[1].Error, "currency mismatch")
+	require.Equal(t, TransferBatchItemStatusFailed, result.Items[2].Status)
+	require.Equal(t, "account is frozen", result.Items[2].Error)
+
+	account, err = store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-10, account.Balance)
+
+	batch, err := store.GetTransferBatch(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Batch, batch)
+
+	batches, err := store.ListTransferBatches(context.Background(), ListTransferBatchesParams{Owner: from.Owner, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+}
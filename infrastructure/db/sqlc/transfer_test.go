@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -62,7 +63,6 @@ func TestListTransfer(t *testing.T) {
 
 	arg := ListTransfersParams{
 		AccountID: account1.ID,
-		PageID:    5,
 		PageSize:  5,
 	}
 
@@ -73,4 +73,81 @@ func TestListTransfer(t *testing.T) {
 	for _, transfer := range transfers {
 		validateTransferBasic(t, transfer)
 	}
+
+	// the next page starts after the last transfer, latest first
+	last := transfers[len(transfers)-1]
+	arg.AfterID = sql.NullInt64{Int64: last.ID, Valid: true}
+	arg.AfterCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+
+	next, err := testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, next, 5)
+	require.Less(t, next[0].ID, last.ID)
+
+	arg.AfterID, arg.AfterCreatedAt = sql.NullInt64{Int64: next[4].ID, Valid: true}, sql.NullTime{Time: next[4].CreatedAt, Valid: true}
+	end, err := testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, end)
+}
+
+func TestListTransferFilters(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	createRandomTransfer(t, account1, account2)
+	createRandomTransfer(t, account2, account1)
+	createRandomTransfer(t, account1, account3)
+
+	list := func(arg ListTransfersParams) []Transfer {
+		arg.AccountID = account1.ID
+		arg.PageSize = 10
+		transfers, err := testQueries.ListTransfers(context.Background(), arg)
+		require.NoError(t, err)
+		return transfers
+	}
+
+	require.Len(t, list(ListTransfersParams{}), 3)
+	require.Len(t, list(ListTransfersParams{Direction: "outgoing"}), 2)
+	require.Len(t, list(ListTransfersParams{Direction: "incoming"}), 1)
+	require.Len(t, list(ListTransfersParams{CounterpartyAccountID: sql.NullInt64{Int64: account3.ID, Valid: true}}), 1)
+	require.Len(t, list(ListTransfersParams{MinAmount: sql.NullInt64{Int64: 11, Valid: true}}), 0)
+	require.Len(t, list(ListTransfersParams{MaxAmount: sql.NullInt64{Int64: 10, Valid: true}}), 3)
+	require.Len(t, list(ListTransfersParams{FromTime: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}}), 0)
+	require.Len(t, list(ListTransfersParams{ToTime: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}}), 3)
+}
+
+func TestGetTransferUsage(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	for i := 0; i < 3; i++ {
+		createRandomTransfer(t, account1, account2)
+	}
+
+	// transfers to the account aren't its usage
+	createRandomTransfer(t, account2, account1)
+
+	now := time.Now().UTC()
+	usage, err := testQueries.GetTransferUsage(context.Background(), GetTransferUsageParams{
+		DayStart:   now.Add(-24 * time.Hour),
+		HourStart:  now.Add(-time.Hour),
+		AccountID:  account1.ID,
+		MonthStart: now.Add(-31 * 24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(30), usage.DailyTotal)
+	require.Equal(t, int64(30), usage.MonthlyTotal)
+	require.Equal(t, int64(3), usage.HourlyCount)
+
+	// nothing was sent after now
+	usage, err = testQueries.GetTransferUsage(context.Background(), GetTransferUsageParams{
+		DayStart:   now.Add(time.Minute),
+		HourStart:  now.Add(time.Minute),
+		AccountID:  account1.ID,
+		MonthStart: now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Zero(t, usage.DailyTotal)
+	require.Equal(t, int64(30), usage.MonthlyTotal)
+	require.Zero(t, usage.HourlyCount)
 }
@@ -2,11 +2,14 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/random"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func validateUserBasic(t *testing.T, user User) {
@@ -16,17 +19,18 @@ func validateUserBasic(t *testing.T, user User) {
 	require.NotEmpty(t, user.Email)
 	require.True(t, user.PasswordChangedAt.IsZero())
 	require.NotZero(t, user.CreatedAt)
+	require.NotEmpty(t, user.Role)
 }
 
 func createRandomUser(t *testing.T) User {
-	hashPassword, err := utils.GenerateHashPassword(utils.RandomString(10))
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(random.String(10)), bcrypt.DefaultCost)
 	require.NoError(t, err)
 
 	user1 := CreateUserParams{
-		Username:       utils.RandomOwner(),
-		HashedPassword: hashPassword,
-		FullName:       utils.RandomOwner(),
-		Email:          utils.RandomEmail(),
+		Username:       random.Owner(),
+		HashedPassword: string(hashPassword),
+		FullName:       random.Owner(),
+		Email:          random.Email(),
 	}
 
 	user2, err := testQueries.CreateUser(context.Background(), user1)
@@ -63,3 +67,52 @@ func TestGetUser(t *testing.T) {
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user1 := createRandomUser(t)
+	require.Equal(t, "customer", user1.Role)
+
+	user2, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{Username: user1.Username, Role: "auditor"})
+	require.NoError(t, err)
+	require.Equal(t, "auditor", user2.Role)
+
+	// roles are checked by the database too
+	_, err = testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{Username: user1.Username, Role: "root"})
+	require.Error(t, err)
+}
+
+func TestUpdateUserTier(t *testing.T) {
+	user1 := createRandomUser(t)
+	require.Equal(t, "standard", user1.Tier)
+
+	user2, err := testQueries.UpdateUserTier(context.Background(), UpdateUserTierParams{Username: user1.Username, Tier: "premium"})
+	require.NoError(t, err)
+	require.Equal(t, "premium", user2.Tier)
+}
+
+func TestSearchUsers(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	users, err := testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Query:    sql.NullString{String: strings.ToUpper(user1.Email), Valid: true},
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, user1.Username, users[0].Username)
+
+	users, err = testQueries.SearchUsers(context.Background(), SearchUsersParams{
+		Query:         sql.NullString{String: user1.Email, Valid: true},
+		AfterUsername: sql.NullString{String: user1.Username, Valid: true},
+		PageSize:      10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, users)
+
+	users, err = testQueries.SearchUsers(context.Background(), SearchUsersParams{PageSize: 5})
+	require.NoError(t, err)
+	require.NotEmpty(t, users)
+	for i := 1; i < len(users); i++ {
+		require.Less(t, users[i-1].Username, users[i].Username)
+	}
+}
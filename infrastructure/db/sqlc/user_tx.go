@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/audit"
+	"github.com/dhiemaz/bank-api/utils/outbox"
+)
+
+// UserRegistered is the payload of user.registered, the user without its password
+type UserRegistered struct {
+	Username  string    `json:"username"`
+	FullName  string    `json:"full_name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Tier      string    `json:"tier"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateUserTxParam struct {
+	CreateUserParams
+	// Audit builds the audit event of the registration, appended in the same transaction. nil audits nothing.
+	Audit func(user User) (audit.Event, error) `json:"-"`
+}
+
+// CreateUserTx creates a user with its user.registered event
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParam) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		err = appendOutbox(ctx, q, outbox.AggregateUser, user.Username, outbox.EventUserRegistered, UserRegistered{
+			Username:  user.Username,
+			FullName:  user.FullName,
+			Email:     user.Email,
+			Role:      user.Role,
+			Tier:      user.Tier,
+			CreatedAt: user.CreatedAt,
+		})
+		if err != nil || arg.Audit == nil {
+			return err
+		}
+
+		event, err := arg.Audit(user)
+		return appendAudit(ctx, q, event, err)
+	})
+
+	return user, err
+}
+
+type UpdateUserTxParam struct {
+	UpdateUserParams
+	// Audit builds the audit event of the update, appended in the same transaction. nil audits nothing.
+	Audit func(user User) (audit.Event, error) `json:"-"`
+}
+
+// UpdateUserTx updates a user and appends the audit event of the update
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParam) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil || arg.Audit == nil {
+			return err
+		}
+
+		event, err := arg.Audit(user)
+		return appendAudit(ctx, q, event, err)
+	})
+
+	return user, err
+}
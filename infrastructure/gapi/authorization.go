@@ -2,7 +2,10 @@ package gapi
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/dhiemaz/bank-api/utils/api_error"
 	"github.com/dhiemaz/bank-api/utils/token"
 	"strings"
 
@@ -16,29 +19,80 @@ const (
 	authorizationTypeBearer = "bearer"
 )
 
+// publicMethods are served without an access token, every other method needs one
+var publicMethods = map[string]bool{
+	"/pb.BankService/Login":            true,
+	"/pb.BankService/RenewAccessToken": true,
+	"/pb.BankService/CreateUser":       true,
+
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      true,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": true,
+}
+
+type payloadContextKey struct{}
+
+// contextWithPayload returns a copy of ctx carrying the authenticated user
+func contextWithPayload(ctx context.Context, payload *token.Payload) context.Context {
+	return context.WithValue(ctx, payloadContextKey{}, payload)
+}
+
+// authorizeUser returns the user authenticated by the auth interceptor, every call goes through it, the ones of the
+// gateway included
+func (server *GRPCServer) authorizeUser(ctx context.Context) (*token.Payload, error) {
+	payload, ok := ctx.Value(payloadContextKey{}).(*token.Payload)
+	if !ok {
+		return nil, unauthenticatedError(errors.New("call not authenticated"))
+	}
+
+	return payload, nil
+}
+
 func (server *GRPCServer) authenticateUser(ctx context.Context) (payload *token.Payload, err error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return nil, err
+		return nil, errors.New("missing metadata")
 	}
 
-	// check authorization header
+	// check authorization header, its value is "bearer <token>"
 	authorizationHeader := md.Get(authorizationHeaderKey)
-	if len(authorizationHeader) != 2 {
-		return nil, fmt.Errorf("invalid authorization header format: %s", err)
+	if len(authorizationHeader) == 0 {
+		return nil, errors.New("authorization header not provided")
+	}
+
+	fields := strings.Fields(authorizationHeader[0])
+	if len(fields) != 2 {
+		return nil, errors.New("invalid authorization header format")
 	}
 
 	// check authorization type
-	if strings.ToLower(authorizationHeader[0]) != authorizationTypeBearer {
-		return nil, fmt.Errorf("unsupported authentication type, provided: %s, expected: %s", authorizationHeader[0], authorizationTypeBearer)
+	if strings.ToLower(fields[0]) != authorizationTypeBearer {
+		return nil, fmt.Errorf("unsupported authentication type, provided: %s, expected: %s", fields[0], authorizationTypeBearer)
 	}
 
 	// verify access token
-	payload, err = server.token.VerifyToken(authorizationHeader[1])
+	payload, err = server.token.VerifyToken(fields[1])
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify token: %s", err)
 	}
 
+	// refresh tokens only renew access tokens
+	if err = payload.CheckType(token.TypeAccess); err != nil {
+		return nil, err
+	}
+
+	// the session of the token must neither be revoked nor expired
+	session, err := server.db.GetSession(ctx, payload.SessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api_error.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %s", err)
+	}
+
+	if err = session.CheckActive(payload.Username); err != nil {
+		return nil, err
+	}
+
 	return
 }
 
@@ -0,0 +1,44 @@
+package gapi
+
+import (
+	"errors"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/token"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatusError maps usecase and store errors to gRPC status errors
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, api_error.ErrInsufficientFunds), errors.Is(err, api_error.ErrTransferLimitExceeded),
+		errors.Is(err, api_error.ErrAccountBalanceNotZero), api_error.IsInvalidStatusTransition(err),
+		errors.Is(err, api_error.ErrFxQuoteExpired), errors.Is(err, api_error.ErrFxQuoteUsed),
+		errors.Is(err, api_error.ErrAccountDeleted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, api_error.ErrIdempotencyKeyConflict), errors.Is(err, api_error.ErrUsernameTaken),
+		errors.Is(err, api_error.ErrAccountExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, api_error.ErrInvalidIdempotencyKey), errors.Is(err, api_error.ErrInvalidProduct),
+		errors.Is(err, api_error.ErrInvalidSweepAccount), api_error.IsCurrencyMismatch(err),
+		errors.Is(err, api_error.ErrFxQuoteMismatch), errors.Is(err, api_error.ErrFxAmountTooSmall),
+		errors.Is(err, api_error.ErrInvalidCursor), errors.Is(err, api_error.ErrEmailSameAsOld),
+		errors.Is(err, api_error.ErrPasswordWrong), errors.Is(err, api_error.ErrNothingToUpdate),
+		errors.Is(err, api_error.ErrSameAccountTransfer):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, api_error.ErrIncorrectPassword), errors.Is(err, token.ErrTokenInvalid),
+		errors.Is(err, token.ErrTokenExpired), errors.Is(err, api_error.ErrBlockedRefreshToken),
+		errors.Is(err, api_error.ErrExpiredRefreshToken), errors.Is(err, api_error.ErrMismatchedRefreshTokens),
+		errors.Is(err, api_error.ErrRefreshTokenReused):
+		return unauthenticatedError(err)
+	case errors.Is(err, api_error.ErrNotAccountOwner), errors.Is(err, api_error.ErrAccountFrozen),
+		errors.Is(err, api_error.ErrAccountDormant):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, api_error.ErrSessionNotFound), errors.Is(err, api_error.ErrAccountNotFound),
+		errors.Is(err, api_error.ErrFxQuoteNotFound), errors.Is(err, api_error.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "internal error: %s", err)
+	}
+}
@@ -0,0 +1,39 @@
+package gapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "InsufficientFunds", err: api_error.ErrInsufficientFunds, code: codes.FailedPrecondition},
+		{name: "IdempotencyKeyConflict", err: api_error.ErrIdempotencyKeyConflict, code: codes.AlreadyExists},
+		{name: "CurrencyMismatch", err: api_error.ErrCurrencyMismatch("USD", "IDR"), code: codes.InvalidArgument},
+		{name: "NotAccountOwner", err: api_error.ErrNotAccountOwner, code: codes.PermissionDenied},
+		{name: "AccountFrozen", err: api_error.ErrAccountFrozen, code: codes.PermissionDenied},
+		{name: "AccountDeleted", err: api_error.ErrAccountDeleted, code: codes.FailedPrecondition},
+		{name: "SameAccountTransfer", err: api_error.ErrSameAccountTransfer, code: codes.InvalidArgument},
+		{name: "AccountNotFound", err: api_error.ErrAccountNotFound, code: codes.NotFound},
+		{name: "UsernameTaken", err: api_error.ErrUsernameTaken, code: codes.AlreadyExists},
+		{name: "UserNotFound", err: api_error.ErrUserNotFound, code: codes.NotFound},
+		{name: "IncorrectPassword", err: api_error.ErrIncorrectPassword, code: codes.Unauthenticated},
+		{name: "RefreshTokenReused", err: api_error.ErrRefreshTokenReused, code: codes.Unauthenticated},
+		{name: "Unknown", err: errors.New("connection reset"), code: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.code, status.Code(toStatusError(tc.err)))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package gapi
+
+import (
+	"context"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDHeader = "x-request-id"
+
+type requestIDContextKey struct{}
+
+// RequestID returns the id of the call ctx belongs to, empty outside of a call
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// unaryInterceptors are run in order around every unary call: the request id is set first so that the log line of
+// the call carries it, panics are recovered before the call is logged and the caller is authenticated last
+func (server *GRPCServer) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		requestIDUnaryInterceptor,
+		loggingUnaryInterceptor,
+		recoveryUnaryInterceptor,
+		server.authUnaryInterceptor,
+	}
+}
+
+// streamInterceptors are the stream counterparts of unaryInterceptors, in the same order
+func (server *GRPCServer) streamInterceptors() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		requestIDStreamInterceptor,
+		loggingStreamInterceptor,
+		recoveryStreamInterceptor,
+		server.authStreamInterceptor,
+	}
+}
+
+// contextStream is a server stream with a context derived from the one of the stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *contextStream) Context() context.Context {
+	return stream.ctx
+}
+
+// withRequestID reads the request id of the caller, or creates one, and sends it back in the response headers
+func withRequestID(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+func requestIDUnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+func requestIDStreamInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: stream, ctx: withRequestID(stream.Context())})
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := logger.Fields{
+		"component":  "grpc",
+		"method":     method,
+		"code":       code.String(),
+		"duration":   time.Since(start).String(),
+		"request_id": RequestID(ctx),
+	}
+
+	if err != nil {
+		logger.WithFields(fields).Errorf("%s failed with %s, error : %v", method, code, err)
+		return
+	}
+
+	logger.WithFields(fields).Infof("%s succeeded", method)
+}
+
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+	return res, err
+}
+
+func loggingStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	logCall(stream.Context(), info.FullMethod, start, err)
+	return err
+}
+
+// recoverPanic turns a panic of a call into an Internal error, the panic is logged with its stack
+func recoverPanic(ctx context.Context, method string, err *error) {
+	if recovered := recover(); recovered != nil {
+		logger.WithFields(logger.Fields{"component": "grpc", "method": method, "request_id": RequestID(ctx)}).
+			Errorf("recovered panic in %s: %v\n%s", method, recovered, debug.Stack())
+
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	defer recoverPanic(ctx, info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+func recoveryStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverPanic(stream.Context(), info.FullMethod, &err)
+	return handler(srv, stream)
+}
+
+// authorize authenticates the caller of every method but the public ones and puts the token payload in the context
+func (server *GRPCServer) authorize(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	payload, err := server.authenticateUser(ctx)
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	return contextWithPayload(ctx, payload), nil
+}
+
+func (server *GRPCServer) authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := server.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (server *GRPCServer) authStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := server.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+}
@@ -0,0 +1,135 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/random"
+	"github.com/dhiemaz/bank-api/utils/rbac"
+	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestServer(t *testing.T, store db.Store) *GRPCServer {
+	maker, err := token.NewPasetoMaker(random.String(32))
+	require.NoError(t, err)
+
+	return &GRPCServer{db: store, token: maker}
+}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	sessionID := uuid.New()
+	accessToken, _, err := server.token.CreateToken("alice", rbac.RoleCustomer, sessionID)
+	require.NoError(t, err)
+
+	store.EXPECT().GetSession(gomock.Any(), sessionID).
+		Return(db.Session{ID: sessionID, Username: "alice", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		payload, err := server.authorizeUser(ctx)
+		require.NoError(t, err)
+		return payload.Username, nil
+	}
+
+	// a single "bearer <token>" value, the way clients and the gateway send it
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeaderKey, "Bearer "+accessToken))
+	res, err := server.authUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pb.BankService/ListAccounts"}, handler)
+	require.NoError(t, err)
+	require.Equal(t, "alice", res)
+
+	testCases := map[string]metadata.MD{
+		"NoHeader":          metadata.MD{},
+		"NoToken":           metadata.Pairs(authorizationHeaderKey, "Bearer"),
+		"UnsupportedType":   metadata.Pairs(authorizationHeaderKey, "Basic "+accessToken),
+		"InvalidToken":      metadata.Pairs(authorizationHeaderKey, "Bearer invalid"),
+		"TooManyValueParts": metadata.Pairs(authorizationHeaderKey, "Bearer "+accessToken+" extra"),
+	}
+
+	for name, md := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			_, err := server.authUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pb.BankService/ListAccounts"}, handler)
+			require.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
+
+func TestAuthUnaryInterceptorRefreshToken(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	refreshToken, _, err := server.token.CreateRefreshToken("alice")
+	require.NoError(t, err)
+
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		t.Fatal("a refresh token authenticated the call")
+		return nil, nil
+	}
+
+	// refresh tokens are rejected before their session is read, they only renew access tokens
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeaderKey, "Bearer "+refreshToken))
+	_, err = server.authUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pb.BankService/ListAccounts"}, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthorizeUserNeedsInterceptor(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	accessToken, _, err := server.token.CreateToken("alice", rbac.RoleCustomer, uuid.New())
+	require.NoError(t, err)
+
+	// the token of a call that skipped the interceptors isn't read
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeaderKey, "Bearer "+accessToken))
+	_, err = server.authorizeUser(ctx)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthUnaryInterceptorPublicMethod(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	res, err := server.authUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pb.BankService/Login"},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, "ok", res)
+}
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	_, err := recoveryUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pb.BankService/GetAccount"},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			panic("boom")
+		})
+	require.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestRequestIDUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.BankService/GetAccount"}
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return RequestID(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDHeader, "req-1"))
+	res, err := requestIDUnaryInterceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "req-1", res)
+
+	res, err = requestIDUnaryInterceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	_, err = uuid.Parse(res.(string))
+	require.NoError(t, err)
+}
@@ -0,0 +1,13 @@
+package gapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/config"
+)
+
+func TestMain(m *testing.M) {
+	config.InitLogger()
+	os.Exit(m.Run())
+}
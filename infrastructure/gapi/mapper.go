@@ -1,12 +1,15 @@
 package gapi
 
 import (
+	"github.com/dhiemaz/bank-api/entities"
 	"github.com/dhiemaz/bank-api/grpc/pb"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func fromDBUserToPbUserResponse(user db.User) *pb.UserResponse {
+func fromUserResponseToPbUserResponse(user entities.UserResponse) *pb.UserResponse {
 	return &pb.UserResponse{
 		Username:          user.Username,
 		FullName:          user.FullName,
@@ -15,3 +18,60 @@ func fromDBUserToPbUserResponse(user db.User) *pb.UserResponse {
 		CreatedAt:         timestamppb.New(user.CreatedAt),
 	}
 }
+
+func fromDBSessionToPbSession(session db.Session, currentSessionID uuid.UUID) *pb.Session {
+	return &pb.Session{
+		Id:        session.ID.String(),
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		CreatedAt: timestamppb.New(session.CreatedAt),
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		Current:   session.ID == currentSessionID,
+	}
+}
+
+func fromDBAccountToPbAccount(account *db.Account) *pb.Account {
+	return &pb.Account{
+		Id:                      account.ID,
+		Owner:                   account.Owner,
+		Balance:                 account.Balance,
+		BalanceDecimal:          currency.FormatAmount(account.Balance, account.Currency),
+		AvailableBalance:        account.AvailableBalance(),
+		AvailableBalanceDecimal: currency.FormatAmount(account.AvailableBalance(), account.Currency),
+		Currency:                account.Currency,
+		Product:                 account.Product,
+		Status:                  account.Status,
+		StatusReason:            account.StatusReason,
+		CreatedAt:               timestamppb.New(account.CreatedAt),
+	}
+}
+
+func fromDBTransferToPbTransfer(transfer db.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:            transfer.ID,
+		FromAccountId: transfer.FromAccountID,
+		ToAccountId:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		ToAmount:      transfer.ToAmount.Int64,
+		ExchangeRate:  transfer.ExchangeRate.String,
+		Fee:           transfer.Fee,
+		CreatedAt:     timestamppb.New(transfer.CreatedAt),
+	}
+}
+
+func fromAccountEventToPbAccountEvent(event entities.AccountEventResponse) *pb.AccountEvent {
+	res := &pb.AccountEvent{
+		Id:             event.ID,
+		AccountId:      event.AccountID,
+		Amount:         event.Amount,
+		AmountDecimal:  event.AmountDecimal,
+		Balance:        event.Balance,
+		BalanceDecimal: event.BalanceDecimal,
+		CreatedAt:      timestamppb.New(event.CreatedAt),
+	}
+
+	if event.TransferID != nil {
+		res.TransferId = *event.TransferID
+	}
+	return res
+}
@@ -2,44 +2,47 @@ package gapi
 
 import (
 	"context"
-	"log"
+	"github.com/dhiemaz/bank-api/entities"
+	"net"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
 
 const (
-	// MetadataKey is the key used to store the metadata in the context
-	userAgent            = "user-agent"
-	grpcGatewayUserAgent = "grpcgateway-user-agent"
-	xForwardForHeader    = "x-forwarded-host"
+	userAgentHeader            = "user-agent"
+	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
+	xForwardedForHeader        = "x-forwarded-for"
 )
 
-type Metadata struct {
-	ClientIP  string
-	UserAgent string
-}
-
-func (server *GRPCServer) extractMetadata(ctx context.Context) *Metadata {
-	meta := &Metadata{}
+// extractMetadata reads the client of a call the way gin reads it from an http request. Calls coming through the
+// gateway carry the user agent and address of the http client, direct calls the ones of the grpc client.
+func (server *GRPCServer) extractMetadata(ctx context.Context) entities.ClientInfo {
+	var client entities.ClientInfo
 
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		log.Printf("extractMetadata: %v", md)
-
-		if len(md[userAgent]) > 0 {
-			meta.UserAgent = md[userAgent][0]
+		if values := md.Get(grpcGatewayUserAgentHeader); len(values) > 0 {
+			client.UserAgent = values[0]
+		} else if values = md.Get(userAgentHeader); len(values) > 0 {
+			client.UserAgent = values[0]
 		}
-		if len(md[grpcGatewayUserAgent]) > 0 {
-			meta.UserAgent = md[grpcGatewayUserAgent][0]
-		}
-		if len(md[xForwardForHeader]) > 0 {
-			meta.ClientIP = md[xForwardForHeader][0]
+
+		// the first address is the client, the next ones are the proxies it went through
+		if values := md.Get(xForwardedForHeader); len(values) > 0 {
+			forwardedFor, _, _ := strings.Cut(values[0], ",")
+			client.ClientIP = strings.TrimSpace(forwardedFor)
 		}
 	}
 
-	if p, ok := peer.FromContext(ctx); ok {
-		meta.ClientIP = p.Addr.String()
+	if client.ClientIP == "" {
+		if p, ok := peer.FromContext(ctx); ok {
+			client.ClientIP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(client.ClientIP); err == nil {
+				client.ClientIP = host
+			}
+		}
 	}
 
-	return meta
+	return client
 }
@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"github.com/dhiemaz/bank-api/entities"
+
+	"github.com/dhiemaz/bank-api/grpc/pb"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (server *GRPCServer) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.AccountResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := entities.CreateAccountRequest{Currency: req.GetCurrency(), Product: req.GetProduct()}
+	if err = validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	account, err := server.account.AccountRegistration(ctx, payload, request)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.AccountResponse{Account: fromDBAccountToPbAccount(account)}, nil
+}
+
+func (server *GRPCServer) GetAccount(ctx context.Context, req *pb.AccountRequest) (*pb.AccountResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := entities.GetAccountRequest{ID: req.GetId()}
+	if err = validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	account, err := server.account.GetOwnedAccount(ctx, payload, request.ID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.AccountResponse{Account: fromDBAccountToPbAccount(account)}, nil
+}
+
+func (server *GRPCServer) ListAccounts(ctx context.Context, _ *emptypb.Empty) (*pb.ListAccountsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := server.account.GetAccounts(ctx, payload)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := &pb.ListAccountsResponse{Accounts: make([]*pb.Account, 0, len(accounts))}
+	for i := range accounts {
+		res.Accounts = append(res.Accounts, fromDBAccountToPbAccount(&accounts[i]))
+	}
+
+	return res, nil
+}
+
+func (server *GRPCServer) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := entities.DeleteAccountRequest{ID: req.GetId()}
+	if err = validateRequest(&uri); err != nil {
+		return nil, err
+	}
+
+	request := entities.CloseAccountRequest{
+		AccountID:        uri.ID,
+		SweepToAccountID: req.GetSweepToAccountId(),
+		SweepQuoteID:     req.GetSweepQuoteId(),
+		Reason:           req.GetReason(),
+	}
+	if err = validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	result, err := server.account.DeleteAccount(ctx, payload, request)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := &pb.DeleteAccountResponse{Account: fromDBAccountToPbAccount(&result.Account)}
+	if result.Sweep != nil {
+		res.Sweep = fromDBTransferToPbTransfer(result.Sweep.Transfer)
+	}
+
+	return res, nil
+}
+
+func (server *GRPCServer) RestoreAccount(ctx context.Context, req *pb.AccountRequest) (*pb.AccountResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := entities.RestoreAccountRequest{ID: req.GetId()}
+	if err = validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	if err = server.account.RestoreAccount(ctx, payload, request.ID); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	account, err := server.account.GetOwnedAccount(ctx, payload, request.ID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.AccountResponse{Account: fromDBAccountToPbAccount(account)}, nil
+}
+
+// WatchAccount streams the events of an account of the caller until the call is cancelled
+func (server *GRPCServer) WatchAccount(req *pb.WatchAccountRequest, stream pb.BankService_WatchAccountServer) error {
+	ctx := stream.Context()
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	request := entities.WatchAccountRequest{ID: req.GetAccountId(), LastEventID: req.GetLastEventId()}
+	if err = validateRequest(&request); err != nil {
+		return err
+	}
+
+	err = server.watcher.WatchAccount(ctx, payload, request.ID, request.LastEventID, func(event entities.AccountEventResponse) error {
+		return stream.Send(fromAccountEventToPbAccountEvent(event))
+	})
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		// failed sends are status errors already
+		return err
+	}
+
+	return toStatusError(err)
+}
@@ -0,0 +1,101 @@
+package gapi
+
+import (
+	"context"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/google/uuid"
+
+	"github.com/dhiemaz/bank-api/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *GRPCServer) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	request := entities.RenewAccessTokenRequest{RefreshToken: req.GetRefreshToken()}
+	if err := validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	response, err := server.auth.RenewToken(ctx, request, server.extractMetadata(ctx))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := &pb.RenewAccessTokenResponse{
+		SessionId:             response.SessionID.String(),
+		AccessToken:           response.AccessToken,
+		AccessTokenExpiresAt:  timestamppb.New(response.AccessTokenExpiresAt),
+		RefreshToken:          response.RefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(response.RefreshTokenExpiresAt),
+	}
+	return res, nil
+}
+
+func (server *GRPCServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*emptypb.Empty, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetUsername() != "" && req.GetUsername() != payload.Username {
+		return nil, status.Error(codes.Unauthenticated, "requested username doesn't match the provided in token")
+	}
+
+	if err = server.auth.Logout(ctx, payload); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (server *GRPCServer) ListSessions(ctx context.Context, _ *emptypb.Empty) (*pb.ListSessionsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := server.auth.GetSessions(ctx, payload)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := &pb.ListSessionsResponse{Sessions: make([]*pb.Session, 0, len(sessions))}
+	for _, session := range sessions {
+		res.Sessions = append(res.Sessions, fromDBSessionToPbSession(session, payload.SessionID))
+	}
+	return res, nil
+}
+
+func (server *GRPCServer) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*emptypb.Empty, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := entities.SessionURIRequest{ID: req.GetSessionId()}
+	if err = validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	if err = server.auth.RevokeSession(ctx, payload, uuid.MustParse(request.ID)); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (server *GRPCServer) RevokeOtherSessions(ctx context.Context, _ *emptypb.Empty) (*pb.RevokeOtherSessionsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := server.auth.RevokeOtherSessions(ctx, payload)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.RevokeOtherSessionsResponse{Revoked: revoked}, nil
+}
@@ -0,0 +1,107 @@
+package gapi
+
+import (
+	"context"
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/utils"
+
+	"github.com/dhiemaz/bank-api/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *GRPCServer) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := entities.CreateTransferRequest{
+		FromAccountID:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		QuoteID:        req.GetQuoteId(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	}
+	if err = validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	result, err := server.transfer.CreateTransfer(ctx, payload, request)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := &pb.CreateTransferResponse{
+		Transfer:           fromDBTransferToPbTransfer(result.Transfer),
+		FromAccountBalance: result.FromAccount.Balance,
+		Replayed:           result.Replayed,
+	}
+	return res, nil
+}
+
+func (server *GRPCServer) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := entities.GetTransferRequest{AccountID: req.GetAccountId()}
+	if err = validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	// zero values are the filters that aren't set
+	filter := entities.ListTransfersFilter{Direction: req.GetDirection()}
+	if req.GetMinAmount() != 0 {
+		minAmount := req.GetMinAmount()
+		filter.MinAmount = &minAmount
+	}
+	if req.GetMaxAmount() != 0 {
+		maxAmount := req.GetMaxAmount()
+		filter.MaxAmount = &maxAmount
+	}
+	if req.GetCounterpartyAccountId() != 0 {
+		counterparty := req.GetCounterpartyAccountId()
+		filter.CounterpartyAccountID = &counterparty
+	}
+	if req.GetFrom() != nil {
+		from := req.GetFrom().AsTime()
+		filter.From = &from
+	}
+	if req.GetTo() != nil {
+		to := req.GetTo().AsTime()
+		filter.To = &to
+	}
+	if err = validateRequest(&filter); err != nil {
+		return nil, err
+	}
+
+	pagination := &utils.PaginationQuery{Cursor: req.GetCursor(), Limit: req.GetLimit()}
+	if err = validateRequest(pagination); err != nil {
+		return nil, err
+	}
+
+	if pagination.Limit == 0 {
+		pagination.Limit = utils.DefaultPageSize
+	}
+
+	if pagination.Cursor != "" {
+		pagination.AfterCreatedAt, pagination.AfterID, err = utils.DecodeCursor(pagination.Cursor)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	transfers, nextCursor, err := server.transfer.GetListTransfer(ctx, payload, request, filter, pagination)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := &pb.ListTransfersResponse{Transfers: make([]*pb.Transfer, 0, len(transfers)), NextCursor: nextCursor}
+	for _, transfer := range transfers {
+		res.Transfers = append(res.Transfers, fromDBTransferToPbTransfer(transfer))
+	}
+
+	return res, nil
+}
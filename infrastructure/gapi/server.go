@@ -3,48 +3,108 @@ package gapi
 import (
 	"fmt"
 	"github.com/dhiemaz/bank-api/config"
+	accountUsecase "github.com/dhiemaz/bank-api/domain/account/usecase"
+	auditUsecase "github.com/dhiemaz/bank-api/domain/audit/usecase"
+	securityUsecase "github.com/dhiemaz/bank-api/domain/security/usecase"
+	transactionUsecase "github.com/dhiemaz/bank-api/domain/transaction/usecase"
+	userUsecase "github.com/dhiemaz/bank-api/domain/user/usecase"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/dhiemaz/bank-api/utils/notify"
 	"github.com/dhiemaz/bank-api/utils/token"
-	"log"
 	"net"
 
 	"github.com/dhiemaz/bank-api/grpc/pb"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+// GRPCServer serves BankService with the usecases of the http api, requests are validated with the same rules and
+// the usecases are called with the caller authenticated from the metadata
 type GRPCServer struct {
-	config *config.Config
-	db     db.Store
-	token  token.Maker
+	config   *config.Config
+	db       db.Store
+	token    token.Maker
+	auth     securityUsecase.AuthUseCase
+	user     userUsecase.UserUseCase
+	account  accountUsecase.AccountUseCase
+	transfer transactionUsecase.TransferUseCase
+	watcher  accountUsecase.WatchUseCase
 	pb.UnimplementedBankServiceServer
 }
 
-func NewServer(config *config.Config, store db.Store) (*GRPCServer, error) {
+// NewServer : bus wakes the account watchers, it's fed the entry notifications of the database by the caller
+func NewServer(config *config.Config, store db.Store, bus *notify.Bus) (*GRPCServer, error) {
 	maker, err := token.NewPasetoMaker(config.SymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create tokenMaker for grpcServer, %w", err)
 	}
 
-	grpcServer := &GRPCServer{config: config, token: maker, db: store}
+	if err = currency.Configure(config.Currencies.Enabled); err != nil {
+		return nil, fmt.Errorf("cannot configure currencies for grpcServer, %w", err)
+	}
+
+	fees, err := config.FeeSchedule()
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure fees for grpcServer, %w", err)
+	}
+
+	limits, err := config.TransferLimits()
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure transfer limits for grpcServer, %w", err)
+	}
+
+	products, err := config.AccountProducts()
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure account products for grpcServer, %w", err)
+	}
+
+	// requests are validated with the binding rules of the http api
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("currency", utils.ValidCurrency)
+	}
+
+	auditor := auditUsecase.NewAuditor()
+	authUC := securityUsecase.NewAuthUseCase(store, maker)
+	userUC := userUsecase.NewUserUseCase(store, maker, limits, auditor)
+	accountUC := accountUsecase.NewAccountUseCase(store, config.FxHouseAccounts(), products, config.Accounts.OpeningBalance,
+		config.AccountFundingAccounts(), auditor)
+	transferUC := transactionUsecase.NewTransferUseCase(store, accountUC, config.FxHouseAccounts(), config.Accounts.FrozenAcceptsCredits, fees, limits, auditor)
+	watcher := accountUsecase.NewWatcher(store, accountUC, bus, config.Watch.PollInterval)
+
+	grpcServer := &GRPCServer{
+		config:   config,
+		token:    maker,
+		db:       store,
+		auth:     authUC,
+		user:     userUC,
+		account:  accountUC,
+		transfer: transferUC,
+		watcher:  watcher,
+	}
 	return grpcServer, nil
 }
 
 func (server *GRPCServer) Start(address string) error {
-	grpcServer := grpc.NewServer()
-	pb.RegisterBankServiceServer(grpcServer, server)
-	reflection.Register(grpcServer)
-
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		return err
 	}
 
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		return err
-	}
+	return server.Serve(listener)
+}
+
+// Serve serves the calls accepted by listener through the interceptors until it fails
+func (server *GRPCServer) Serve(listener net.Listener) error {
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.unaryInterceptors()...),
+		grpc.ChainStreamInterceptor(server.streamInterceptors()...),
+	)
+	pb.RegisterBankServiceServer(grpcServer, server)
+	reflection.Register(grpcServer)
 
-	log.Printf("gRPC server listening on %s", address)
-	return nil
+	return grpcServer.Serve(listener)
 }
@@ -0,0 +1,16 @@
+package gapi
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validateRequest checks a usecase request against the binding rules the http api checks it with
+func validateRequest(request interface{}) error {
+	if err := binding.Validator.ValidateStruct(request); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %s", err)
+	}
+
+	return nil
+}
@@ -5,8 +5,19 @@ import (
 	"github.com/dhiemaz/bank-api/config"
 	accountHandler "github.com/dhiemaz/bank-api/domain/account/handler"
 	accountUsecase "github.com/dhiemaz/bank-api/domain/account/usecase"
+	adminHandler "github.com/dhiemaz/bank-api/domain/admin/handler"
+	adminUsecase "github.com/dhiemaz/bank-api/domain/admin/usecase"
+	auditUsecase "github.com/dhiemaz/bank-api/domain/audit/usecase"
+	fxHandler "github.com/dhiemaz/bank-api/domain/fx/handler"
+	fxUsecase "github.com/dhiemaz/bank-api/domain/fx/usecase"
+	holdHandler "github.com/dhiemaz/bank-api/domain/hold/handler"
+	holdUsecase "github.com/dhiemaz/bank-api/domain/hold/usecase"
+	scheduleHandler "github.com/dhiemaz/bank-api/domain/schedule/handler"
+	scheduleUsecase "github.com/dhiemaz/bank-api/domain/schedule/usecase"
 	securityHandler "github.com/dhiemaz/bank-api/domain/security/handler"
 	securityUsecase "github.com/dhiemaz/bank-api/domain/security/usecase"
+	statementHandler "github.com/dhiemaz/bank-api/domain/statement/handler"
+	statementUsecase "github.com/dhiemaz/bank-api/domain/statement/usecase"
 	transactionHandler "github.com/dhiemaz/bank-api/domain/transaction/handler"
 	transactionUsecase "github.com/dhiemaz/bank-api/domain/transaction/usecase"
 	userHandler "github.com/dhiemaz/bank-api/domain/user/handler"
@@ -15,6 +26,9 @@ import (
 	"github.com/dhiemaz/bank-api/middlewares"
 	"github.com/dhiemaz/bank-api/swagger/docs"
 	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/dhiemaz/bank-api/utils/notify"
+	"github.com/dhiemaz/bank-api/utils/rbac"
 	"github.com/dhiemaz/bank-api/utils/token"
 
 	_ "github.com/dhiemaz/bank-api/swagger/docs"
@@ -34,31 +48,81 @@ type GinServer struct {
 	userHandler        *userHandler.Handler
 	accountHandler     *accountHandler.Handler
 	transactionHandler *transactionHandler.Handler
+	scheduleHandler    *scheduleHandler.Handler
+	fxHandler          *fxHandler.Handler
+	holdHandler        *holdHandler.Handler
+	statementHandler   *statementHandler.Handler
+	adminHandler       *adminHandler.Handler
 	router             *gin.Engine
 }
 
-func NewServer(config *config.Config, dbStore db.Store, dbQueries db.Querier) (*GinServer, error) {
+// NewServer : bus wakes the account watchers, it's fed the entry notifications of the database by the caller
+func NewServer(config *config.Config, dbStore db.Store, dbQueries db.Querier, bus *notify.Bus) (*GinServer, error) {
 	maker, err := token.NewPasetoMaker(config.SymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create tokenMaker, %w", err)
 	}
 
+	if err = currency.Configure(config.Currencies.Enabled); err != nil {
+		return nil, fmt.Errorf("cannot configure currencies, %w", err)
+	}
+
+	fees, err := config.FeeSchedule()
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure fees, %w", err)
+	}
+
+	limits, err := config.TransferLimits()
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure transfer limits, %w", err)
+	}
+
+	products, err := config.AccountProducts()
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure account products, %w", err)
+	}
+
+	// audit
+	auditor := auditUsecase.NewAuditor()
+
 	// authentication
-	authUC := securityUsecase.NewAuthUseCase(dbStore)
+	authUC := securityUsecase.NewAuthUseCase(dbStore, maker)
 	authHandler := securityHandler.NewAuthHandler(authUC)
 
 	// user
-	userUC := userUsecase.NewUserUseCase(dbQueries)
+	userUC := userUsecase.NewUserUseCase(dbStore, maker, limits, auditor)
 	userHandler := userHandler.NewUserHandler(userUC)
 
 	// account
-	accountUC := accountUsecase.NewAccountUseCase(dbQueries)
-	accountHandler := accountHandler.NewAccountHandler(accountUC)
+	accountUC := accountUsecase.NewAccountUseCase(dbStore, config.FxHouseAccounts(), products, config.Accounts.OpeningBalance,
+		config.AccountFundingAccounts(), auditor)
+	watcher := accountUsecase.NewWatcher(dbStore, accountUC, bus, config.Watch.PollInterval)
+	accountHandler := accountHandler.NewAccountHandler(accountUC, watcher, config.Watch.Heartbeat)
 
 	// transaction
-	transactionUC := transactionUsecase.NewTransferUseCase(dbStore, accountUC)
+	transactionUC := transactionUsecase.NewTransferUseCase(dbStore, accountUC, config.FxHouseAccounts(), config.Accounts.FrozenAcceptsCredits, fees, limits, auditor)
 	transactionHandler := transactionHandler.NewTransactionHandler(transactionUC)
 
+	// scheduled transfer
+	scheduleUC := scheduleUsecase.NewScheduleUseCase(dbStore, accountUC)
+	scheduleHandler := scheduleHandler.NewScheduleHandler(scheduleUC)
+
+	// fx
+	fxUC := fxUsecase.NewFxUseCase(dbStore, config.FX.QuoteTTL)
+	fxHandler := fxHandler.NewFxHandler(fxUC)
+
+	// hold
+	holdUC := holdUsecase.NewHoldUseCase(dbStore, transactionUC, config.Holds.DefaultTTL, config.Holds.MaxTTL, config.Accounts.FrozenAcceptsCredits, limits)
+	holdHandler := holdHandler.NewHoldHandler(holdUC)
+
+	// admin
+	adminUC := adminUsecase.NewAdminUseCase(dbStore, config.FxHouseAccounts(), limits)
+	adminHandler := adminHandler.NewAdminHandler(adminUC)
+
+	// statement
+	statementUC := statementUsecase.NewStatementUseCase(dbStore, accountUC)
+	statementHandler := statementHandler.NewStatementHandler(statementUC)
+
 	s := &GinServer{
 		config:             config,
 		tm:                 maker,
@@ -66,6 +130,11 @@ func NewServer(config *config.Config, dbStore db.Store, dbQueries db.Querier) (*
 		dbQueries:          dbQueries,
 		authHandler:        authHandler,
 		transactionHandler: transactionHandler,
+		scheduleHandler:    scheduleHandler,
+		fxHandler:          fxHandler,
+		holdHandler:        holdHandler,
+		statementHandler:   statementHandler,
+		adminHandler:       adminHandler,
 		userHandler:        userHandler,
 		accountHandler:     accountHandler,
 	}
@@ -94,7 +163,7 @@ func (s *GinServer) setupValidator() {
 func (s *GinServer) setupRouter() {
 	router := gin.Default()
 
-	auth := router.Group("/").Use(middlewares.AuthMiddleware(s.tm))
+	auth := router.Group("/").Use(middlewares.AuthMiddleware(s.tm, s.dbStore))
 
 	// Account Routes
 	auth.POST("/api/accounts", s.accountHandler.CreateAccount)
@@ -103,14 +172,63 @@ func (s *GinServer) setupRouter() {
 	auth.GET("/api/accounts/del", s.accountHandler.GetDeletedAccounts)
 	auth.PATCH("/api/accounts/res/:id", s.accountHandler.RestoreAccount)
 	auth.DELETE("/api/accounts/:id", s.accountHandler.DeleteAccount)
+	auth.GET("/api/accounts/:id/events", s.accountHandler.WatchAccount)
+	auth.GET("/api/accounts/:id/statement", s.statementHandler.GetStatement)
+	auth.GET("/api/accounts/:id/statement.csv", s.statementHandler.ExportStatement(statementUsecase.FormatCSV))
+	auth.GET("/api/accounts/:id/statement.ofx", s.statementHandler.ExportStatement(statementUsecase.FormatOFX))
+	auth.GET("/api/accounts/:id/statement.xml", s.statementHandler.ExportStatement(statementUsecase.FormatCamt053))
 
 	// Transfer Routes
 	auth.GET("/api/transfers/:id", s.transactionHandler.GetTransfersList)
 	auth.POST("/api/transfers", s.transactionHandler.CreateTransfer)
+	auth.POST("/api/transfers/:id/reverse", s.transactionHandler.ReverseTransfer)
+	auth.GET("/api/transfers/fee", s.transactionHandler.PreviewFee)
+	auth.POST("/api/transfers/batch", s.transactionHandler.CreateTransferBatch)
+	auth.GET("/api/transfers/batch", s.transactionHandler.GetTransferBatches)
+	auth.GET("/api/transfers/batch/:id", s.transactionHandler.GetTransferBatch)
+
+	// Hold Routes
+	auth.POST("/api/holds", s.holdHandler.CreateHold)
+	auth.GET("/api/holds/:id", s.holdHandler.GetHold)
+	auth.POST("/api/holds/:id/capture", s.holdHandler.CaptureHold)
+	auth.POST("/api/holds/:id/release", s.holdHandler.ReleaseHold)
+	auth.GET("/api/accounts/:id/holds", s.holdHandler.GetAccountHolds)
+
+	// Scheduled Transfer Routes
+	auth.POST("/api/transfers/scheduled", s.scheduleHandler.CreateSchedule)
+	auth.GET("/api/transfers/scheduled", s.scheduleHandler.GetSchedules)
+	auth.GET("/api/transfers/scheduled/:id", s.scheduleHandler.GetSchedule)
+	auth.PATCH("/api/transfers/scheduled/:id", s.scheduleHandler.UpdateSchedule)
+	auth.DELETE("/api/transfers/scheduled/:id", s.scheduleHandler.CancelSchedule)
+	auth.GET("/api/transfers/scheduled/:id/runs", s.scheduleHandler.GetScheduleRuns)
+
+	// FX Routes
+	auth.GET("/api/fx/rates", s.fxHandler.GetRates)
+	auth.POST("/api/fx/quotes", s.fxHandler.CreateQuote)
 
 	// User Routes
 	auth.GET("api/users", s.userHandler.GetUser)
 	auth.PATCH("api/users", s.userHandler.UpdateUser)
+	auth.GET("api/users/limits", s.userHandler.GetLimits)
+	auth.POST("api/users/logout", s.authHandler.Logout)
+	auth.GET("api/users/sessions", s.authHandler.GetSessions)
+	auth.DELETE("api/users/sessions", s.authHandler.RevokeOtherSessions)
+	auth.DELETE("api/users/sessions/:id", s.authHandler.RevokeSession)
+
+	// Admin Routes, every route requires a permission of the role of the user
+	admin := router.Group("/api/admin").Use(middlewares.AuthMiddleware(s.tm, s.dbStore))
+	admin.GET("/users", middlewares.RequirePermission(rbac.PermissionUsersRead), s.adminHandler.SearchUsers)
+	admin.GET("/users/:username", middlewares.RequirePermission(rbac.PermissionUsersRead), s.adminHandler.GetUser)
+	admin.PUT("/users/:username/role", middlewares.RequirePermission(rbac.PermissionUsersManage), s.adminHandler.SetUserRole)
+	admin.PUT("/users/:username/tier", middlewares.RequirePermission(rbac.PermissionUsersManage), s.adminHandler.SetUserTier)
+	admin.GET("/accounts/:id", middlewares.RequirePermission(rbac.PermissionAccountsRead), s.adminHandler.GetAccount)
+	admin.POST("/accounts/:id/freeze", middlewares.RequirePermission(rbac.PermissionAccountsFreeze), s.adminHandler.FreezeAccount)
+	admin.POST("/accounts/:id/unfreeze", middlewares.RequirePermission(rbac.PermissionAccountsFreeze), s.adminHandler.UnfreezeAccount)
+	admin.GET("/accounts/:id/status", middlewares.RequirePermission(rbac.PermissionAccountsRead), s.adminHandler.GetAccountStatusChanges)
+	admin.PUT("/accounts/:id/status", middlewares.RequirePermission(rbac.PermissionAccountsFreeze), s.adminHandler.SetAccountStatus)
+	admin.GET("/accounts/:id/transfers", middlewares.RequirePermission(rbac.PermissionTransfersRead), s.adminHandler.GetAccountTransfers)
+	admin.GET("/transfers/:id", middlewares.RequirePermission(rbac.PermissionTransfersRead), s.adminHandler.GetTransfer)
+	admin.GET("/actions", middlewares.RequirePermission(rbac.PermissionAuditRead), s.adminHandler.GetActions)
 
 	// Unauthenticated Routes
 	router.POST("api/users/register", s.userHandler.Register)
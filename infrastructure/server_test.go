@@ -10,6 +10,7 @@ import (
 
 	mockdb "github.com/dhiemaz/bank-api/infrastructure/db/mock"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/notify"
 	"github.com/dhiemaz/bank-api/utils/token"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -70,7 +71,7 @@ func runServerTest(t *testing.T, tc testCase, req *http.Request) {
 func newTestServer(t *testing.T, store db.Store) *GinServer {
 	testConfig := config.GetConfig()
 
-	server, err := NewServer(testConfig, store, nil)
+	server, err := NewServer(testConfig, store, nil, notify.NewBus())
 	require.NoError(t, err)
 	return server
 }
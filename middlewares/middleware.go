@@ -1,10 +1,16 @@
 package middlewares
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/dhiemaz/bank-api/utils/rbac"
 	"github.com/dhiemaz/bank-api/utils/token"
+	"github.com/google/uuid"
 	"net/http"
 	"strings"
 
@@ -17,7 +23,12 @@ const (
 	AuthorizationPayloadKey = "payload"
 )
 
-func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// SessionGetter looks up the session an access token was issued for
+type SessionGetter interface {
+	GetSession(ctx context.Context, id uuid.UUID) (db.Session, error)
+}
+
+func AuthMiddleware(tokenMaker token.Maker, sessions SessionGetter) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
 		// Get Header
@@ -52,7 +63,44 @@ func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
+		// refresh tokens only renew access tokens
+		if err = payload.CheckType(token.TypeAccess); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, entities.Err(err))
+			return
+		}
+
+		// Check the session wasn't revoked, by a logout for example
+		session, err := sessions.GetSession(ctx, payload.SessionID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, entities.Err(api_error.ErrSessionNotFound))
+				return
+			}
+
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, entities.Err(err))
+			return
+		}
+
+		if err = session.CheckActive(payload.Username); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, entities.Err(err))
+			return
+		}
+
 		ctx.Set(AuthorizationPayloadKey, payload)
 		ctx.Next()
 	}
 }
+
+// RequirePermission lets the request through when the role of the access token is granted permission, it must run
+// after AuthMiddleware
+func RequirePermission(permission rbac.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(AuthorizationPayloadKey).(*token.Payload)
+		if !payload.Role.Can(permission) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, entities.Err(api_error.ErrPermissionDenied))
+			return
+		}
+
+		ctx.Next()
+	}
+}
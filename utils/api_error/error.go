@@ -3,24 +3,194 @@ package api_error
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
 	ErrNotAccountOwner         = errors.New("account doesn't belong to authenticated user")
 	ErrEmailSameAsOld          = errors.New("new email is the same as old email")
+	ErrNothingToUpdate         = errors.New("at least one field must be updated")
 	ErrBlockedRefreshToken     = errors.New("refresh token is blocked")
 	ErrMismatchedRefreshTokens = errors.New("refresh token doesn't match with stored refresh token")
 	ErrExpiredRefreshToken     = errors.New("refresh token has expired")
+	ErrRefreshTokenReused      = errors.New("refresh token has already been used, the session is revoked")
 	ErrPasswordWrong           = errors.New("old password is different from the one stored in the database")
+	ErrIncorrectPassword       = errors.New("incorrect password")
+	ErrIdempotencyKeyConflict  = errors.New("idempotency key has already been used with a different request")
+	ErrInvalidIdempotencyKey   = errors.New("idempotency key must be at most 255 characters")
+	ErrInsufficientFunds       = errors.New("insufficient funds")
+	ErrTransferNotFound        = errors.New("transfer not found")
+	ErrTransferFullyReversed   = errors.New("transfer has already been fully reversed")
+	ErrScheduleNotFound        = errors.New("scheduled transfer not found")
+	ErrFxRateNotFound          = errors.New("no exchange rate for the currency pair")
+	ErrFxQuoteNotFound         = errors.New("fx quote not found")
+	ErrFxQuoteExpired          = errors.New("fx quote has expired")
+	ErrFxQuoteUsed             = errors.New("fx quote has already been used")
+	ErrFxQuoteMismatch         = errors.New("fx quote doesn't match the currencies of the accounts")
+	ErrFxAmountTooSmall        = errors.New("amount is too small to be converted")
+	ErrFxReversalUnsupported   = errors.New("transfers between currencies can't be reversed")
+	ErrInvalidCursor           = errors.New("invalid pagination cursor")
+	ErrInvalidStatementPeriod  = errors.New("statement period must end after it starts")
+	ErrSessionNotFound         = errors.New("session not found")
+	ErrSessionRevoked          = errors.New("session has been revoked")
+	ErrSessionExpired          = errors.New("session has expired")
+	ErrPermissionDenied        = errors.New("role of authenticated user isn't allowed to do this")
+	ErrInvalidRole             = errors.New("role must be one of customer, support, admin or auditor")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrUsernameTaken           = errors.New("username is already taken")
+	ErrAccountExists           = errors.New("user already has an account in this currency")
+	ErrAccountNotFound         = errors.New("account not found")
+	ErrAccountFrozen           = errors.New("account is frozen")
+	ErrAccountDormant          = errors.New("account is dormant")
+	ErrAccountDeleted          = errors.New("account is closed")
+	ErrSameAccountTransfer     = errors.New("can't transfer to the same account")
+	ErrInvalidAccountStatus    = errors.New("status must be one of active, frozen, dormant or closed")
+	ErrAccountBalanceNotZero   = errors.New("account balance must be zero to close it, or swept to another account")
+	ErrInvalidSweepAccount     = errors.New("sweep account must be another open account of the same owner")
+	ErrAccountHasHolds         = errors.New("account has active holds, capture or release them first")
+	ErrHoldNotFound            = errors.New("hold not found")
+	ErrHoldExpired             = errors.New("hold has expired")
+	ErrInvalidHoldExpiry       = errors.New("hold must expire in the future and within the maximum hold duration")
+	ErrTransferBatchNotFound   = errors.New("transfer batch not found")
+	ErrInvalidBatchSize        = errors.New("a transfer batch must have between 1 and 1000 items")
+	ErrTransferLimitExceeded   = errors.New("transfer limit exceeded")
+	ErrInvalidTier             = errors.New("tier isn't one of the configured transfer limit tiers")
+	ErrInvalidProduct          = errors.New("product isn't one of the configured account products")
+	ErrNoInterestToPost        = errors.New("no interest to post")
 
-	ErrSameAccountTransfer = func(from, to int64) error {
-		return fmt.Errorf(fmt.Sprintf("can't transfer to the same account, req.FromAccountId=%d, req.ToAccount=%d", from, to))
-	}
 	ErrCurrencyMismatch = func(from, to string) error {
-		return fmt.Errorf(fmt.Sprintf("currency mismatch account1.currency=%s, account2.currency=%s", from, to))
+		return fmt.Errorf("%w account1.currency=%s, account2.currency=%s", errCurrencyMismatch, from, to)
+	}
+	errCurrencyMismatch = errors.New("currency mismatch")
+
+	ErrFxHouseAccountMissing = func(currency string) error {
+		return fmt.Errorf("no fx house account configured for currency %s", currency)
+	}
+
+	ErrFeeRevenueAccountMissing = func(currency string) error {
+		return fmt.Errorf("no fee revenue account configured for currency %s", currency)
+	}
+
+	ErrInvalidStatusTransition = func(from, to string) error {
+		return fmt.Errorf("%w, from=%s, to=%s", errInvalidStatusTransition, from, to)
+	}
+	errInvalidStatusTransition = errors.New("account status can't be changed")
+
+	ErrHoldNotActive = func(status string) error {
+		return fmt.Errorf("%w, status=%s", errHoldNotActive, status)
+	}
+	errHoldNotActive = errors.New("hold has already been settled")
+
+	ErrCaptureExceedsHold = func(amount, held int64) error {
+		return fmt.Errorf("%w, capture amount=%d, held amount=%d", errCaptureExceedsHold, amount, held)
+	}
+	errCaptureExceedsHold = errors.New("capture exceeds the amount on hold")
+
+	ErrReversalExceedsRemaining = func(amount, remaining int64) error {
+		return fmt.Errorf("%w, reversal amount=%d, remaining amount=%d", errReversalExceedsRemaining, amount, remaining)
+	}
+	errReversalExceedsRemaining = errors.New("reversal exceeds the amount left on the transfer")
+
+	ErrScheduleNotEditable = func(status string) error {
+		return fmt.Errorf("%w, status=%s", errScheduleNotEditable, status)
+	}
+	errScheduleNotEditable = errors.New("scheduled transfer can't be changed anymore")
+
+	ErrInvalidBatchCSV = func(line int, reason string) error {
+		return fmt.Errorf("%w, line %d: %s", errInvalidBatchCSV, line, reason)
 	}
+	errInvalidBatchCSV = errors.New("invalid transfer batch csv")
 
-	ErrAccountDeleted = func(id int64) error {
-		return fmt.Errorf("account %d is deleted", id)
+	ErrInvalidSchedule = func(reason string) error {
+		return fmt.Errorf("%w, %s", errInvalidSchedule, reason)
 	}
+	errInvalidSchedule = errors.New("invalid schedule")
 )
+
+// InsufficientFundsError is returned when a debit would take an account below its overdraft limit,
+// errors.Is(err, ErrInsufficientFunds) reports true for it.
+type InsufficientFundsError struct {
+	AccountID      int64
+	Balance        int64
+	HeldAmount     int64
+	OverdraftLimit int64
+	Amount         int64
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient funds in account %d, balance=%d, held_amount=%d, overdraft_limit=%d, amount=%d",
+		e.AccountID, e.Balance, e.HeldAmount, e.OverdraftLimit, e.Amount)
+}
+
+func (e *InsufficientFundsError) Is(target error) bool {
+	return target == ErrInsufficientFunds
+}
+
+// LimitExceededError is returned when a transfer would go over one of the transfer limits of the user, Remaining is
+// what is left of the limit until ResetsAt. errors.Is(err, ErrTransferLimitExceeded) reports true for it.
+type LimitExceededError struct {
+	Limit     string
+	Max       int64
+	Used      int64
+	Remaining int64
+	ResetsAt  *time.Time
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s, limit=%s, max=%d, used=%d, remaining=%d", ErrTransferLimitExceeded, e.Limit, e.Max, e.Used, e.Remaining)
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrTransferLimitExceeded
+}
+
+// Details is the part of the error clients act on, returned next to the message
+func (e *LimitExceededError) Details() interface{} {
+	return struct {
+		Limit     string     `json:"limit"`
+		Max       int64      `json:"max"`
+		Used      int64      `json:"used"`
+		Remaining int64      `json:"remaining"`
+		ResetsAt  *time.Time `json:"resets_at,omitempty"`
+	}{e.Limit, e.Max, e.Used, e.Remaining, e.ResetsAt}
+}
+
+// IsCurrencyMismatch reports whether err was created by ErrCurrencyMismatch
+func IsCurrencyMismatch(err error) bool {
+	return errors.Is(err, errCurrencyMismatch)
+}
+
+// IsReversalExceedsRemaining reports whether err was created by ErrReversalExceedsRemaining
+func IsReversalExceedsRemaining(err error) bool {
+	return errors.Is(err, errReversalExceedsRemaining)
+}
+
+// IsScheduleNotEditable reports whether err was created by ErrScheduleNotEditable
+func IsScheduleNotEditable(err error) bool {
+	return errors.Is(err, errScheduleNotEditable)
+}
+
+// IsInvalidSchedule reports whether err was created by ErrInvalidSchedule
+func IsInvalidSchedule(err error) bool {
+	return errors.Is(err, errInvalidSchedule)
+}
+
+// IsInvalidStatusTransition reports whether err was created by ErrInvalidStatusTransition
+func IsInvalidStatusTransition(err error) bool {
+	return errors.Is(err, errInvalidStatusTransition)
+}
+
+// IsHoldNotActive reports whether err was created by ErrHoldNotActive
+func IsHoldNotActive(err error) bool {
+	return errors.Is(err, errHoldNotActive)
+}
+
+// IsCaptureExceedsHold reports whether err was created by ErrCaptureExceedsHold
+func IsCaptureExceedsHold(err error) bool {
+	return errors.Is(err, errCaptureExceedsHold)
+}
+
+// IsInvalidBatchCSV reports whether err was created by ErrInvalidBatchCSV
+func IsInvalidBatchCSV(err error) bool {
+	return errors.Is(err, errInvalidBatchCSV)
+}
@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first event of the chain
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Event is what an audit event hashes, the hash of an event covers its content and the hash of the previous event
+// so that editing, removing or reordering events breaks every later link of the chain
+type Event struct {
+	Actor  string
+	Role   string
+	Action string
+	Target string
+	// Before and After are the JSON payloads of the target around the action, null when it didn't exist
+	Before    json.RawMessage
+	After     json.RawMessage
+	CreatedAt time.Time
+}
+
+// Hash is the hex encoded SHA-256 of an event chained to prevHash. The event is hashed as compact JSON with its time
+// in UTC at the microsecond precision of the database, so that an event read back hashes the same.
+func Hash(prevHash string, event Event) (string, error) {
+	canonical, err := json.Marshal(struct {
+		PrevHash  string          `json:"prev_hash"`
+		Actor     string          `json:"actor"`
+		Role      string          `json:"role"`
+		Action    string          `json:"action"`
+		Target    string          `json:"target"`
+		Before    json.RawMessage `json:"before"`
+		After     json.RawMessage `json:"after"`
+		CreatedAt string          `json:"created_at"`
+	}{
+		PrevHash:  prevHash,
+		Actor:     event.Actor,
+		Role:      event.Role,
+		Action:    event.Action,
+		Target:    event.Target,
+		Before:    Payload(event.Before),
+		After:     Payload(event.After),
+		CreatedAt: Timestamp(event.CreatedAt).Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Payload returns a payload as stored, an empty payload is null
+func Payload(payload json.RawMessage) json.RawMessage {
+	if len(payload) == 0 {
+		return json.RawMessage("null")
+	}
+	return payload
+}
+
+// Timestamp returns t as stored, in UTC truncated to microseconds
+func Timestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	event := Event{
+		Actor:     "alice",
+		Role:      "customer",
+		Action:    "create_transfer",
+		Target:    "transfer:1",
+		After:     json.RawMessage(`{"amount": 10}`),
+		CreatedAt: time.Date(2024, 3, 14, 10, 0, 0, 123456789, time.UTC),
+	}
+
+	hash, err := Hash(GenesisHash, event)
+	require.NoError(t, err)
+	require.Len(t, hash, 64)
+
+	// read back from the database, in another zone, truncated to microseconds and compacted
+	stored := event
+	stored.CreatedAt = time.Date(2024, 3, 14, 17, 0, 0, 123456000, time.FixedZone("WIB", 7*3600))
+	stored.After = json.RawMessage(`{"amount":10}`)
+	stored.Before = json.RawMessage("null")
+
+	again, err := Hash(GenesisHash, stored)
+	require.NoError(t, err)
+	require.Equal(t, hash, again)
+
+	// any change breaks the hash
+	for name, changed := range map[string]Event{
+		"Actor":     {Actor: "mallory", Role: event.Role, Action: event.Action, Target: event.Target, After: event.After, CreatedAt: event.CreatedAt},
+		"After":     {Actor: event.Actor, Role: event.Role, Action: event.Action, Target: event.Target, After: json.RawMessage(`{"amount":11}`), CreatedAt: event.CreatedAt},
+		"CreatedAt": {Actor: event.Actor, Role: event.Role, Action: event.Action, Target: event.Target, After: event.After, CreatedAt: event.CreatedAt.Add(time.Second)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			changedHash, err := Hash(GenesisHash, changed)
+			require.NoError(t, err)
+			require.NotEqual(t, hash, changedHash)
+		})
+	}
+
+	chained, err := Hash(hash, event)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, chained)
+}
+
+func TestHashInvalidPayload(t *testing.T) {
+	_, err := Hash(GenesisHash, Event{After: json.RawMessage("{")})
+	require.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+)
+
+// ParseTransferBatchCSV reads the items of a transfer batch, one to_account_id,amount row per item. A first row
+// naming the columns is optional, it allows them in any order and other columns, like the name of the payee, which
+// are ignored.
+func ParseTransferBatchCSV(r io.Reader) ([]entities.TransferBatchItemRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	toColumn, amountColumn := 0, 1
+	var items []entities.TransferBatchItemRequest
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, api_error.ErrInvalidBatchCSV(parseErr.Line, parseErr.Err.Error())
+			}
+			return nil, err
+		}
+
+		if first && isBatchCSVHeader(record) {
+			toColumn, amountColumn = -1, -1
+			for i, name := range record {
+				switch strings.ToLower(strings.TrimSpace(name)) {
+				case "to_account_id":
+					toColumn = i
+				case "amount":
+					amountColumn = i
+				}
+			}
+
+			if toColumn < 0 || amountColumn < 0 {
+				return nil, api_error.ErrInvalidBatchCSV(line, "header must name the to_account_id and amount columns")
+			}
+			continue
+		}
+
+		if len(record) <= toColumn || len(record) <= amountColumn {
+			return nil, api_error.ErrInvalidBatchCSV(line, "missing to_account_id or amount")
+		}
+
+		toAccountID, err := strconv.ParseInt(strings.TrimSpace(record[toColumn]), 10, 64)
+		if err != nil || toAccountID < 1 {
+			return nil, api_error.ErrInvalidBatchCSV(line, "to_account_id must be a positive integer")
+		}
+
+		amount, err := strconv.ParseInt(strings.TrimSpace(record[amountColumn]), 10, 64)
+		if err != nil || amount < 1 {
+			return nil, api_error.ErrInvalidBatchCSV(line, "amount must be a positive integer")
+		}
+
+		items = append(items, entities.TransferBatchItemRequest{ToAccountID: toAccountID, Amount: amount})
+	}
+
+	return items, nil
+}
+
+// isBatchCSVHeader reports whether the first row names columns instead of holding an item
+func isBatchCSVHeader(record []string) bool {
+	for _, field := range record {
+		if _, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64); err == nil {
+			return false
+		}
+	}
+
+	return true
+}
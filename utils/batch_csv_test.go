@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dhiemaz/bank-api/entities"
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTransferBatchCSV(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		items []entities.TransferBatchItemRequest
+	}{
+		{
+			name:  "NoHeader",
+			input: "2,100\n3, 250\n",
+			items: []entities.TransferBatchItemRequest{{ToAccountID: 2, Amount: 100}, {ToAccountID: 3, Amount: 250}},
+		},
+		{
+			name:  "Header",
+			input: "to_account_id,amount\n2,100\n",
+			items: []entities.TransferBatchItemRequest{{ToAccountID: 2, Amount: 100}},
+		},
+		{
+			name:  "ReorderedHeader",
+			input: "Amount,To_Account_ID\n100,2\n\n50,4",
+			items: []entities.TransferBatchItemRequest{{ToAccountID: 2, Amount: 100}, {ToAccountID: 4, Amount: 50}},
+		},
+		{
+			name:  "ExtraColumns",
+			input: "name,to_account_id,amount\nJohn Doe,2,100\n",
+			items: []entities.TransferBatchItemRequest{{ToAccountID: 2, Amount: 100}},
+		},
+		{
+			name:  "Empty",
+			input: "",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := ParseTransferBatchCSV(strings.NewReader(tc.input))
+			require.NoError(t, err)
+			require.Equal(t, tc.items, items)
+		})
+	}
+}
+
+func TestParseTransferBatchCSVInvalid(t *testing.T) {
+	for _, input := range []string{
+		"2,100\n3\n",
+		"2,100\n3,-5\n",
+		"2,100\n0,5\n",
+		"2,abc\n",
+		"name,amount\n2,100\n",
+		"2,\"100\n",
+	} {
+		_, err := ParseTransferBatchCSV(strings.NewReader(input))
+		require.Error(t, err, input)
+		require.True(t, api_error.IsInvalidBatchCSV(err), input)
+	}
+
+	_, err := ParseTransferBatchCSV(strings.NewReader("2,100\n3,-5\n"))
+	require.ErrorContains(t, err, "line 2")
+}
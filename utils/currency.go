@@ -1,14 +1,13 @@
 package utils
 
+import "github.com/dhiemaz/bank-api/utils/currency"
+
 const (
-	IDR = "IDR"
-	USD = "USD"
+	IDR = currency.IDR
+	USD = currency.USD
 )
 
-func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case IDR, USD:
-		return true
-	}
-	return false
+// IsSupportedCurrency reports whether the currency is enabled in this deployment, see the currency package
+func IsSupportedCurrency(code string) bool {
+	return currency.IsEnabled(code)
 }
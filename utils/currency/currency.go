@@ -0,0 +1,176 @@
+// Package currency is the registry of the currencies the bank knows about. Amounts are always kept as int64
+// counts of the minor unit of their currency (cents for USD, yen for JPY, fils for KWD), the registry knows
+// how many decimals each currency has and which currencies are enabled in this deployment.
+package currency
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Currency struct {
+	Code       string `json:"code"`
+	Number     string `json:"number"`
+	Name       string `json:"name"`
+	MinorUnits int    `json:"minor_units"`
+}
+
+// Codes of the currencies enabled by default
+const (
+	IDR = "IDR"
+	USD = "USD"
+)
+
+// DefaultEnabled are the currencies enabled when the configuration doesn't list any
+var DefaultEnabled = []string{IDR, USD}
+
+var (
+	mu      sync.RWMutex
+	enabled = mustEnabledSet(DefaultEnabled)
+)
+
+// Lookup returns the ISO 4217 currency of code, enabled or not
+func Lookup(code string) (Currency, bool) {
+	c, ok := iso4217[code]
+	return c, ok
+}
+
+// Configure replaces the set of enabled currencies, an empty list enables DefaultEnabled
+func Configure(codes []string) error {
+	if len(codes) == 0 {
+		codes = DefaultEnabled
+	}
+
+	set, err := enabledSet(codes)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = set
+
+	return nil
+}
+
+// IsEnabled reports whether accounts and transfers may use the currency in this deployment
+func IsEnabled(code string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_, ok := enabled[code]
+	return ok
+}
+
+// Enabled returns the enabled currencies sorted by code
+func Enabled() []Currency {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	currencies := make([]Currency, 0, len(enabled))
+	for _, c := range enabled {
+		currencies = append(currencies, c)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+
+	return currencies
+}
+
+// FormatAmount renders an amount of minor units of code as an exact decimal string, the raw amount is returned
+// for unknown codes
+func FormatAmount(amount int64, code string) string {
+	c, ok := Lookup(code)
+	if !ok {
+		return strconv.FormatInt(amount, 10)
+	}
+	return c.FormatAmount(amount)
+}
+
+// FormatAmount renders an amount of minor units as an exact decimal string, 12345 USD is "123.45"
+func (c Currency) FormatAmount(amount int64) string {
+	digits := strconv.FormatUint(absUint(amount), 10)
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+
+	if c.MinorUnits == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= c.MinorUnits {
+		digits = strings.Repeat("0", c.MinorUnits-len(digits)+1) + digits
+	}
+
+	point := len(digits) - c.MinorUnits
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// ParseAmount converts a decimal string into minor units, more decimals than the currency has are refused
+// rather than rounded
+func (c Currency) ParseAmount(s string) (int64, error) {
+	value := strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	whole, fraction, hasPoint := strings.Cut(value, ".")
+	if whole == "" || (hasPoint && fraction == "") || strings.ContainsAny(whole+fraction, "+-") {
+		return 0, fmt.Errorf("invalid %s amount %q", c.Code, s)
+	}
+
+	if len(fraction) > c.MinorUnits {
+		return 0, fmt.Errorf("%s amount %q has more than %d decimals", c.Code, s, c.MinorUnits)
+	}
+
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", c.MinorUnits-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s amount %q", c.Code, s)
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return minor, nil
+}
+
+// Scale returns 10^MinorUnits, the number of minor units in one major unit
+func (c Currency) Scale() int64 {
+	scale := int64(1)
+	for i := 0; i < c.MinorUnits; i++ {
+		scale *= 10
+	}
+	return scale
+}
+
+func enabledSet(codes []string) (map[string]Currency, error) {
+	set := make(map[string]Currency, len(codes))
+	for _, code := range codes {
+		c, ok := Lookup(strings.ToUpper(strings.TrimSpace(code)))
+		if !ok {
+			return nil, fmt.Errorf("unknown ISO 4217 currency %q", code)
+		}
+		set[c.Code] = c
+	}
+	return set, nil
+}
+
+func mustEnabledSet(codes []string) map[string]Currency {
+	set, err := enabledSet(codes)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+func absUint(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+	return uint64(amount)
+}
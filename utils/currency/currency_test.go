@@ -0,0 +1,106 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		code       string
+		minorUnits int
+	}{
+		{"USD", 2},
+		{"IDR", 2},
+		{"JPY", 0},
+		{"KWD", 3},
+		{"CLF", 4},
+	}
+
+	for _, tc := range testCases {
+		c, ok := Lookup(tc.code)
+		require.True(t, ok, tc.code)
+		require.Equal(t, tc.minorUnits, c.MinorUnits, tc.code)
+	}
+
+	_, ok := Lookup("XAU")
+	require.False(t, ok)
+}
+
+func TestConfigure(t *testing.T) {
+	defer Configure(nil)
+
+	require.True(t, IsEnabled("USD"))
+	require.False(t, IsEnabled("JPY"))
+
+	require.NoError(t, Configure([]string{"jpy", "KWD"}))
+	require.True(t, IsEnabled("JPY"))
+	require.True(t, IsEnabled("KWD"))
+	require.False(t, IsEnabled("USD"))
+	require.Len(t, Enabled(), 2)
+	require.Equal(t, "JPY", Enabled()[0].Code)
+
+	require.Error(t, Configure([]string{"USD", "ABC"}))
+	require.True(t, IsEnabled("JPY"))
+
+	require.NoError(t, Configure(nil))
+	require.True(t, IsEnabled("USD"))
+	require.True(t, IsEnabled("IDR"))
+}
+
+func TestFormatAmount(t *testing.T) {
+	testCases := []struct {
+		amount   int64
+		code     string
+		expected string
+	}{
+		{12345, "USD", "123.45"},
+		{5, "USD", "0.05"},
+		{-5, "USD", "-0.05"},
+		{0, "USD", "0.00"},
+		{1234, "JPY", "1234"},
+		{-1234, "JPY", "-1234"},
+		{1234, "KWD", "1.234"},
+		{-9223372036854775808, "USD", "-92233720368547758.08"},
+		{42, "ABC", "42"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, FormatAmount(tc.amount, tc.code))
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	usd, _ := Lookup("USD")
+	jpy, _ := Lookup("JPY")
+	kwd, _ := Lookup("KWD")
+
+	amount, err := usd.ParseAmount("123.45")
+	require.NoError(t, err)
+	require.Equal(t, int64(12345), amount)
+
+	amount, err = usd.ParseAmount("7.5")
+	require.NoError(t, err)
+	require.Equal(t, int64(750), amount)
+
+	amount, err = usd.ParseAmount("-0.05")
+	require.NoError(t, err)
+	require.Equal(t, int64(-5), amount)
+
+	amount, err = jpy.ParseAmount("1234")
+	require.NoError(t, err)
+	require.Equal(t, int64(1234), amount)
+
+	amount, err = kwd.ParseAmount("1.234")
+	require.NoError(t, err)
+	require.Equal(t, int64(1234), amount)
+
+	for _, invalid := range []string{"", "1.", ".5", "1.234", "abc", "1.2.3", "--1"} {
+		_, err = usd.ParseAmount(invalid)
+		require.Error(t, err, invalid)
+	}
+
+	_, err = jpy.ParseAmount("1.5")
+	require.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package currency
+
+// iso4217 lists the active ISO 4217 currencies that have minor units, precious metals and other codes without
+// minor units are left out since they can't be held in an account.
+var iso4217 = map[string]Currency{
+	"AED": {Code: "AED", Number: "784", Name: "UAE Dirham", MinorUnits: 2},
+	"AFN": {Code: "AFN", Number: "971", Name: "Afghani", MinorUnits: 2},
+	"ALL": {Code: "ALL", Number: "008", Name: "Lek", MinorUnits: 2},
+	"AMD": {Code: "AMD", Number: "051", Name: "Armenian Dram", MinorUnits: 2},
+	"AOA": {Code: "AOA", Number: "973", Name: "Kwanza", MinorUnits: 2},
+	"ARS": {Code: "ARS", Number: "032", Name: "Argentine Peso", MinorUnits: 2},
+	"AUD": {Code: "AUD", Number: "036", Name: "Australian Dollar", MinorUnits: 2},
+	"AWG": {Code: "AWG", Number: "533", Name: "Aruban Florin", MinorUnits: 2},
+	"AZN": {Code: "AZN", Number: "944", Name: "Azerbaijan Manat", MinorUnits: 2},
+	"BAM": {Code: "BAM", Number: "977", Name: "Convertible Mark", MinorUnits: 2},
+	"BBD": {Code: "BBD", Number: "052", Name: "Barbados Dollar", MinorUnits: 2},
+	"BDT": {Code: "BDT", Number: "050", Name: "Taka", MinorUnits: 2},
+	"BHD": {Code: "BHD", Number: "048", Name: "Bahraini Dinar", MinorUnits: 3},
+	"BIF": {Code: "BIF", Number: "108", Name: "Burundi Franc", MinorUnits: 0},
+	"BMD": {Code: "BMD", Number: "060", Name: "Bermudian Dollar", MinorUnits: 2},
+	"BND": {Code: "BND", Number: "096", Name: "Brunei Dollar", MinorUnits: 2},
+	"BOB": {Code: "BOB", Number: "068", Name: "Boliviano", MinorUnits: 2},
+	"BOV": {Code: "BOV", Number: "984", Name: "Mvdol", MinorUnits: 2},
+	"BRL": {Code: "BRL", Number: "986", Name: "Brazilian Real", MinorUnits: 2},
+	"BSD": {Code: "BSD", Number: "044", Name: "Bahamian Dollar", MinorUnits: 2},
+	"BTN": {Code: "BTN", Number: "064", Name: "Ngultrum", MinorUnits: 2},
+	"BWP": {Code: "BWP", Number: "072", Name: "Pula", MinorUnits: 2},
+	"BYN": {Code: "BYN", Number: "933", Name: "Belarusian Ruble", MinorUnits: 2},
+	"BZD": {Code: "BZD", Number: "084", Name: "Belize Dollar", MinorUnits: 2},
+	"CAD": {Code: "CAD", Number: "124", Name: "Canadian Dollar", MinorUnits: 2},
+	"CDF": {Code: "CDF", Number: "976", Name: "Congolese Franc", MinorUnits: 2},
+	"CHE": {Code: "CHE", Number: "947", Name: "WIR Euro", MinorUnits: 2},
+	"CHF": {Code: "CHF", Number: "756", Name: "Swiss Franc", MinorUnits: 2},
+	"CHW": {Code: "CHW", Number: "948", Name: "WIR Franc", MinorUnits: 2},
+	"CLF": {Code: "CLF", Number: "990", Name: "Unidad de Fomento", MinorUnits: 4},
+	"CLP": {Code: "CLP", Number: "152", Name: "Chilean Peso", MinorUnits: 0},
+	"CNY": {Code: "CNY", Number: "156", Name: "Yuan Renminbi", MinorUnits: 2},
+	"COP": {Code: "COP", Number: "170", Name: "Colombian Peso", MinorUnits: 2},
+	"COU": {Code: "COU", Number: "970", Name: "Unidad de Valor Real", MinorUnits: 2},
+	"CRC": {Code: "CRC", Number: "188", Name: "Costa Rican Colon", MinorUnits: 2},
+	"CUP": {Code: "CUP", Number: "192", Name: "Cuban Peso", MinorUnits: 2},
+	"CVE": {Code: "CVE", Number: "132", Name: "Cabo Verde Escudo", MinorUnits: 2},
+	"CZK": {Code: "CZK", Number: "203", Name: "Czech Koruna", MinorUnits: 2},
+	"DJF": {Code: "DJF", Number: "262", Name: "Djibouti Franc", MinorUnits: 0},
+	"DKK": {Code: "DKK", Number: "208", Name: "Danish Krone", MinorUnits: 2},
+	"DOP": {Code: "DOP", Number: "214", Name: "Dominican Peso", MinorUnits: 2},
+	"DZD": {Code: "DZD", Number: "012", Name: "Algerian Dinar", MinorUnits: 2},
+	"EGP": {Code: "EGP", Number: "818", Name: "Egyptian Pound", MinorUnits: 2},
+	"ERN": {Code: "ERN", Number: "232", Name: "Nakfa", MinorUnits: 2},
+	"ETB": {Code: "ETB", Number: "230", Name: "Ethiopian Birr", MinorUnits: 2},
+	"EUR": {Code: "EUR", Number: "978", Name: "Euro", MinorUnits: 2},
+	"FJD": {Code: "FJD", Number: "242", Name: "Fiji Dollar", MinorUnits: 2},
+	"FKP": {Code: "FKP", Number: "238", Name: "Falkland Islands Pound", MinorUnits: 2},
+	"GBP": {Code: "GBP", Number: "826", Name: "Pound Sterling", MinorUnits: 2},
+	"GEL": {Code: "GEL", Number: "981", Name: "Lari", MinorUnits: 2},
+	"GHS": {Code: "GHS", Number: "936", Name: "Ghana Cedi", MinorUnits: 2},
+	"GIP": {Code: "GIP", Number: "292", Name: "Gibraltar Pound", MinorUnits: 2},
+	"GMD": {Code: "GMD", Number: "270", Name: "Dalasi", MinorUnits: 2},
+	"GNF": {Code: "GNF", Number: "324", Name: "Guinean Franc", MinorUnits: 0},
+	"GTQ": {Code: "GTQ", Number: "320", Name: "Quetzal", MinorUnits: 2},
+	"GYD": {Code: "GYD", Number: "328", Name: "Guyana Dollar", MinorUnits: 2},
+	"HKD": {Code: "HKD", Number: "344", Name: "Hong Kong Dollar", MinorUnits: 2},
+	"HNL": {Code: "HNL", Number: "340", Name: "Lempira", MinorUnits: 2},
+	"HTG": {Code: "HTG", Number: "332", Name: "Gourde", MinorUnits: 2},
+	"HUF": {Code: "HUF", Number: "348", Name: "Forint", MinorUnits: 2},
+	"IDR": {Code: "IDR", Number: "360", Name: "Rupiah", MinorUnits: 2},
+	"ILS": {Code: "ILS", Number: "376", Name: "New Israeli Sheqel", MinorUnits: 2},
+	"INR": {Code: "INR", Number: "356", Name: "Indian Rupee", MinorUnits: 2},
+	"IQD": {Code: "IQD", Number: "368", Name: "Iraqi Dinar", MinorUnits: 3},
+	"IRR": {Code: "IRR", Number: "364", Name: "Iranian Rial", MinorUnits: 2},
+	"ISK": {Code: "ISK", Number: "352", Name: "Iceland Krona", MinorUnits: 0},
+	"JMD": {Code: "JMD", Number: "388", Name: "Jamaican Dollar", MinorUnits: 2},
+	"JOD": {Code: "JOD", Number: "400", Name: "Jordanian Dinar", MinorUnits: 3},
+	"JPY": {Code: "JPY", Number: "392", Name: "Yen", MinorUnits: 0},
+	"KES": {Code: "KES", Number: "404", Name: "Kenyan Shilling", MinorUnits: 2},
+	"KGS": {Code: "KGS", Number: "417", Name: "Som", MinorUnits: 2},
+	"KHR": {Code: "KHR", Number: "116", Name: "Riel", MinorUnits: 2},
+	"KMF": {Code: "KMF", Number: "174", Name: "Comorian Franc", MinorUnits: 0},
+	"KPW": {Code: "KPW", Number: "408", Name: "North Korean Won", MinorUnits: 2},
+	"KRW": {Code: "KRW", Number: "410", Name: "Won", MinorUnits: 0},
+	"KWD": {Code: "KWD", Number: "414", Name: "Kuwaiti Dinar", MinorUnits: 3},
+	"KYD": {Code: "KYD", Number: "136", Name: "Cayman Islands Dollar", MinorUnits: 2},
+	"KZT": {Code: "KZT", Number: "398", Name: "Tenge", MinorUnits: 2},
+	"LAK": {Code: "LAK", Number: "418", Name: "Lao Kip", MinorUnits: 2},
+	"LBP": {Code: "LBP", Number: "422", Name: "Lebanese Pound", MinorUnits: 2},
+	"LKR": {Code: "LKR", Number: "144", Name: "Sri Lanka Rupee", MinorUnits: 2},
+	"LRD": {Code: "LRD", Number: "430", Name: "Liberian Dollar", MinorUnits: 2},
+	"LSL": {Code: "LSL", Number: "426", Name: "Loti", MinorUnits: 2},
+	"LYD": {Code: "LYD", Number: "434", Name: "Libyan Dinar", MinorUnits: 3},
+	"MAD": {Code: "MAD", Number: "504", Name: "Moroccan Dirham", MinorUnits: 2},
+	"MDL": {Code: "MDL", Number: "498", Name: "Moldovan Leu", MinorUnits: 2},
+	"MGA": {Code: "MGA", Number: "969", Name: "Malagasy Ariary", MinorUnits: 2},
+	"MKD": {Code: "MKD", Number: "807", Name: "Denar", MinorUnits: 2},
+	"MMK": {Code: "MMK", Number: "104", Name: "Kyat", MinorUnits: 2},
+	"MNT": {Code: "MNT", Number: "496", Name: "Tugrik", MinorUnits: 2},
+	"MOP": {Code: "MOP", Number: "446", Name: "Pataca", MinorUnits: 2},
+	"MRU": {Code: "MRU", Number: "929", Name: "Ouguiya", MinorUnits: 2},
+	"MUR": {Code: "MUR", Number: "480", Name: "Mauritius Rupee", MinorUnits: 2},
+	"MVR": {Code: "MVR", Number: "462", Name: "Rufiyaa", MinorUnits: 2},
+	"MWK": {Code: "MWK", Number: "454", Name: "Malawi Kwacha", MinorUnits: 2},
+	"MXN": {Code: "MXN", Number: "484", Name: "Mexican Peso", MinorUnits: 2},
+	"MXV": {Code: "MXV", Number: "979", Name: "Mexican Unidad de Inversion (UDI)", MinorUnits: 2},
+	"MYR": {Code: "MYR", Number: "458", Name: "Malaysian Ringgit", MinorUnits: 2},
+	"MZN": {Code: "MZN", Number: "943", Name: "Mozambique Metical", MinorUnits: 2},
+	"NAD": {Code: "NAD", Number: "516", Name: "Namibia Dollar", MinorUnits: 2},
+	"NGN": {Code: "NGN", Number: "566", Name: "Naira", MinorUnits: 2},
+	"NIO": {Code: "NIO", Number: "558", Name: "Cordoba Oro", MinorUnits: 2},
+	"NOK": {Code: "NOK", Number: "578", Name: "Norwegian Krone", MinorUnits: 2},
+	"NPR": {Code: "NPR", Number: "524", Name: "Nepalese Rupee", MinorUnits: 2},
+	"NZD": {Code: "NZD", Number: "554", Name: "New Zealand Dollar", MinorUnits: 2},
+	"OMR": {Code: "OMR", Number: "512", Name: "Rial Omani", MinorUnits: 3},
+	"PAB": {Code: "PAB", Number: "590", Name: "Balboa", MinorUnits: 2},
+	"PEN": {Code: "PEN", Number: "604", Name: "Sol", MinorUnits: 2},
+	"PGK": {Code: "PGK", Number: "598", Name: "Kina", MinorUnits: 2},
+	"PHP": {Code: "PHP", Number: "608", Name: "Philippine Peso", MinorUnits: 2},
+	"PKR": {Code: "PKR", Number: "586", Name: "Pakistan Rupee", MinorUnits: 2},
+	"PLN": {Code: "PLN", Number: "985", Name: "Zloty", MinorUnits: 2},
+	"PYG": {Code: "PYG", Number: "600", Name: "Guarani", MinorUnits: 0},
+	"QAR": {Code: "QAR", Number: "634", Name: "Qatari Rial", MinorUnits: 2},
+	"RON": {Code: "RON", Number: "946", Name: "Romanian Leu", MinorUnits: 2},
+	"RSD": {Code: "RSD", Number: "941", Name: "Serbian Dinar", MinorUnits: 2},
+	"RUB": {Code: "RUB", Number: "643", Name: "Russian Ruble", MinorUnits: 2},
+	"RWF": {Code: "RWF", Number: "646", Name: "Rwanda Franc", MinorUnits: 0},
+	"SAR": {Code: "SAR", Number: "682", Name: "Saudi Riyal", MinorUnits: 2},
+	"SBD": {Code: "SBD", Number: "090", Name: "Solomon Islands Dollar", MinorUnits: 2},
+	"SCR": {Code: "SCR", Number: "690", Name: "Seychelles Rupee", MinorUnits: 2},
+	"SDG": {Code: "SDG", Number: "938", Name: "Sudanese Pound", MinorUnits: 2},
+	"SEK": {Code: "SEK", Number: "752", Name: "Swedish Krona", MinorUnits: 2},
+	"SGD": {Code: "SGD", Number: "702", Name: "Singapore Dollar", MinorUnits: 2},
+	"SHP": {Code: "SHP", Number: "654", Name: "Saint Helena Pound", MinorUnits: 2},
+	"SLE": {Code: "SLE", Number: "925", Name: "Leone", MinorUnits: 2},
+	"SOS": {Code: "SOS", Number: "706", Name: "Somali Shilling", MinorUnits: 2},
+	"SRD": {Code: "SRD", Number: "968", Name: "Surinam Dollar", MinorUnits: 2},
+	"SSP": {Code: "SSP", Number: "728", Name: "South Sudanese Pound", MinorUnits: 2},
+	"STN": {Code: "STN", Number: "930", Name: "Dobra", MinorUnits: 2},
+	"SVC": {Code: "SVC", Number: "222", Name: "El Salvador Colon", MinorUnits: 2},
+	"SYP": {Code: "SYP", Number: "760", Name: "Syrian Pound", MinorUnits: 2},
+	"SZL": {Code: "SZL", Number: "748", Name: "Lilangeni", MinorUnits: 2},
+	"THB": {Code: "THB", Number: "764", Name: "Baht", MinorUnits: 2},
+	"TJS": {Code: "TJS", Number: "972", Name: "Somoni", MinorUnits: 2},
+	"TMT": {Code: "TMT", Number: "934", Name: "Turkmenistan New Manat", MinorUnits: 2},
+	"TND": {Code: "TND", Number: "788", Name: "Tunisian Dinar", MinorUnits: 3},
+	"TOP": {Code: "TOP", Number: "776", Name: "Pa'anga", MinorUnits: 2},
+	"TRY": {Code: "TRY", Number: "949", Name: "Turkish Lira", MinorUnits: 2},
+	"TTD": {Code: "TTD", Number: "780", Name: "Trinidad and Tobago Dollar", MinorUnits: 2},
+	"TWD": {Code: "TWD", Number: "901", Name: "New Taiwan Dollar", MinorUnits: 2},
+	"TZS": {Code: "TZS", Number: "834", Name: "Tanzanian Shilling", MinorUnits: 2},
+	"UAH": {Code: "UAH", Number: "980", Name: "Hryvnia", MinorUnits: 2},
+	"UGX": {Code: "UGX", Number: "800", Name: "Uganda Shilling", MinorUnits: 0},
+	"USD": {Code: "USD", Number: "840", Name: "US Dollar", MinorUnits: 2},
+	"USN": {Code: "USN", Number: "997", Name: "US Dollar (Next day)", MinorUnits: 2},
+	"UYI": {Code: "UYI", Number: "940", Name: "Uruguay Peso en Unidades Indexadas (UI)", MinorUnits: 0},
+	"UYU": {Code: "UYU", Number: "858", Name: "Peso Uruguayo", MinorUnits: 2},
+	"UYW": {Code: "UYW", Number: "927", Name: "Unidad Previsional", MinorUnits: 4},
+	"UZS": {Code: "UZS", Number: "860", Name: "Uzbekistan Sum", MinorUnits: 2},
+	"VED": {Code: "VED", Number: "926", Name: "Bolivar Soberano", MinorUnits: 2},
+	"VES": {Code: "VES", Number: "928", Name: "Bolivar Soberano", MinorUnits: 2},
+	"VND": {Code: "VND", Number: "704", Name: "Dong", MinorUnits: 0},
+	"VUV": {Code: "VUV", Number: "548", Name: "Vatu", MinorUnits: 0},
+	"WST": {Code: "WST", Number: "882", Name: "Tala", MinorUnits: 2},
+	"XAF": {Code: "XAF", Number: "950", Name: "CFA Franc BEAC", MinorUnits: 0},
+	"XCD": {Code: "XCD", Number: "951", Name: "East Caribbean Dollar", MinorUnits: 2},
+	"XCG": {Code: "XCG", Number: "532", Name: "Caribbean Guilder", MinorUnits: 2},
+	"XOF": {Code: "XOF", Number: "952", Name: "CFA Franc BCEAO", MinorUnits: 0},
+	"XPF": {Code: "XPF", Number: "953", Name: "CFP Franc", MinorUnits: 0},
+	"YER": {Code: "YER", Number: "886", Name: "Yemeni Rial", MinorUnits: 2},
+	"ZAR": {Code: "ZAR", Number: "710", Name: "Rand", MinorUnits: 2},
+	"ZMW": {Code: "ZMW", Number: "967", Name: "Zambian Kwacha", MinorUnits: 2},
+	"ZWG": {Code: "ZWG", Number: "924", Name: "Zimbabwe Gold", MinorUnits: 2},
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+)
+
+// EncodeCursor builds an opaque keyset pagination cursor from the sort key of the last row of a page
+func EncodeCursor(createdAt time.Time, id int64) string {
+	raw := strconv.FormatInt(createdAt.UnixNano(), 10) + ":" + strconv.FormatInt(id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor, any malformed cursor is reported as api_error.ErrInvalidCursor
+func DecodeCursor(cursor string) (time.Time, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, api_error.ErrInvalidCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, 0, api_error.ErrInvalidCursor
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, api_error.ErrInvalidCursor
+	}
+
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || rowID <= 0 {
+		return time.Time{}, 0, api_error.ErrInvalidCursor
+	}
+
+	return time.Unix(0, unixNano), rowID, nil
+}
+
+// EncodeKeyCursor builds an opaque cursor for lists sorted on a unique text column, like users on username
+func EncodeKeyCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// DecodeKeyCursor reverses EncodeKeyCursor, any malformed cursor is reported as api_error.ErrInvalidCursor
+func DecodeKeyCursor(cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) == 0 {
+		return "", api_error.ErrInvalidCursor
+	}
+
+	return string(raw), nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 30, 0, 123456000, time.UTC)
+
+	cursor := EncodeCursor(createdAt, 42)
+	require.NotEmpty(t, cursor)
+
+	decodedAt, id, err := DecodeCursor(cursor)
+	require.NoError(t, err)
+	require.True(t, createdAt.Equal(decodedAt))
+	require.Equal(t, int64(42), id)
+
+	for _, invalid := range []string{"", "%%%", "bm9jb2xvbg", "YWJjOjE", "MTIzOmFiYw", "MTIzOjA"} {
+		_, _, err = DecodeCursor(invalid)
+		require.ErrorIs(t, err, api_error.ErrInvalidCursor, invalid)
+	}
+}
+
+func TestKeyCursor(t *testing.T) {
+	cursor := EncodeKeyCursor("johndoe1")
+	require.NotEmpty(t, cursor)
+
+	key, err := DecodeKeyCursor(cursor)
+	require.NoError(t, err)
+	require.Equal(t, "johndoe1", key)
+
+	for _, invalid := range []string{"", "%%%"} {
+		_, err = DecodeKeyCursor(invalid)
+		require.ErrorIs(t, err, api_error.ErrInvalidCursor, invalid)
+	}
+}
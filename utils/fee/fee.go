@@ -0,0 +1,206 @@
+package fee
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	KindFlat       = "flat"
+	KindPercentage = "percentage"
+	KindTiered     = "tiered"
+)
+
+// Transfer types a rule can be limited to, a rule without a type applies to every type of its currency that has no
+// rule of its own
+const (
+	TypeTransfer  = "transfer"
+	TypeFx        = "fx"
+	TypeScheduled = "scheduled"
+	TypeBatch     = "batch"
+)
+
+// basisPointsScale is 100%, percentages are configured in basis points so that 0.25% is 25
+const basisPointsScale = 10000
+
+// Tier prices the amounts up to UpTo, the last tier leaves UpTo at zero to take every larger amount
+type Tier struct {
+	UpTo        int64 `mapstructure:"up_to" json:"up_to"`
+	Flat        int64 `mapstructure:"flat" json:"flat,omitempty"`
+	BasisPoints int64 `mapstructure:"basis_points" json:"basis_points,omitempty"`
+}
+
+// Rule is the fee of a currency and transfer type, in minor units of the currency. Min and Max bound percentage and
+// tiered fees, zero leaves them unbounded.
+type Rule struct {
+	Currency     string `mapstructure:"currency"`
+	TransferType string `mapstructure:"transfer_type"`
+	Kind         string `mapstructure:"kind"`
+	Flat         int64  `mapstructure:"flat"`
+	BasisPoints  int64  `mapstructure:"basis_points"`
+	Min          int64  `mapstructure:"min"`
+	Max          int64  `mapstructure:"max"`
+	Tiers        []Tier `mapstructure:"tiers"`
+}
+
+// Breakdown is the fee charged for a transfer and how it was computed, Amount is zero when no rule applies
+type Breakdown struct {
+	Currency     string `json:"currency"`
+	TransferType string `json:"transfer_type"`
+	Kind         string `json:"kind,omitempty"`
+	Flat         int64  `json:"flat,omitempty"`
+	BasisPoints  int64  `json:"basis_points,omitempty"`
+	// Tier is the upper bound of the tier applied, zero for the last tier
+	Tier *int64 `json:"tier,omitempty"`
+	// Capped is true when Min or Max replaced the computed fee
+	Capped bool  `json:"capped,omitempty"`
+	Amount int64 `json:"amount"`
+	// RevenueAccountID is the house account the fee is credited to
+	RevenueAccountID int64 `json:"revenue_account_id,omitempty"`
+}
+
+type ruleKey struct {
+	currency     string
+	transferType string
+}
+
+// Schedule looks up the fee of transfers, a nil Schedule charges no fees
+type Schedule struct {
+	rules           map[ruleKey]Rule
+	revenueAccounts map[string]int64
+}
+
+// NewSchedule checks the rules, every currency with a rule needs a revenue account to credit its fees to
+func NewSchedule(rules []Rule, revenueAccounts map[string]int64) (*Schedule, error) {
+	schedule := &Schedule{rules: make(map[ruleKey]Rule, len(rules)), revenueAccounts: revenueAccounts}
+
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("fee rule %s/%s: %w", rule.Currency, rule.TransferType, err)
+		}
+
+		if _, ok := revenueAccounts[rule.Currency]; !ok {
+			return nil, fmt.Errorf("fee rule %s/%s: no revenue account for currency %s", rule.Currency, rule.TransferType, rule.Currency)
+		}
+
+		key := ruleKey{rule.Currency, rule.TransferType}
+		if _, ok := schedule.rules[key]; ok {
+			return nil, fmt.Errorf("fee rule %s/%s: duplicated", rule.Currency, rule.TransferType)
+		}
+
+		if rule.Kind == KindTiered {
+			rule.Tiers = append([]Tier(nil), rule.Tiers...)
+			sort.Slice(rule.Tiers, func(i, j int) bool {
+				return rule.Tiers[j].UpTo == 0 || (rule.Tiers[i].UpTo != 0 && rule.Tiers[i].UpTo < rule.Tiers[j].UpTo)
+			})
+		}
+
+		schedule.rules[key] = rule
+	}
+
+	return schedule, nil
+}
+
+// Quote computes the fee of a transfer of amount minor units of currency
+func (schedule *Schedule) Quote(currency, transferType string, amount int64) Breakdown {
+	breakdown := Breakdown{Currency: currency, TransferType: transferType}
+	if schedule == nil {
+		return breakdown
+	}
+
+	rule, ok := schedule.rules[ruleKey{currency, transferType}]
+	if !ok {
+		rule, ok = schedule.rules[ruleKey{currency, ""}]
+	}
+
+	if !ok {
+		return breakdown
+	}
+
+	breakdown.Kind = rule.Kind
+	breakdown.RevenueAccountID = schedule.revenueAccounts[currency]
+
+	switch rule.Kind {
+	case KindFlat:
+		breakdown.Flat = rule.Flat
+	case KindPercentage:
+		breakdown.BasisPoints = rule.BasisPoints
+	case KindTiered:
+		tier := rule.Tiers[len(rule.Tiers)-1]
+		for _, candidate := range rule.Tiers {
+			if candidate.UpTo == 0 || amount <= candidate.UpTo {
+				tier = candidate
+				break
+			}
+		}
+
+		upTo := tier.UpTo
+		breakdown.Tier = &upTo
+		breakdown.Flat, breakdown.BasisPoints = tier.Flat, tier.BasisPoints
+	}
+
+	breakdown.Amount = breakdown.Flat + percentage(amount, breakdown.BasisPoints)
+
+	if rule.Kind != KindFlat {
+		switch {
+		case rule.Min > 0 && breakdown.Amount < rule.Min:
+			breakdown.Amount, breakdown.Capped = rule.Min, true
+		case rule.Max > 0 && breakdown.Amount > rule.Max:
+			breakdown.Amount, breakdown.Capped = rule.Max, true
+		}
+	}
+
+	return breakdown
+}
+
+// percentage returns basisPoints of amount rounded half up, without overflowing for large amounts
+func percentage(amount, basisPoints int64) int64 {
+	return amount/basisPointsScale*basisPoints + (amount%basisPointsScale*basisPoints+basisPointsScale/2)/basisPointsScale
+}
+
+func (rule Rule) validate() error {
+	if rule.Currency == "" {
+		return fmt.Errorf("currency is required")
+	}
+
+	switch rule.TransferType {
+	case "", TypeTransfer, TypeFx, TypeScheduled, TypeBatch:
+	default:
+		return fmt.Errorf("unknown transfer type %q", rule.TransferType)
+	}
+
+	if rule.Flat < 0 || rule.BasisPoints < 0 || rule.Min < 0 || rule.Max < 0 {
+		return fmt.Errorf("fees can't be negative")
+	}
+
+	if rule.Max > 0 && rule.Min > rule.Max {
+		return fmt.Errorf("min is above max")
+	}
+
+	switch rule.Kind {
+	case KindFlat, KindPercentage:
+	case KindTiered:
+		if len(rule.Tiers) == 0 {
+			return fmt.Errorf("tiered fee needs tiers")
+		}
+
+		unbounded := 0
+		for _, tier := range rule.Tiers {
+			if tier.UpTo < 0 || tier.Flat < 0 || tier.BasisPoints < 0 {
+				return fmt.Errorf("tiers can't be negative")
+			}
+
+			if tier.UpTo == 0 {
+				unbounded++
+			}
+		}
+
+		if unbounded > 1 {
+			return fmt.Errorf("only the last tier can leave up_to unset")
+		}
+	default:
+		return fmt.Errorf("unknown fee kind %q", rule.Kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package fee
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSchedule(t *testing.T) *Schedule {
+	schedule, err := NewSchedule([]Rule{
+		{Currency: "IDR", TransferType: TypeTransfer, Kind: KindFlat, Flat: 2500},
+		{Currency: "IDR", Kind: KindFlat, Flat: 5000},
+		{Currency: "USD", TransferType: TypeFx, Kind: KindPercentage, BasisPoints: 50, Min: 100, Max: 2000},
+		{Currency: "USD", TransferType: TypeBatch, Kind: KindTiered, Max: 500, Tiers: []Tier{
+			{UpTo: 0, BasisPoints: 10},
+			{UpTo: 10000, Flat: 25},
+			{UpTo: 100000, Flat: 10, BasisPoints: 5},
+		}},
+	}, map[string]int64{"IDR": 3, "USD": 4})
+	require.NoError(t, err)
+
+	return schedule
+}
+
+func TestScheduleQuote(t *testing.T) {
+	schedule := testSchedule(t)
+
+	testCases := []struct {
+		name         string
+		currency     string
+		transferType string
+		amount       int64
+		fee          int64
+		capped       bool
+	}{
+		{"Flat", "IDR", TypeTransfer, 1000000, 2500, false},
+		{"AnyTypeOfCurrency", "IDR", TypeScheduled, 1000000, 5000, false},
+		{"NoRule", "USD", TypeTransfer, 1000000, 0, false},
+		{"UnknownCurrency", "EUR", TypeTransfer, 1000000, 0, false},
+		{"Percentage", "USD", TypeFx, 100000, 500, false},
+		{"PercentageRoundsHalfUp", "USD", TypeFx, 100100, 501, false},
+		{"PercentageMin", "USD", TypeFx, 1000, 100, true},
+		{"PercentageMax", "USD", TypeFx, 10000000, 2000, true},
+		{"FirstTier", "USD", TypeBatch, 10000, 25, false},
+		{"SecondTier", "USD", TypeBatch, 50000, 35, false},
+		{"LastTier", "USD", TypeBatch, 200000, 200, false},
+		{"TieredMax", "USD", TypeBatch, 10000000, 500, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			breakdown := schedule.Quote(tc.currency, tc.transferType, tc.amount)
+			require.Equal(t, tc.fee, breakdown.Amount)
+			require.Equal(t, tc.capped, breakdown.Capped)
+			require.Equal(t, tc.currency, breakdown.Currency)
+			require.Equal(t, tc.transferType, breakdown.TransferType)
+
+			if tc.fee > 0 {
+				require.NotZero(t, breakdown.RevenueAccountID)
+			}
+		})
+	}
+
+	breakdown := schedule.Quote("USD", TypeBatch, 50000)
+	require.NotNil(t, breakdown.Tier)
+	require.Equal(t, int64(100000), *breakdown.Tier)
+}
+
+func TestNilScheduleQuote(t *testing.T) {
+	var schedule *Schedule
+	require.Zero(t, schedule.Quote("USD", TypeTransfer, 1000).Amount)
+}
+
+func TestPercentageLargeAmount(t *testing.T) {
+	// 1% of the largest amount
+	require.Equal(t, int64(math.MaxInt64/100), percentage(math.MaxInt64, 100))
+}
+
+func TestNewScheduleInvalid(t *testing.T) {
+	revenueAccounts := map[string]int64{"USD": 4}
+
+	for _, rules := range [][]Rule{
+		{{Currency: "USD", Kind: "free"}},
+		{{Currency: "USD", TransferType: "wire", Kind: KindFlat}},
+		{{Currency: "USD", Kind: KindFlat, Flat: -1}},
+		{{Currency: "USD", Kind: KindPercentage, Min: 10, Max: 5}},
+		{{Currency: "USD", Kind: KindTiered}},
+		{{Currency: "USD", Kind: KindTiered, Tiers: []Tier{{Flat: 1}, {Flat: 2}}}},
+		{{Currency: "IDR", Kind: KindFlat, Flat: 1}},
+		{{Currency: "USD", Kind: KindFlat}, {Currency: "USD", Kind: KindFlat}},
+	} {
+		_, err := NewSchedule(rules, revenueAccounts)
+		require.Error(t, err, rules)
+	}
+}
@@ -0,0 +1,153 @@
+package interest
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Day count conventions, they set the fraction of the annual rate earned on one day
+const (
+	DayCountActual365    = "actual/365"
+	DayCountActual360    = "actual/360"
+	DayCountActualActual = "actual/actual"
+	// DayCount30360 counts every month as 30 days, the 31st earns nothing and the last day of February earns the
+	// days up to the 30th
+	DayCount30360 = "30/360"
+)
+
+// DefaultProduct is the product of accounts opened without one, it earns nothing unless it is configured
+const DefaultProduct = "checking"
+
+// MicrosPerUnit is the number of accrual units in a minor unit, interest accrues in millionths of minor units so
+// that the daily interest of small balances isn't lost to rounding
+const MicrosPerUnit = 1000000
+
+// basisPointsScale is 100%, rates are configured in basis points so that 2.5% is 250
+const basisPointsScale = 10000
+
+// Product is an account product and the interest its accounts earn
+type Product struct {
+	Name                  string `mapstructure:"name" json:"name"`
+	AnnualRateBasisPoints int64  `mapstructure:"annual_rate_basis_points" json:"annual_rate_basis_points"`
+	DayCount              string `mapstructure:"day_count" json:"day_count"`
+}
+
+// Catalog holds the account products, a nil Catalog only knows the default product
+type Catalog struct {
+	products map[string]Product
+}
+
+func NewCatalog(products []Product) (*Catalog, error) {
+	catalog := &Catalog{products: map[string]Product{
+		DefaultProduct: {Name: DefaultProduct, DayCount: DayCountActual365},
+	}}
+
+	seen := make(map[string]bool, len(products))
+	for _, product := range products {
+		if product.Name == "" {
+			return nil, fmt.Errorf("account products need a name")
+		}
+
+		if seen[product.Name] {
+			return nil, fmt.Errorf("account product %s: duplicated", product.Name)
+		}
+
+		if product.AnnualRateBasisPoints < 0 {
+			return nil, fmt.Errorf("account product %s: rate can't be negative", product.Name)
+		}
+
+		switch product.DayCount {
+		case DayCountActual365, DayCountActual360, DayCountActualActual, DayCount30360:
+		default:
+			return nil, fmt.Errorf("account product %s: unknown day count %q", product.Name, product.DayCount)
+		}
+
+		seen[product.Name] = true
+		catalog.products[product.Name] = product
+	}
+
+	return catalog, nil
+}
+
+// Lookup returns a product by name
+func (catalog *Catalog) Lookup(name string) (Product, bool) {
+	if catalog == nil {
+		return Product{Name: DefaultProduct, DayCount: DayCountActual365}, name == DefaultProduct
+	}
+
+	product, ok := catalog.products[name]
+	return product, ok
+}
+
+// Accruing returns the names of the products earning interest, sorted
+func (catalog *Catalog) Accruing() []string {
+	names := []string{}
+	if catalog == nil {
+		return names
+	}
+
+	for name, product := range catalog.products {
+		if product.AnnualRateBasisPoints > 0 {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// DailyAccrual is the interest earned by an end of day balance of date, in millionths of minor units rounded down.
+// Balances at or below zero earn nothing.
+func (product Product) DailyAccrual(balance int64, date time.Time) int64 {
+	if balance <= 0 || product.AnnualRateBasisPoints <= 0 {
+		return 0
+	}
+
+	days, yearDays := DayFraction(product.DayCount, date)
+	if days == 0 {
+		return 0
+	}
+
+	accrual := new(big.Int).SetInt64(balance)
+	accrual.Mul(accrual, big.NewInt(product.AnnualRateBasisPoints))
+	accrual.Mul(accrual, big.NewInt(days*MicrosPerUnit))
+	accrual.Quo(accrual, big.NewInt(basisPointsScale*yearDays))
+
+	return accrual.Int64()
+}
+
+// DayFraction returns the days earned on date and the days of the year for a day count convention
+func DayFraction(dayCount string, date time.Time) (days, yearDays int64) {
+	switch dayCount {
+	case DayCountActual360:
+		return 1, 360
+	case DayCountActualActual:
+		if isLeap(date.Year()) {
+			return 1, 366
+		}
+		return 1, 365
+	case DayCount30360:
+		lastOfMonth := date.AddDate(0, 0, 1).Day() == 1
+		switch {
+		case date.Day() == 31:
+			return 0, 360
+		case date.Month() == time.February && lastOfMonth:
+			return int64(30 - date.Day() + 1), 360
+		default:
+			return 1, 360
+		}
+	default:
+		return 1, 365
+	}
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// ToMinorUnits rounds accrued micros down to minor units
+func ToMinorUnits(micros int64) int64 {
+	return micros / MicrosPerUnit
+}
@@ -0,0 +1,119 @@
+package interest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDayFraction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dayCount string
+		date     time.Time
+		days     int64
+		yearDays int64
+	}{
+		{name: "Actual365", dayCount: DayCountActual365, date: date(2024, 2, 29), days: 1, yearDays: 365},
+		{name: "Actual360", dayCount: DayCountActual360, date: date(2024, 3, 31), days: 1, yearDays: 360},
+		{name: "ActualActualLeap", dayCount: DayCountActualActual, date: date(2024, 6, 1), days: 1, yearDays: 366},
+		{name: "ActualActual", dayCount: DayCountActualActual, date: date(2100, 6, 1), days: 1, yearDays: 365},
+		{name: "30360", dayCount: DayCount30360, date: date(2024, 4, 30), days: 1, yearDays: 360},
+		{name: "30360Day31", dayCount: DayCount30360, date: date(2024, 3, 31), days: 0, yearDays: 360},
+		{name: "30360EndOfFebruary", dayCount: DayCount30360, date: date(2023, 2, 28), days: 3, yearDays: 360},
+		{name: "30360EndOfLeapFebruary", dayCount: DayCount30360, date: date(2024, 2, 29), days: 2, yearDays: 360},
+		{name: "30360February28OfLeapYear", dayCount: DayCount30360, date: date(2024, 2, 28), days: 1, yearDays: 360},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			days, yearDays := DayFraction(tc.dayCount, tc.date)
+			require.Equal(t, tc.days, days)
+			require.Equal(t, tc.yearDays, yearDays)
+		})
+	}
+}
+
+func TestDayFraction30360MonthTotals(t *testing.T) {
+	for _, year := range []int{2023, 2024} {
+		for month := time.January; month <= time.December; month++ {
+			var total int64
+			for day := date(year, month, 1); day.Month() == month; day = day.AddDate(0, 0, 1) {
+				days, _ := DayFraction(DayCount30360, day)
+				total += days
+			}
+			require.Equal(t, int64(30), total, "%d-%02d", year, month)
+		}
+	}
+}
+
+func TestDailyAccrual(t *testing.T) {
+	savings := Product{Name: "savings", AnnualRateBasisPoints: 365, DayCount: DayCountActual365}
+
+	// 3.65% of 1,000,000 over 365 days is 100 a day
+	require.Equal(t, int64(100*MicrosPerUnit), savings.DailyAccrual(1000000, date(2024, 3, 1)))
+
+	// fractions of minor units are kept
+	require.Equal(t, int64(100), savings.DailyAccrual(1, date(2024, 3, 1)))
+
+	require.Zero(t, savings.DailyAccrual(0, date(2024, 3, 1)))
+	require.Zero(t, savings.DailyAccrual(-1000000, date(2024, 3, 1)))
+
+	// no overflow for large balances
+	require.Equal(t, int64(100000000*MicrosPerUnit), savings.DailyAccrual(1000000000000, date(2024, 3, 1)))
+
+	thirty := Product{Name: "savings", AnnualRateBasisPoints: 360, DayCount: DayCount30360}
+	require.Zero(t, thirty.DailyAccrual(1000000, date(2024, 3, 31)))
+	require.Equal(t, int64(300*MicrosPerUnit), thirty.DailyAccrual(1000000, date(2023, 2, 28)))
+
+	require.Equal(t, int64(1), ToMinorUnits(1999999))
+}
+
+func TestCatalog(t *testing.T) {
+	catalog, err := NewCatalog([]Product{
+		{Name: "savings", AnnualRateBasisPoints: 250, DayCount: DayCountActual365},
+		{Name: "current", DayCount: DayCountActual360},
+	})
+	require.NoError(t, err)
+
+	product, ok := catalog.Lookup("savings")
+	require.True(t, ok)
+	require.Equal(t, int64(250), product.AnnualRateBasisPoints)
+
+	_, ok = catalog.Lookup(DefaultProduct)
+	require.True(t, ok)
+
+	_, ok = catalog.Lookup("gold")
+	require.False(t, ok)
+
+	require.Equal(t, []string{"savings"}, catalog.Accruing())
+
+	var none *Catalog
+	_, ok = none.Lookup(DefaultProduct)
+	require.True(t, ok)
+	require.Empty(t, none.Accruing())
+}
+
+func TestNewCatalogInvalid(t *testing.T) {
+	testCases := map[string][]Product{
+		"NoName":          {{DayCount: DayCountActual365}},
+		"NegativeRate":    {{Name: "savings", AnnualRateBasisPoints: -1, DayCount: DayCountActual365}},
+		"UnknownDayCount": {{Name: "savings", DayCount: "actual/364"}},
+		"Duplicated": {
+			{Name: "savings", DayCount: DayCountActual365},
+			{Name: "savings", DayCount: DayCountActual360},
+		},
+	}
+
+	for name, products := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewCatalog(products)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package limit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+)
+
+// DefaultTier is the tier of users nobody moved to another one
+const DefaultTier = "standard"
+
+// Names of the limits, as reported by api_error.LimitExceededError
+const (
+	MaxSingle    = "max_single"
+	DailyTotal   = "daily_total"
+	MonthlyTotal = "monthly_total"
+	HourlyCount  = "hourly_count"
+)
+
+// Limits of the outgoing transfers of a tier in one currency, amounts are in minor units and zero leaves a limit off.
+// Users have a single account per currency, so the limits of a currency are the limits of that account.
+type Limits struct {
+	Tier         string `mapstructure:"tier" json:"tier"`
+	Currency     string `mapstructure:"currency" json:"currency"`
+	MaxSingle    int64  `mapstructure:"max_single" json:"max_single"`
+	DailyTotal   int64  `mapstructure:"daily_total" json:"daily_total"`
+	MonthlyTotal int64  `mapstructure:"monthly_total" json:"monthly_total"`
+	HourlyCount  int64  `mapstructure:"hourly_count" json:"hourly_count"`
+}
+
+// Usage is what an account already sent in the current windows
+type Usage struct {
+	DailyTotal   int64 `json:"daily_total"`
+	MonthlyTotal int64 `json:"monthly_total"`
+	HourlyCount  int64 `json:"hourly_count"`
+	// HourlyOldest is when the oldest transfer of the hour was sent, zero when none was
+	HourlyOldest time.Time `json:"-"`
+}
+
+// Add returns the usage after one more transfer of amount
+func (usage Usage) Add(amount int64) Usage {
+	return Usage{
+		DailyTotal:   usage.DailyTotal + amount,
+		MonthlyTotal: usage.MonthlyTotal + amount,
+		HourlyCount:  usage.HourlyCount + 1,
+		HourlyOldest: usage.HourlyOldest,
+	}
+}
+
+// Windows are the periods the usage is counted over. The day and the month are calendar periods in UTC, the hour
+// is the last 60 minutes.
+type Windows struct {
+	HourStart  time.Time
+	DayStart   time.Time
+	DayEnd     time.Time
+	MonthStart time.Time
+	MonthEnd   time.Time
+}
+
+func WindowsAt(now time.Time) Windows {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return Windows{
+		HourStart:  now.Add(-time.Hour),
+		DayStart:   day,
+		DayEnd:     day.AddDate(0, 0, 1),
+		MonthStart: month,
+		MonthEnd:   month.AddDate(0, 1, 0),
+	}
+}
+
+// hourlyResetsAt is when the oldest transfer of the rolling hour leaves it. Transfers not recorded yet, like the
+// earlier items of a batch, leave it an hour from now.
+func (windows Windows) hourlyResetsAt(usage Usage) time.Time {
+	oldest := usage.HourlyOldest
+	if oldest.IsZero() {
+		oldest = windows.HourStart.Add(time.Hour)
+	}
+	return oldest.Add(time.Hour)
+}
+
+// Check returns an api_error.LimitExceededError for the first limit a transfer of amount would exceed
+func (limits Limits) Check(usage Usage, amount int64, windows Windows) error {
+	after := usage.Add(amount)
+
+	switch {
+	case limits.MaxSingle > 0 && amount > limits.MaxSingle:
+		return &api_error.LimitExceededError{Limit: MaxSingle, Max: limits.MaxSingle, Remaining: limits.MaxSingle}
+	case limits.HourlyCount > 0 && after.HourlyCount > limits.HourlyCount:
+		return exceeded(HourlyCount, limits.HourlyCount, usage.HourlyCount, windows.hourlyResetsAt(usage))
+	case limits.DailyTotal > 0 && after.DailyTotal > limits.DailyTotal:
+		return exceeded(DailyTotal, limits.DailyTotal, usage.DailyTotal, windows.DayEnd)
+	case limits.MonthlyTotal > 0 && after.MonthlyTotal > limits.MonthlyTotal:
+		return exceeded(MonthlyTotal, limits.MonthlyTotal, usage.MonthlyTotal, windows.MonthEnd)
+	}
+
+	return nil
+}
+
+// Remaining is what is left of every limit, nil for the limits that are off
+func (limits Limits) Remaining(usage Usage) (daily, monthly, hourly *int64) {
+	return remaining(limits.DailyTotal, usage.DailyTotal), remaining(limits.MonthlyTotal, usage.MonthlyTotal),
+		remaining(limits.HourlyCount, usage.HourlyCount)
+}
+
+func exceeded(name string, max, used int64, resetsAt time.Time) error {
+	return &api_error.LimitExceededError{Limit: name, Max: max, Used: used, Remaining: *remaining(max, used), ResetsAt: &resetsAt}
+}
+
+func remaining(max, used int64) *int64 {
+	if max <= 0 {
+		return nil
+	}
+
+	left := max - used
+	if left < 0 {
+		left = 0
+	}
+	return &left
+}
+
+type limitsKey struct {
+	tier     string
+	currency string
+}
+
+// Policy holds the limits of every tier, a nil Policy limits nothing
+type Policy struct {
+	limits map[limitsKey]Limits
+	tiers  map[string]bool
+}
+
+func NewPolicy(tiers []Limits) (*Policy, error) {
+	policy := &Policy{limits: make(map[limitsKey]Limits, len(tiers)), tiers: map[string]bool{DefaultTier: true}}
+
+	for _, limits := range tiers {
+		if limits.Tier == "" || limits.Currency == "" {
+			return nil, fmt.Errorf("transfer limits need a tier and a currency")
+		}
+
+		if limits.MaxSingle < 0 || limits.DailyTotal < 0 || limits.MonthlyTotal < 0 || limits.HourlyCount < 0 {
+			return nil, fmt.Errorf("transfer limits %s/%s: limits can't be negative", limits.Tier, limits.Currency)
+		}
+
+		key := limitsKey{limits.Tier, limits.Currency}
+		if _, ok := policy.limits[key]; ok {
+			return nil, fmt.Errorf("transfer limits %s/%s: duplicated", limits.Tier, limits.Currency)
+		}
+
+		policy.limits[key] = limits
+		policy.tiers[limits.Tier] = true
+	}
+
+	return policy, nil
+}
+
+// Lookup returns the limits of a tier in a currency, ok is false when nothing limits it
+func (policy *Policy) Lookup(tier, currency string) (limits Limits, ok bool) {
+	if policy == nil {
+		return limits, false
+	}
+
+	if tier == "" {
+		tier = DefaultTier
+	}
+
+	limits, ok = policy.limits[limitsKey{tier, currency}]
+	return limits, ok
+}
+
+// ValidTier reports whether users can be moved to tier, the default tier always exists
+func (policy *Policy) ValidTier(tier string) bool {
+	if policy == nil {
+		return tier == DefaultTier
+	}
+	return policy.tiers[tier]
+}
@@ -0,0 +1,148 @@
+package limit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/api_error"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowsAt(t *testing.T) {
+	now := time.Date(2024, 12, 31, 22, 30, 0, 0, time.FixedZone("WIB", 7*60*60))
+	windows := WindowsAt(now)
+
+	require.Equal(t, time.Date(2024, 12, 31, 14, 30, 0, 0, time.UTC), windows.HourStart)
+	require.Equal(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), windows.DayStart)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), windows.DayEnd)
+	require.Equal(t, time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), windows.MonthStart)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), windows.MonthEnd)
+}
+
+func TestCheck(t *testing.T) {
+	windows := WindowsAt(time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC))
+	limits := Limits{Tier: DefaultTier, Currency: "IDR", MaxSingle: 1000, DailyTotal: 3000, MonthlyTotal: 10000, HourlyCount: 5}
+
+	testCases := []struct {
+		name     string
+		usage    Usage
+		amount   int64
+		limit    string
+		used     int64
+		left     int64
+		resetsAt *time.Time
+	}{
+		{name: "WithinLimits", usage: Usage{DailyTotal: 1000, MonthlyTotal: 5000, HourlyCount: 2}, amount: 1000},
+		{name: "ExactlyAtLimits", usage: Usage{DailyTotal: 2000, MonthlyTotal: 9000, HourlyCount: 4}, amount: 1000},
+		{name: "MaxSingle", amount: 1001, limit: MaxSingle, left: 1000},
+		{
+			name:     "HourlyCount",
+			usage:    Usage{HourlyCount: 5, HourlyOldest: time.Date(2024, 3, 10, 11, 20, 0, 0, time.UTC)},
+			amount:   1,
+			limit:    HourlyCount,
+			used:     5,
+			resetsAt: &[]time.Time{time.Date(2024, 3, 10, 12, 20, 0, 0, time.UTC)}[0],
+		},
+		{
+			// the transfers of the hour are not recorded yet, like the earlier items of a batch
+			name:     "HourlyCountPending",
+			usage:    Usage{HourlyCount: 5},
+			amount:   1,
+			limit:    HourlyCount,
+			used:     5,
+			resetsAt: &[]time.Time{time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC)}[0],
+		},
+		{
+			name:     "DailyTotal",
+			usage:    Usage{DailyTotal: 2500, MonthlyTotal: 2500, HourlyCount: 1},
+			amount:   600,
+			limit:    DailyTotal,
+			used:     2500,
+			left:     500,
+			resetsAt: &windows.DayEnd,
+		},
+		{
+			name:     "MonthlyTotal",
+			usage:    Usage{MonthlyTotal: 9900},
+			amount:   200,
+			limit:    MonthlyTotal,
+			used:     9900,
+			left:     100,
+			resetsAt: &windows.MonthEnd,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := limits.Check(tc.usage, tc.amount, windows)
+			if tc.limit == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, api_error.ErrTransferLimitExceeded)
+
+			var exceeded *api_error.LimitExceededError
+			require.True(t, errors.As(err, &exceeded))
+			require.Equal(t, tc.limit, exceeded.Limit)
+			require.Equal(t, tc.used, exceeded.Used)
+			require.Equal(t, tc.left, exceeded.Remaining)
+			require.Equal(t, tc.resetsAt, exceeded.ResetsAt)
+		})
+	}
+}
+
+func TestCheckUnlimited(t *testing.T) {
+	limits := Limits{Tier: "premium", Currency: "IDR"}
+	require.NoError(t, limits.Check(Usage{DailyTotal: 1 << 40, HourlyCount: 1000}, 1<<40, WindowsAt(time.Now())))
+
+	daily, monthly, hourly := limits.Remaining(Usage{DailyTotal: 10})
+	require.Nil(t, daily)
+	require.Nil(t, monthly)
+	require.Nil(t, hourly)
+}
+
+func TestPolicy(t *testing.T) {
+	policy, err := NewPolicy([]Limits{
+		{Tier: DefaultTier, Currency: "IDR", DailyTotal: 1000},
+		{Tier: "premium", Currency: "IDR", DailyTotal: 5000},
+	})
+	require.NoError(t, err)
+
+	limits, ok := policy.Lookup("", "IDR")
+	require.True(t, ok)
+	require.Equal(t, int64(1000), limits.DailyTotal)
+
+	limits, ok = policy.Lookup("premium", "IDR")
+	require.True(t, ok)
+	require.Equal(t, int64(5000), limits.DailyTotal)
+
+	_, ok = policy.Lookup("premium", "USD")
+	require.False(t, ok)
+
+	require.True(t, policy.ValidTier(DefaultTier))
+	require.True(t, policy.ValidTier("premium"))
+	require.False(t, policy.ValidTier("gold"))
+
+	var none *Policy
+	_, ok = none.Lookup(DefaultTier, "IDR")
+	require.False(t, ok)
+	require.True(t, none.ValidTier(DefaultTier))
+}
+
+func TestNewPolicyInvalid(t *testing.T) {
+	testCases := map[string][]Limits{
+		"NoTier":     {{Currency: "IDR"}},
+		"NoCurrency": {{Tier: DefaultTier}},
+		"Negative":   {{Tier: DefaultTier, Currency: "IDR", HourlyCount: -1}},
+		"Duplicated": {{Tier: DefaultTier, Currency: "IDR"}, {Tier: DefaultTier, Currency: "IDR"}},
+	}
+
+	for name, tiers := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewPolicy(tiers)
+			require.Error(t, err)
+		})
+	}
+}
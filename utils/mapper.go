@@ -1,8 +1,14 @@
 package utils
 
 import (
+	"time"
+
 	"github.com/dhiemaz/bank-api/entities"
 	"github.com/dhiemaz/bank-api/infrastructure/db/sqlc"
+	"github.com/dhiemaz/bank-api/utils/currency"
+	"github.com/dhiemaz/bank-api/utils/fee"
+	"github.com/dhiemaz/bank-api/utils/limit"
+	"github.com/google/uuid"
 )
 
 func MapUserToResponse(user *db.User) entities.UserResponse {
@@ -12,20 +18,67 @@ func MapUserToResponse(user *db.User) entities.UserResponse {
 		Email:             user.Email,
 		CreatedAt:         user.CreatedAt,
 		PasswordChangedAt: user.PasswordChangedAt,
+		Role:              user.Role,
+		Tier:              user.Tier,
 	}
 }
 
 func MapAccountToResponse(account *db.Account) entities.AccountResponse {
 	return entities.AccountResponse{
-		ID:        account.ID,
-		Balance:   account.Balance,
-		Currency:  account.Currency,
-		CreatedAt: account.CreatedAt,
+		ID:             account.ID,
+		Balance:        account.Balance,
+		BalanceDecimal: currency.FormatAmount(account.Balance, account.Currency),
+
+		AvailableBalance:        account.AvailableBalance(),
+		AvailableBalanceDecimal: currency.FormatAmount(account.AvailableBalance(), account.Currency),
+
+		Currency:     account.Currency,
+		Product:      account.Product,
+		Status:       account.Status,
+		StatusReason: account.StatusReason,
+		CreatedAt:    account.CreatedAt,
+	}
+}
+
+func MapCloseAccountToResponse(result *db.ChangeAccountStatusTxResult) entities.CloseAccountResponse {
+	response := entities.CloseAccountResponse{Account: MapAccountToResponse(&result.Account)}
+	if result.Sweep != nil {
+		sweep := FromTransferTxToTransferResponse(result.Sweep)
+		response.Sweep = &sweep
+	}
+
+	return response
+}
+
+func MapAccountStatusChangeToResponse(change db.AccountStatusChange) entities.AccountStatusChangeResponse {
+	response := entities.AccountStatusChangeResponse{
+		ID:         change.ID,
+		FromStatus: change.FromStatus,
+		ToStatus:   change.ToStatus,
+		Reason:     change.Reason,
+		ChangedBy:  change.ChangedBy,
+		CreatedAt:  change.CreatedAt,
+	}
+
+	if change.SweepTransferID.Valid {
+		transferID := change.SweepTransferID.Int64
+		response.SweepTransferID = &transferID
+	}
+
+	return response
+}
+
+func MapAccountToAdminResponse(account *db.Account) entities.AdminAccountResponse {
+	return entities.AdminAccountResponse{
+		AccountResponse: MapAccountToResponse(account),
+		Owner:           account.Owner,
+		OverdraftLimit:  account.OverdraftLimit,
+		StatusChangedAt: account.StatusChangedAt,
 	}
 }
 
 func MapTransferToResponse(transfer db.Transfer) *entities.TransferResponse {
-	return &entities.TransferResponse{
+	response := &entities.TransferResponse{
 		ID: transfer.ID,
 		// FromAccount: transfer.FromAccountID,
 		ToAccountID: transfer.ToAccountID,
@@ -33,14 +86,313 @@ func MapTransferToResponse(transfer db.Transfer) *entities.TransferResponse {
 		Amount:    transfer.Amount,
 		CreatedAt: transfer.CreatedAt,
 	}
+
+	mapTransferConversion(transfer, response)
+	return response
 }
 
 func FromTransferTxToTransferResponse(result *db.TransferTxResult) entities.TransferResponse {
-	return entities.TransferResponse{
+	response := entities.TransferResponse{
 		ID:          result.Transfer.ID,
 		FromAccount: result.FromAccount,
 		ToAccountID: result.ToAccount.ID,
 		FromEntry:   result.FromEntry,
 		Amount:      result.Transfer.Amount,
 	}
+
+	mapTransferConversion(result.Transfer, &response)
+	response.AmountDecimal = currency.FormatAmount(response.Amount, result.FromAccount.Currency)
+	if response.Fee > 0 {
+		response.FeeDecimal = currency.FormatAmount(response.Fee, result.FromAccount.Currency)
+		response.FeeBreakdown = result.Fee
+	}
+	if response.ToAmount != nil {
+		response.ToAmountDecimal = currency.FormatAmount(*response.ToAmount, result.ToAccount.Currency)
+	}
+
+	return response
+}
+
+// mapTransferConversion maps the parts of a transfer that depend on how it was executed, the conversion of transfers
+// between currencies and the fee
+func mapTransferConversion(transfer db.Transfer, response *entities.TransferResponse) {
+	if transfer.ToAmount.Valid {
+		toAmount := transfer.ToAmount.Int64
+		response.ToAmount = &toAmount
+	}
+
+	response.ExchangeRate = transfer.ExchangeRate.String
+	response.Fee = transfer.Fee
+}
+
+func FromReverseTransferTxToResponse(result *db.ReverseTransferTxResult) entities.TransferReversalResponse {
+	return entities.TransferReversalResponse{
+		ID:                 result.Reversal.ID,
+		TransferID:         result.Reversal.TransferID,
+		ReversalTransferID: result.Reversal.ReversalTransferID,
+		Amount:             result.Reversal.Amount,
+		RemainingAmount:    result.RemainingAmount,
+		Reason:             result.Reversal.Reason,
+		Transfer:           FromTransferTxToTransferResponse(&result.TransferTxResult),
+		CreatedAt:          result.Reversal.CreatedAt,
+	}
+}
+
+func MapScheduledTransferToResponse(schedule *db.ScheduledTransfer) entities.ScheduledTransferResponse {
+	response := entities.ScheduledTransferResponse{
+		ID:                  schedule.ID,
+		FromAccountID:       schedule.FromAccountID,
+		ToAccountID:         schedule.ToAccountID,
+		Amount:              schedule.Amount,
+		Frequency:           schedule.Frequency,
+		StartAt:             schedule.StartAt,
+		NextRunAt:           schedule.NextRunAt,
+		Occurrences:         schedule.Occurrences,
+		Status:              schedule.Status,
+		OnInsufficientFunds: schedule.OnInsufficientFunds,
+		MaxRetries:          schedule.MaxRetries,
+		RetryCount:          schedule.RetryCount,
+		CreatedAt:           schedule.CreatedAt,
+	}
+
+	if schedule.EndAt.Valid {
+		response.EndAt = &schedule.EndAt.Time
+	}
+
+	if schedule.LastRunAt.Valid {
+		response.LastRunAt = &schedule.LastRunAt.Time
+	}
+
+	return response
+}
+
+func MapScheduledTransferRunToResponse(run db.ScheduledTransferRun) entities.ScheduledTransferRunResponse {
+	response := entities.ScheduledTransferRunResponse{
+		ID:         run.ID,
+		Occurrence: run.Occurrence,
+		Attempt:    run.Attempt,
+		Status:     run.Status,
+		Error:      run.Error.String,
+		CreatedAt:  run.CreatedAt,
+	}
+
+	if run.TransferID.Valid {
+		response.TransferID = &run.TransferID.Int64
+	}
+
+	return response
+}
+
+func MapFxRateToResponse(rate db.FxRate) entities.FxRateResponse {
+	return entities.FxRateResponse{
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		Source:        rate.Source,
+		CreatedAt:     rate.CreatedAt,
+	}
+}
+
+func MapFxQuoteToResponse(quote *db.FxQuote, amount, toAmount int64) entities.FxQuoteResponse {
+	response := entities.FxQuoteResponse{
+		ID:           quote.ID,
+		FromCurrency: quote.FromCurrency,
+		ToCurrency:   quote.ToCurrency,
+		Rate:         quote.Rate,
+		Amount:       amount,
+		ToAmount:     toAmount,
+		ExpiresAt:    quote.ExpiresAt,
+	}
+
+	if amount > 0 {
+		response.AmountDecimal = currency.FormatAmount(amount, quote.FromCurrency)
+		response.ToAmountDecimal = currency.FormatAmount(toAmount, quote.ToCurrency)
+	}
+
+	return response
+}
+
+func MapAccountEventToResponse(event db.ListAccountEventsRow, code string) entities.AccountEventResponse {
+	response := entities.AccountEventResponse{
+		ID:             event.ID,
+		AccountID:      event.AccountID,
+		Amount:         event.Amount,
+		AmountDecimal:  currency.FormatAmount(event.Amount, code),
+		Balance:        event.Balance,
+		BalanceDecimal: currency.FormatAmount(event.Balance, code),
+		CreatedAt:      event.CreatedAt,
+	}
+
+	if event.TransferID.Valid {
+		transferID := event.TransferID.Int64
+		response.TransferID = &transferID
+	}
+
+	return response
+}
+
+func MapStatementToResponse(result *db.StatementTxResult, from, to time.Time, nextCursor string) entities.StatementResponse {
+	code := result.Account.Currency
+	response := entities.StatementResponse{
+		AccountID:             result.Account.ID,
+		Currency:              code,
+		From:                  from,
+		To:                    to,
+		OpeningBalance:        result.OpeningBalance,
+		OpeningBalanceDecimal: currency.FormatAmount(result.OpeningBalance, code),
+		ClosingBalance:        result.ClosingBalance,
+		ClosingBalanceDecimal: currency.FormatAmount(result.ClosingBalance, code),
+		Entries:               make([]entities.StatementEntryResponse, 0, len(result.Entries)),
+		NextCursor:            nextCursor,
+	}
+
+	for _, entry := range result.Entries {
+		entryResponse := entities.StatementEntryResponse{
+			ID:             entry.ID,
+			Amount:         entry.Amount,
+			AmountDecimal:  currency.FormatAmount(entry.Amount, code),
+			Balance:        entry.Balance,
+			BalanceDecimal: currency.FormatAmount(entry.Balance, code),
+			CreatedAt:      entry.CreatedAt,
+		}
+
+		if entry.TransferID.Valid {
+			transferID := entry.TransferID.Int64
+			entryResponse.TransferID = &transferID
+		}
+
+		if entry.CounterpartyAccountID.Valid {
+			entryResponse.Counterparty = &entities.CounterpartyResponse{
+				AccountID: entry.CounterpartyAccountID.Int64,
+				Owner:     entry.CounterpartyOwner.String,
+			}
+		}
+
+		response.Entries = append(response.Entries, entryResponse)
+	}
+
+	return response
+}
+
+func MapSessionToResponse(session db.Session, currentSessionID uuid.UUID) entities.SessionResponse {
+	return entities.SessionResponse{
+		ID:        session.ID,
+		UserAgent: session.UserAgent,
+		ClientIP:  session.ClientIp,
+		CreatedAt: session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+		Current:   session.ID == currentSessionID,
+	}
+}
+
+func MapAdminActionToResponse(action db.AdminAction) entities.AdminActionResponse {
+	return entities.AdminActionResponse{
+		ID:        action.ID,
+		Actor:     action.Actor,
+		Role:      action.Role,
+		Action:    action.Action,
+		Target:    action.Target,
+		Details:   action.Details,
+		CreatedAt: action.CreatedAt,
+	}
+}
+
+func MapHoldToResponse(hold db.Hold) entities.HoldResponse {
+	response := entities.HoldResponse{
+		ID:             hold.ID,
+		FromAccountID:  hold.AccountID,
+		ToAccountID:    hold.ToAccountID,
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Status:         hold.Status,
+		Description:    hold.Description,
+		ExpiresAt:      hold.ExpiresAt,
+		CreatedAt:      hold.CreatedAt,
+		UpdatedAt:      hold.UpdatedAt,
+	}
+
+	if hold.TransferID.Valid {
+		transferID := hold.TransferID.Int64
+		response.TransferID = &transferID
+	}
+
+	return response
+}
+
+func MapCaptureHoldToResponse(result *db.CaptureHoldTxResult) entities.CaptureHoldResponse {
+	return entities.CaptureHoldResponse{
+		Hold:     MapHoldToResponse(result.Hold),
+		Transfer: FromTransferTxToTransferResponse(&result.TransferTxResult),
+	}
+}
+
+// MapTransferBatchToResponse maps a batch without its items, as listed
+func MapTransferBatchToResponse(batch db.TransferBatch) entities.TransferBatchResponse {
+	return entities.TransferBatchResponse{
+		ID:             batch.ID,
+		FromAccountID:  batch.FromAccountID,
+		Mode:           batch.Mode,
+		Status:         batch.Status,
+		TotalItems:     batch.TotalItems,
+		SucceededItems: batch.SucceededItems,
+		FailedItems:    batch.FailedItems,
+		CreatedAt:      batch.CreatedAt,
+		UpdatedAt:      batch.UpdatedAt,
+	}
+}
+
+func MapTransferBatchTxToResponse(result *db.TransferBatchTxResult) entities.TransferBatchResponse {
+	response := MapTransferBatchToResponse(result.Batch)
+	response.Items = make([]entities.TransferBatchItemResponse, 0, len(result.Items))
+
+	for _, item := range result.Items {
+		itemResponse := entities.TransferBatchItemResponse{
+			Line:        item.Line,
+			ToAccountID: item.ToAccountID,
+			Amount:      item.Amount,
+			Status:      item.Status,
+			Error:       item.Error,
+		}
+
+		if item.TransferID.Valid {
+			transferID := item.TransferID.Int64
+			itemResponse.TransferID = &transferID
+		}
+
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response
+}
+
+// MapFeePreviewToResponse maps the fee of a transfer of amount, the total is what the source account is debited
+func MapFeePreviewToResponse(amount int64, breakdown fee.Breakdown) entities.FeePreviewResponse {
+	total := amount + breakdown.Amount
+	return entities.FeePreviewResponse{
+		Currency:     breakdown.Currency,
+		Amount:       amount,
+		Fee:          breakdown.Amount,
+		FeeDecimal:   currency.FormatAmount(breakdown.Amount, breakdown.Currency),
+		Total:        total,
+		TotalDecimal: currency.FormatAmount(total, breakdown.Currency),
+		FeeBreakdown: breakdown,
+	}
+}
+
+// MapAccountLimitsToResponse maps the transfer limits of an account, limited is false when its currency isn't limited
+func MapAccountLimitsToResponse(account db.Account, limits limit.Limits, limited bool, usage limit.Usage, windows limit.Windows) entities.AccountLimitsResponse {
+	response := entities.AccountLimitsResponse{
+		AccountID:       account.ID,
+		Currency:        account.Currency,
+		Used:            usage,
+		DailyResetsAt:   windows.DayEnd,
+		MonthlyResetsAt: windows.MonthEnd,
+	}
+
+	if limited {
+		response.Limits = &limits
+		response.Remaining.DailyTotal, response.Remaining.MonthlyTotal, response.Remaining.HourlyCount = limits.Remaining(usage)
+	}
+
+	return response
 }
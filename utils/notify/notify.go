@@ -0,0 +1,138 @@
+// Package notify wakes the watchers of an account when entries are posted to it. Notifications carry no data, a
+// woken watcher reads what changed from the database, so a lost notification only delays an event until the next one.
+package notify
+
+import (
+	"context"
+	"github.com/dhiemaz/bank-api/infrastructure/logger"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres channel the entries_notify trigger notifies with the account id of every entry
+const Channel = "account_entries"
+
+// Bus fans the notifications of accounts out to their subscribers, the zero value isn't usable, use NewBus
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan struct{}]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[int64]map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel receiving a value after accountID is published, wakeups published while the previous
+// one wasn't received yet are merged into it. cancel unsubscribes and has to be called once the channel is done with.
+func (bus *Bus) Subscribe(accountID int64) (wakeups <-chan struct{}, cancel func()) {
+	ch := make(chan struct{}, 1)
+
+	bus.mu.Lock()
+	if bus.subscribers[accountID] == nil {
+		bus.subscribers[accountID] = make(map[chan struct{}]struct{})
+	}
+	bus.subscribers[accountID][ch] = struct{}{}
+	bus.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			bus.mu.Lock()
+			defer bus.mu.Unlock()
+
+			delete(bus.subscribers[accountID], ch)
+			if len(bus.subscribers[accountID]) == 0 {
+				delete(bus.subscribers, accountID)
+			}
+		})
+	}
+}
+
+// Publish wakes the subscribers of accountID, it never blocks
+func (bus *Bus) Publish(accountID int64) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for ch := range bus.subscribers[accountID] {
+		wake(ch)
+	}
+}
+
+// Broadcast wakes every subscriber, used when notifications may have been lost
+func (bus *Bus) Broadcast() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for _, subscribers := range bus.subscribers {
+		for ch := range subscribers {
+			wake(ch)
+		}
+	}
+}
+
+func wake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Listen publishes the notifications of Channel on bus until ctx is done. The connection to url is re-established
+// when it's lost, every subscriber is woken once it is as the notifications sent meanwhile are gone.
+func Listen(ctx context.Context, url string, bus *Bus, onError func(error)) error {
+	listener := pq.NewListener(url, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+		if event == pq.ListenerEventReconnected {
+			bus.Broadcast()
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case notification := <-listener.Notify:
+			// nil after a reconnect, handled by the event callback
+			if notification == nil {
+				continue
+			}
+
+			accountID, err := strconv.ParseInt(notification.Extra, 10, 64)
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			bus.Publish(accountID)
+		case <-time.After(90 * time.Second):
+			// a quiet connection is checked so that a dead one is noticed and re-established
+			go listener.Ping()
+		}
+	}
+}
+
+// Start returns a bus publishing the notifications of the database at url for as long as the process runs
+func Start(url string) *Bus {
+	bus := NewBus()
+	go func() {
+		err := Listen(context.Background(), url, bus, func(err error) {
+			logger.WithFields(logger.Fields{"component": "notify", "action": "listen entries"}).
+				Errorf("entry notifications interrupted, error : %v", err)
+		})
+		logger.WithFields(logger.Fields{"component": "notify", "action": "listen entries"}).
+			Errorf("stopped listening to entry notifications, error : %v", err)
+	}()
+
+	return bus
+}
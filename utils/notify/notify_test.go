@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusPublish(t *testing.T) {
+	bus := NewBus()
+	first, cancelFirst := bus.Subscribe(1)
+	defer cancelFirst()
+	second, cancelSecond := bus.Subscribe(1)
+	defer cancelSecond()
+	other, cancelOther := bus.Subscribe(2)
+	defer cancelOther()
+
+	// wakeups not received yet are merged
+	bus.Publish(1)
+	bus.Publish(1)
+
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+	require.Len(t, other, 0)
+}
+
+func TestBusBroadcast(t *testing.T) {
+	bus := NewBus()
+	first, cancelFirst := bus.Subscribe(1)
+	defer cancelFirst()
+	second, cancelSecond := bus.Subscribe(2)
+	defer cancelSecond()
+
+	bus.Broadcast()
+
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+}
+
+func TestBusCancel(t *testing.T) {
+	bus := NewBus()
+	wakeups, cancel := bus.Subscribe(1)
+	cancel()
+	cancel()
+
+	bus.Publish(1)
+
+	require.Len(t, wakeups, 0)
+	require.Empty(t, bus.subscribers)
+}
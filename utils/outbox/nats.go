@@ -0,0 +1,350 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNATSPort    = "4222"
+	defaultNATSTimeout = 5 * time.Second
+)
+
+// NATSSink publishes every event to the subject <prefix>.<event type> of a NATS server, with its id in the
+// Nats-Msg-Id header so that the JetStream stream on the subjects drops the events published again. It speaks the
+// plain text client protocol, an event is published once the stream acknowledged it has stored the event. A stream
+// must capture the subjects, publishing to a subject no stream captures fails. TLS isn't supported.
+type NATSSink struct {
+	url     *url.URL
+	prefix  string
+	timeout time.Duration
+
+	conn   net.Conn
+	reader *bufio.Reader
+	// inbox is the subject prefix the stream acknowledgements of the connection are received on
+	inbox    string
+	requests uint64
+}
+
+// natsPubAck is the answer of a JetStream stream to a publish
+type natsPubAck struct {
+	Stream    string `json:"stream"`
+	Seq       uint64 `json:"seq"`
+	Duplicate bool   `json:"duplicate"`
+	Error     *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+type natsInfo struct {
+	Headers     bool `json:"headers"`
+	TLSRequired bool `json:"tls_required"`
+}
+
+type natsConnect struct {
+	Verbose      bool   `json:"verbose"`
+	Pedantic     bool   `json:"pedantic"`
+	Headers      bool   `json:"headers"`
+	NoResponders bool   `json:"no_responders"`
+	Name         string `json:"name"`
+	Lang         string `json:"lang"`
+	Version      string `json:"version"`
+	User         string `json:"user,omitempty"`
+	Pass         string `json:"pass,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+}
+
+// NewNATSSink connects to the server at rawURL, nats://[user:pass@|token@]host[:port]. timeout bounds the
+// connection and every publish, 5s when zero.
+func NewNATSSink(rawURL, subjectPrefix string, timeout time.Duration) (*NATSSink, error) {
+	if timeout <= 0 {
+		timeout = defaultNATSTimeout
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nats url: %w", err)
+	}
+
+	if u.Scheme != "nats" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid nats url %q, expected nats://host:port", rawURL)
+	}
+
+	sink := &NATSSink{url: u, prefix: strings.TrimSuffix(subjectPrefix, "."), timeout: timeout}
+	if err = sink.connect(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+// Subject is the subject event is published to
+func (sink *NATSSink) Subject(event Event) string {
+	if sink.prefix == "" {
+		return event.Type
+	}
+	return sink.prefix + "." + event.Type
+}
+
+// Publish publishes event and waits for the JetStream stream to acknowledge it, an event the stream already
+// stored is acknowledged as a duplicate. A broken connection is re-established on the next publish.
+func (sink *NATSSink) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	subject := sink.Subject(event)
+	header, data, err := sink.request(ctx, subject, fmt.Sprintf("NATS/1.0\r\nNats-Msg-Id: %d\r\n\r\n", event.ID), payload)
+	if err != nil {
+		return err
+	}
+
+	// a status without an ack, 503 when no stream captures the subject
+	if status := natsStatus(header); status != "" {
+		if strings.HasPrefix(status, "503") {
+			return fmt.Errorf("nats: no jetstream stream captures subject %s", subject)
+		}
+		return fmt.Errorf("nats: publish to %s answered with status %s", subject, status)
+	}
+
+	var ack natsPubAck
+	if err = json.Unmarshal(data, &ack); err != nil {
+		return fmt.Errorf("invalid jetstream ack: %w", err)
+	}
+
+	if ack.Error != nil {
+		return fmt.Errorf("nats jetstream: %s (%d)", ack.Error.Description, ack.Error.Code)
+	}
+
+	if ack.Stream == "" {
+		return fmt.Errorf("invalid jetstream ack %q", data)
+	}
+	return nil
+}
+
+// request publishes payload to subject with a reply subject of the inbox and returns the header and the payload of
+// the reply, header is sent when it isn't empty
+func (sink *NATSSink) request(ctx context.Context, subject, header string, payload []byte) (string, []byte, error) {
+	if sink.conn == nil {
+		if err := sink.connect(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	sink.setDeadline(ctx)
+
+	sink.requests++
+	reply := sink.inbox + "." + strconv.FormatUint(sink.requests, 10)
+
+	var message string
+	if header != "" {
+		message = fmt.Sprintf("HPUB %s %s %d %d\r\n%s%s\r\n", subject, reply, len(header), len(header)+len(payload), header, payload)
+	} else {
+		message = fmt.Sprintf("PUB %s %s %d\r\n%s\r\n", subject, reply, len(payload), payload)
+	}
+
+	if _, err := sink.conn.Write([]byte(message)); err != nil {
+		sink.Close()
+		return "", nil, err
+	}
+
+	replyHeader, data, err := sink.waitReply(reply)
+	if err != nil {
+		sink.Close()
+		return "", nil, err
+	}
+	return replyHeader, data, nil
+}
+
+func (sink *NATSSink) Close() error {
+	if sink.conn == nil {
+		return nil
+	}
+
+	err := sink.conn.Close()
+	sink.conn, sink.reader = nil, nil
+	return err
+}
+
+func (sink *NATSSink) connect() error {
+	host := sink.url.Host
+	if sink.url.Port() == "" {
+		host = net.JoinHostPort(sink.url.Hostname(), defaultNATSPort)
+	}
+
+	conn, err := net.DialTimeout("tcp", host, sink.timeout)
+	if err != nil {
+		return err
+	}
+
+	sink.conn, sink.reader = conn, bufio.NewReader(conn)
+	sink.setDeadline(context.Background())
+
+	if err = sink.handshake(); err != nil {
+		sink.Close()
+		return err
+	}
+	return nil
+}
+
+func (sink *NATSSink) handshake() error {
+	line, err := sink.readLine()
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected nats greeting %q", line)
+	}
+
+	var info natsInfo
+	if err = json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return fmt.Errorf("invalid nats info: %w", err)
+	}
+
+	if info.TLSRequired {
+		return errors.New("the nats server requires tls, which isn't supported")
+	}
+
+	// the event id is deduplicated from the Nats-Msg-Id header
+	if !info.Headers {
+		return errors.New("the nats server doesn't support headers, jetstream needs nats-server 2.2 or later")
+	}
+
+	inbox := make([]byte, 11)
+	if _, err = rand.Read(inbox); err != nil {
+		return err
+	}
+	sink.inbox, sink.requests = "_INBOX."+hex.EncodeToString(inbox), 0
+
+	// with no_responders a publish no stream captures is answered with a 503 status instead of timing out
+	connect := natsConnect{Headers: true, NoResponders: true, Name: "bank-api outbox relay", Lang: "go", Version: "1.0.0"}
+	if user := sink.url.User; user != nil {
+		if pass, ok := user.Password(); ok {
+			connect.User, connect.Pass = user.Username(), pass
+		} else {
+			connect.AuthToken = user.Username()
+		}
+	}
+
+	options, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+
+	// the PONG confirms the server accepted the connection and the subscription, it answers a refused one with -ERR
+	if _, err = fmt.Fprintf(sink.conn, "CONNECT %s\r\nSUB %s.* 1\r\nPING\r\n", options, sink.inbox); err != nil {
+		return err
+	}
+	return sink.waitPong()
+}
+
+// waitPong reads the server messages up to the PONG answering our PING
+func (sink *NATSSink) waitPong() error {
+	for {
+		line, err := sink.readLine()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err = sink.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// +OK and INFO updates need no answer
+	}
+}
+
+// waitReply reads the server messages up to the message sent to reply, replies to earlier requests are skipped
+func (sink *NATSSink) waitReply(reply string) (string, []byte, error) {
+	for {
+		line, err := sink.readLine()
+		if err != nil {
+			return "", nil, err
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "PING":
+			if _, err = sink.conn.Write([]byte("PONG\r\n")); err != nil {
+				return "", nil, err
+			}
+		case fields[0] == "-ERR":
+			return "", nil, fmt.Errorf("nats: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case (fields[0] == "MSG" && len(fields) >= 4) || (fields[0] == "HMSG" && len(fields) >= 5):
+			header, data, err := sink.readMessage(fields)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if fields[1] == reply {
+				return header, data, nil
+			}
+		}
+		// +OK, PONG and INFO updates need no answer
+	}
+}
+
+// readMessage reads the header and the payload of MSG <subject> <sid> [reply-to] <size> or
+// HMSG <subject> <sid> [reply-to] <header size> <total size>
+func (sink *NATSSink) readMessage(fields []string) (string, []byte, error) {
+	total, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid nats message size: %w", err)
+	}
+
+	headerLen := 0
+	if fields[0] == "HMSG" {
+		if headerLen, err = strconv.Atoi(fields[len(fields)-2]); err != nil || headerLen > total {
+			return "", nil, fmt.Errorf("invalid nats header size %q", fields[len(fields)-2])
+		}
+	}
+
+	data := make([]byte, total+2)
+	if _, err = io.ReadFull(sink.reader, data); err != nil {
+		return "", nil, err
+	}
+	return string(data[:headerLen]), data[headerLen:total], nil
+}
+
+// natsStatus is the status of a message header, NATS/1.0 503 for example, empty when there's none
+func natsStatus(header string) string {
+	line, _, _ := strings.Cut(header, "\r\n")
+	return strings.TrimSpace(strings.TrimPrefix(line, "NATS/1.0"))
+}
+
+func (sink *NATSSink) readLine() (string, error) {
+	line, err := sink.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (sink *NATSSink) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(sink.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	sink.conn.SetDeadline(deadline)
+}
@@ -0,0 +1,304 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type natsMessage struct {
+	subject string
+	reply   string
+	header  string
+	payload string
+}
+
+// fakeNATS speaks enough of the NATS protocol for the sink and records what it's sent. It acknowledges every
+// publish like a JetStream stream capturing all subjects.
+type fakeNATS struct {
+	listener net.Listener
+	headers  bool
+	// reject answers the next publish with -ERR and closes the connection
+	reject atomic.Bool
+	// noStream answers the publishes with the 503 status of a subject no stream captures
+	noStream atomic.Bool
+	// full answers the next publish with the error of a stream that can't store it
+	full     atomic.Bool
+	connects chan string
+	messages chan natsMessage
+}
+
+func newFakeNATS(t *testing.T, headers bool) *fakeNATS {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeNATS{
+		listener: listener,
+		headers:  headers,
+		connects: make(chan string, 10),
+		messages: make(chan natsMessage, 10),
+	}
+	go server.serve()
+	return server
+}
+
+func (server *fakeNATS) url(userinfo string) string {
+	return fmt.Sprintf("nats://%s%s", userinfo, server.listener.Addr())
+}
+
+func (server *fakeNATS) serve() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		go server.handle(conn)
+	}
+}
+
+func (server *fakeNATS) handle(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\",\"headers\":%t}\r\n", server.headers)
+
+	reader := bufio.NewReader(conn)
+	sid, stored := "", map[string]bool{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "CONNECT":
+			server.connects <- strings.TrimSpace(strings.TrimPrefix(line, "CONNECT"))
+		case fields[0] == "SUB":
+			sid = fields[len(fields)-1]
+		case fields[0] == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case fields[0] == "PUB" || fields[0] == "HPUB":
+			message, err := readMessage(reader, fields)
+			if err != nil {
+				return
+			}
+
+			if server.reject.Swap(false) {
+				fmt.Fprint(conn, "-ERR 'Permissions Violation for Publish'\r\n")
+				return
+			}
+
+			switch {
+			case server.noStream.Load():
+				fmt.Fprintf(conn, "HMSG %s %s 16 16\r\nNATS/1.0 503\r\n\r\n\r\n", message.reply, sid)
+			case server.full.Swap(false):
+				ack := `{"error":{"code":503,"err_code":10077,"description":"maximum messages exceeded"}}`
+				fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", message.reply, sid, len(ack), ack)
+			default:
+				ack := fmt.Sprintf(`{"stream":"BANK","seq":%d,"duplicate":%t}`, len(stored)+1, stored[message.header])
+				stored[message.header] = true
+				server.messages <- message
+				fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", message.reply, sid, len(ack), ack)
+			}
+		}
+	}
+}
+
+// readMessage reads PUB <subject> [reply-to] <size> or HPUB <subject> [reply-to] <header size> <total size>
+func readMessage(reader *bufio.Reader, fields []string) (natsMessage, error) {
+	message := natsMessage{subject: fields[1]}
+	headerLen, sizes := 0, 1
+	if fields[0] == "HPUB" {
+		sizes = 2
+	}
+	if len(fields) > sizes+2 {
+		message.reply = fields[2]
+	}
+
+	total, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return message, err
+	}
+	if fields[0] == "HPUB" {
+		if headerLen, err = strconv.Atoi(fields[len(fields)-2]); err != nil {
+			return message, err
+		}
+	}
+
+	data := make([]byte, total+2)
+	if _, err = io.ReadFull(reader, data); err != nil {
+		return message, err
+	}
+
+	message.header, message.payload = string(data[:headerLen]), string(data[headerLen:total])
+	return message, nil
+}
+
+func receive(t *testing.T, messages chan natsMessage) natsMessage {
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(time.Second):
+		t.Fatal("no message published")
+		return natsMessage{}
+	}
+}
+
+func TestNATSSinkPublish(t *testing.T) {
+	server := newFakeNATS(t, true)
+
+	sink, err := NewNATSSink(server.url("alice:secret@"), "bank.", time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	var connect natsConnect
+	require.NoError(t, json.Unmarshal([]byte(<-server.connects), &connect))
+	require.Equal(t, "alice", connect.User)
+	require.Equal(t, "secret", connect.Pass)
+	require.True(t, connect.Headers)
+	require.True(t, connect.NoResponders)
+
+	require.NoError(t, sink.Publish(context.Background(), testEvent(1)))
+
+	message := receive(t, server.messages)
+	require.Equal(t, "bank.transfer.created", message.subject)
+	require.True(t, strings.HasPrefix(message.reply, "_INBOX."))
+	require.Equal(t, "NATS/1.0\r\nNats-Msg-Id: 1\r\n\r\n", message.header)
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(message.payload), &event))
+	require.Equal(t, testEvent(1), event)
+
+	// the stream acknowledges an event published again as a duplicate
+	require.NoError(t, sink.Publish(context.Background(), testEvent(1)))
+	require.Equal(t, message.header, receive(t, server.messages).header)
+}
+
+func TestNATSSinkWithoutHeaders(t *testing.T) {
+	server := newFakeNATS(t, false)
+
+	_, err := NewNATSSink(server.url("token@"), "", time.Second)
+	require.ErrorContains(t, err, "doesn't support headers")
+}
+
+func TestNATSSinkWithoutStream(t *testing.T) {
+	server := newFakeNATS(t, true)
+
+	sink, err := NewNATSSink(server.url("token@"), "", time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	var connect natsConnect
+	require.NoError(t, json.Unmarshal([]byte(<-server.connects), &connect))
+	require.Equal(t, "token", connect.AuthToken)
+
+	server.noStream.Store(true)
+	err = sink.Publish(context.Background(), testEvent(2))
+	require.ErrorContains(t, err, "no jetstream stream captures subject transfer.created")
+
+	server.noStream.Store(false)
+	server.full.Store(true)
+	err = sink.Publish(context.Background(), testEvent(2))
+	require.ErrorContains(t, err, "maximum messages exceeded")
+
+	// the connection is kept, the event is published once a stream stores it
+	require.NoError(t, sink.Publish(context.Background(), testEvent(2)))
+	require.Contains(t, receive(t, server.messages).payload, `"id":2`)
+	require.Len(t, server.connects, 0)
+}
+
+func TestNATSSinkReconnect(t *testing.T) {
+	server := newFakeNATS(t, true)
+
+	sink, err := NewNATSSink(server.url(""), "bank", time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	server.reject.Store(true)
+	err = sink.Publish(context.Background(), testEvent(3))
+	require.ErrorContains(t, err, "Permissions Violation")
+
+	// the event is published again over a new connection
+	require.NoError(t, sink.Publish(context.Background(), testEvent(3)))
+	require.Contains(t, receive(t, server.messages).header, "Nats-Msg-Id: 3")
+	require.Len(t, server.connects, 2)
+}
+
+func TestNATSSinkInvalidURL(t *testing.T) {
+	_, err := NewNATSSink("http://localhost:4222", "bank", time.Second)
+	require.Error(t, err)
+}
+
+// TestNATSSinkBroker publishes to the NATS server of NATS_URL, e.g. nats://localhost:4222 of docker compose, with
+// jetstream enabled
+func TestNATSSinkBroker(t *testing.T) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		t.Skip("NATS_URL isn't set")
+	}
+
+	prefix := "bank-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	// subscribed with a connection of its own before publishing
+	subscriber, err := NewNATSSink(natsURL, prefix, 5*time.Second)
+	require.NoError(t, err)
+	defer subscriber.Close()
+
+	_, err = fmt.Fprintf(subscriber.conn, "SUB %s.> 1\r\nPING\r\n", prefix)
+	require.NoError(t, err)
+	require.NoError(t, subscriber.waitPong())
+
+	sink, err := NewNATSSink(natsURL, prefix, 5*time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	// a memory stream captures the subjects of the test and is deleted afterwards
+	stream, err := json.Marshal(map[string]interface{}{"name": prefix, "subjects": []string{prefix + ".>"}, "storage": "memory"})
+	require.NoError(t, err)
+	_, reply, err := sink.request(context.Background(), "$JS.API.STREAM.CREATE."+prefix, "", stream)
+	require.NoError(t, err)
+	require.NotContains(t, string(reply), `"error"`)
+	defer sink.request(context.Background(), "$JS.API.STREAM.DELETE."+prefix, "", nil)
+
+	require.NoError(t, sink.Publish(context.Background(), testEvent(4)))
+	require.NoError(t, sink.Publish(context.Background(), testEvent(4)))
+
+	subscriber.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	for {
+		line, err := subscriber.readLine()
+		require.NoError(t, err)
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 || (fields[0] != "MSG" && fields[0] != "HMSG") {
+			continue
+		}
+		require.Equal(t, prefix+".transfer.created", fields[1])
+
+		// MSG <subject> <sid> [reply-to] <size> or HMSG <subject> <sid> [reply-to] <header size> <total size>
+		total, err := strconv.Atoi(fields[len(fields)-1])
+		require.NoError(t, err)
+		headerLen := 0
+		if fields[0] == "HMSG" {
+			headerLen, err = strconv.Atoi(fields[len(fields)-2])
+			require.NoError(t, err)
+		}
+
+		data := make([]byte, total+2)
+		_, err = io.ReadFull(subscriber.reader, data)
+		require.NoError(t, err)
+		require.Contains(t, string(data[headerLen:total]), `"id":4`)
+		return
+	}
+}
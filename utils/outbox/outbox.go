@@ -0,0 +1,45 @@
+// Package outbox holds the domain events written to the outbox table with the changes they describe and the sinks
+// the relay publishes them to. An event is published at least once, consumers deduplicate by its id.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// aggregates, the events of an aggregate are published in the order they were written
+const (
+	AggregateTransfer = "transfer"
+	AggregateAccount  = "account"
+	AggregateUser     = "user"
+)
+
+// event types
+const (
+	EventTransferCreated  = "transfer.created"
+	EventTransferReversed = "transfer.reversed"
+	EventAccountOpened    = "account.opened"
+	EventAccountFrozen    = "account.frozen"
+	EventAccountDormant   = "account.dormant"
+	EventAccountClosed    = "account.closed"
+	EventAccountActivated = "account.activated"
+	EventUserRegistered   = "user.registered"
+)
+
+// Event is an outbox row as it's published
+type Event struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Sink is where the relay publishes events to. Publish returns once the sink has taken the event durably, an event
+// that failed is published again with the events after it.
+type Sink interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// WriterSink writes every event as a line of JSON
+type WriterSink struct {
+	w io.Writer
+	// file is synced after every event, nil for writers that can't be
+	file *os.File
+}
+
+// NewWriterSink writes the events to w, e.g. os.Stdout, closing the sink doesn't close w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// NewFileSink appends the events to the file at path, it's created when missing
+func NewFileSink(path string) (*WriterSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WriterSink{w: file, file: file}, nil
+}
+
+func (sink *WriterSink) Publish(_ context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err = sink.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	if sink.file != nil {
+		return sink.file.Sync()
+	}
+	return nil
+}
+
+func (sink *WriterSink) Close() error {
+	if sink.file != nil {
+		return sink.file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(id int64) Event {
+	return Event{
+		ID:            id,
+		Type:          EventTransferCreated,
+		AggregateType: AggregateTransfer,
+		AggregateID:   "7",
+		Payload:       json.RawMessage(`{"id":7,"amount":10}`),
+		CreatedAt:     time.Date(2024, 3, 14, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	require.NoError(t, sink.Publish(context.Background(), testEvent(1)))
+	require.NoError(t, sink.Publish(context.Background(), testEvent(2)))
+	require.NoError(t, sink.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{"id":1,"type":"transfer.created","aggregate_type":"transfer","aggregate_id":"7",
+		"payload":{"id":7,"amount":10},"created_at":"2024-03-14T10:00:00Z"}`, lines[0])
+}
+
+func TestFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	for id := int64(1); id <= 2; id++ {
+		sink, err := NewFileSink(path)
+		require.NoError(t, err)
+		require.NoError(t, sink.Publish(context.Background(), testEvent(id)))
+		require.NoError(t, sink.Close())
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	require.Equal(t, int64(2), event.ID)
+}
@@ -1,9 +1,10 @@
 package utils
 
 import (
+	"database/sql"
 	"github.com/dhiemaz/bank-api/entities"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,30 +26,69 @@ func ParseURI(ctx *gin.Context, obj interface{}) error {
 	return nil
 }
 
+func ParseQuery(ctx *gin.Context, obj interface{}) error {
+	if err := ctx.ShouldBindQuery(obj); err != nil {
+		ctx.JSON(http.StatusBadRequest, entities.Err(err))
+		return err
+	}
+	return nil
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PaginationQuery is the keyset pagination of list endpoints, rows are sorted on (created_at, id) and a page
+// starts after the row encoded in the opaque cursor returned with the previous page
 type PaginationQuery struct {
-	Offset int32 `form:"offset" binding:"required"`
-	Limit  int32 `form:"limit" binding:"required"`
+	Cursor string `form:"cursor"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+
+	AfterCreatedAt time.Time `form:"-"`
+	AfterID        int64     `form:"-"`
 }
 
+// ParsePagination reads the cursor and limit query parameters, the limit defaults to DefaultPageSize
 func ParsePagination(ctx *gin.Context) (*PaginationQuery, error) {
 	pgQuery := &PaginationQuery{}
-
-	offset := ctx.DefaultQuery("offset", "1")
-	limit := ctx.DefaultQuery("limit", "1")
-
-	limit32, err := strconv.Atoi(limit)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, entities.Err(err))
+	if err := ParseQuery(ctx, pgQuery); err != nil {
 		return nil, err
 	}
-	offset32, err := strconv.Atoi(offset)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, entities.Err(err))
-		return nil, err
+
+	if pgQuery.Limit == 0 {
+		pgQuery.Limit = DefaultPageSize
 	}
 
-	pgQuery.Limit = int32(limit32)
-	pgQuery.Offset = int32(offset32)
+	if pgQuery.Cursor != "" {
+		var err error
+		pgQuery.AfterCreatedAt, pgQuery.AfterID, err = DecodeCursor(pgQuery.Cursor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, entities.Err(err))
+			return nil, err
+		}
+	}
 
 	return pgQuery, nil
 }
+
+// HasCursor reports whether the page continues a previous one
+func (p *PaginationQuery) HasCursor() bool {
+	return p.AfterID > 0
+}
+
+// AfterParams returns the cursor as the nullable arguments of the list queries
+func (p *PaginationQuery) AfterParams() (sql.NullTime, sql.NullInt64) {
+	return sql.NullTime{Time: p.AfterCreatedAt, Valid: p.HasCursor()}, sql.NullInt64{Int64: p.AfterID, Valid: p.HasCursor()}
+}
+
+// FetchSize is the number of rows to query, one more than the page so that a next page can be detected
+func (p *PaginationQuery) FetchSize() int32 {
+	return p.Limit + 1
+}
+
+// HasNextPage reports whether more rows than the page were fetched, the caller trims them to p.Limit and encodes
+// the last row of the page as the next cursor
+func (p *PaginationQuery) HasNextPage(fetched int) bool {
+	return fetched > int(p.Limit)
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueryContext(query string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	return ctx, recorder
+}
+
+func TestParsePagination(t *testing.T) {
+	ctx, _ := newQueryContext("")
+	pgQuery, err := ParsePagination(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(DefaultPageSize), pgQuery.Limit)
+	require.False(t, pgQuery.HasCursor())
+
+	createdAt, afterID := pgQuery.AfterParams()
+	require.False(t, createdAt.Valid)
+	require.False(t, afterID.Valid)
+
+	cursorAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	ctx, _ = newQueryContext("limit=5&cursor=" + EncodeCursor(cursorAt, 9))
+	pgQuery, err = ParsePagination(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(5), pgQuery.Limit)
+	require.Equal(t, int32(6), pgQuery.FetchSize())
+	require.True(t, pgQuery.HasCursor())
+	require.False(t, pgQuery.HasNextPage(5))
+	require.True(t, pgQuery.HasNextPage(6))
+
+	createdAt, afterID = pgQuery.AfterParams()
+	require.True(t, createdAt.Time.Equal(cursorAt))
+	require.Equal(t, int64(9), afterID.Int64)
+
+	for _, query := range []string{"limit=101", "limit=abc", "cursor=abc"} {
+		ctx, recorder := newQueryContext(query)
+		_, err = ParsePagination(ctx)
+		require.Error(t, err, query)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, query)
+	}
+}
@@ -3,12 +3,13 @@ package utils
 import (
 	"testing"
 
+	"github.com/dhiemaz/bank-api/utils/random"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
 func TestGeneratePassword(t *testing.T) {
-	password := RandomString(10)
+	password := random.String(10)
 
 	hashedPassword, err := GenerateHashPassword(password)
 	require.NoError(t, err)
@@ -19,7 +20,7 @@ func TestGeneratePassword(t *testing.T) {
 	err = CheckHashedPassword(hashedPassword, password)
 	require.NoError(t, err)
 
-	password = RandomString(32)
+	password = random.String(32)
 	err = CheckHashedPassword(hashedPassword, password)
 	require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
 }
@@ -0,0 +1,55 @@
+// Package random generates the random data of the tests. It doesn't import the rest of the module, so the tests
+// of any package can use it without an import cycle.
+package random
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/currency"
+)
+
+const alphabet = "abcdefghijklmnopqrstuvwxyz"
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+// Integer creates a random integer from min to max
+func Integer(min, max int64) int64 {
+	return min + rand.Int63n(max-min+1)
+}
+
+func String(n int) string {
+	var sb strings.Builder
+	k := len(alphabet)
+
+	for i := 0; i < n; i++ {
+		sb.WriteByte(alphabet[rand.Intn(k)])
+	}
+
+	return sb.String()
+}
+
+func Owner() string {
+	return String(7)
+}
+
+func Username() string {
+	return String(10)
+}
+
+func Money() int64 {
+	return Integer(0, 1000)
+}
+
+func Currency() string {
+	codes := []string{currency.IDR, currency.USD}
+	return codes[rand.Intn(len(codes))]
+}
+
+func Email() string {
+	return fmt.Sprintf("%s@email.com", String(7))
+}
@@ -0,0 +1,72 @@
+// Package rbac holds the roles of users and the permissions every role is granted
+package rbac
+
+// Role of a user, stored in users.role and carried in access tokens
+type Role string
+
+const (
+	// RoleCustomer can only reach its own resources, it is granted no permission
+	RoleCustomer Role = "customer"
+	// RoleSupport looks up customers and their money for operations staff
+	RoleSupport Role = "support"
+	// RoleAdmin is granted every permission
+	RoleAdmin Role = "admin"
+	// RoleAuditor has read only access, including the log of admin actions
+	RoleAuditor Role = "auditor"
+)
+
+// Permission guards a back-office route
+type Permission string
+
+const (
+	PermissionUsersRead      Permission = "users:read"
+	PermissionUsersManage    Permission = "users:manage"
+	PermissionAccountsRead   Permission = "accounts:read"
+	PermissionAccountsFreeze Permission = "accounts:freeze"
+	PermissionTransfersRead  Permission = "transfers:read"
+	PermissionAuditRead      Permission = "audit:read"
+)
+
+var grants = map[Role][]Permission{
+	RoleCustomer: {},
+	RoleSupport: {
+		PermissionUsersRead,
+		PermissionAccountsRead,
+		PermissionTransfersRead,
+	},
+	RoleAdmin: {
+		PermissionUsersRead,
+		PermissionUsersManage,
+		PermissionAccountsRead,
+		PermissionAccountsFreeze,
+		PermissionTransfersRead,
+		PermissionAuditRead,
+	},
+	RoleAuditor: {
+		PermissionUsersRead,
+		PermissionAccountsRead,
+		PermissionTransfersRead,
+		PermissionAuditRead,
+	},
+}
+
+// Roles returns every role
+func Roles() []Role {
+	return []Role{RoleCustomer, RoleSupport, RoleAdmin, RoleAuditor}
+}
+
+// Valid reports whether the role exists
+func (role Role) Valid() bool {
+	_, ok := grants[role]
+	return ok
+}
+
+// Can reports whether the role is granted permission, unknown roles are granted nothing
+func (role Role) Can(permission Permission) bool {
+	for _, granted := range grants[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleCan(t *testing.T) {
+	testCases := []struct {
+		role       Role
+		permission Permission
+		can        bool
+	}{
+		{RoleCustomer, PermissionUsersRead, false},
+		{RoleSupport, PermissionAccountsRead, true},
+		{RoleSupport, PermissionAccountsFreeze, false},
+		{RoleSupport, PermissionAuditRead, false},
+		{RoleAuditor, PermissionTransfersRead, true},
+		{RoleAuditor, PermissionAuditRead, true},
+		{RoleAuditor, PermissionAccountsFreeze, false},
+		{RoleAdmin, PermissionAccountsFreeze, true},
+		{RoleAdmin, PermissionUsersManage, true},
+		{Role(""), PermissionUsersRead, false},
+		{Role("root"), PermissionUsersRead, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.role)+"/"+string(tc.permission), func(t *testing.T) {
+			require.Equal(t, tc.can, tc.role.Can(tc.permission))
+		})
+	}
+}
+
+func TestRoleValid(t *testing.T) {
+	for _, role := range Roles() {
+		require.True(t, role.Valid())
+	}
+	require.False(t, Role("").Valid())
+	require.False(t, Role("root").Valid())
+}
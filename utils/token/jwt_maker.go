@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/dhiemaz/bank-api/utils/rbac"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 const minSecretKeyLen = 32
@@ -21,19 +23,33 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-func (jwtMaker *JWTMaker) CreateToken(username string) (string, *Payload, error) {
+func (jwtMaker *JWTMaker) CreateToken(username string, role rbac.Role, sessionID uuid.UUID) (string, *Payload, error) {
 	payload, err := NewPayload(username, AccessTokenExpiration)
 	if err != nil {
 		return "", payload, err
 	}
 
+	payload.Type = TypeAccess
+	payload.Role = role
+	payload.SessionID = sessionID
+
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS512, payload)
 	token, err := jwtToken.SignedString([]byte(jwtMaker.secretKey))
 	return token, payload, err
 }
 
-func (JWTMaker *JWTMaker) CreateRefreshToken(username string) (string, *Payload, error) {
-	return "", nil, nil
+func (jwtMaker *JWTMaker) CreateRefreshToken(username string) (string, *Payload, error) {
+	payload, err := NewPayload(username, RefreshTokenExpiration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	payload.Type = TypeRefresh
+	payload.SessionID = payload.ID
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS512, payload)
+	token, err := jwtToken.SignedString([]byte(jwtMaker.secretKey))
+	return token, payload, err
 }
 
 func (jwtMaker *JWTMaker) VerifyToken(token string) (*Payload, error) {
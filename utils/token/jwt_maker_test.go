@@ -4,19 +4,23 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/random"
+	"github.com/dhiemaz/bank-api/utils/rbac"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
 func TestJWTMaker(t *testing.T) {
-	JWTMaker, err := NewJWTMaker(utils.RandomString(32))
+	JWTMaker, err := NewJWTMaker(random.String(32))
 	require.NoError(t, err)
 
-	username := utils.RandomOwner()
+	username := random.Owner()
 	issuedAt := time.Now()
 
-	token, payload, err := JWTMaker.CreateToken(username)
+	sessionID := uuid.New()
+
+	token, payload, err := JWTMaker.CreateToken(username, rbac.RoleSupport, sessionID)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -25,20 +29,33 @@ func TestJWTMaker(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 	require.NotZero(t, payload.ID)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.Equal(t, rbac.RoleSupport, payload.Role)
+	require.NoError(t, payload.CheckType(TypeAccess))
+
+	refreshToken, refreshPayload, err := JWTMaker.CreateRefreshToken(username)
+	require.NoError(t, err)
+	require.NotEmpty(t, refreshToken)
+	require.Equal(t, refreshPayload.ID, refreshPayload.SessionID)
+
+	refreshPayload, err = JWTMaker.VerifyToken(refreshToken)
+	require.NoError(t, err)
+	require.ErrorIs(t, refreshPayload.CheckType(TypeAccess), ErrTokenInvalid)
+	require.NoError(t, refreshPayload.CheckType(TypeRefresh))
 
 	require.Equal(t, payload.Username, username)
 	require.WithinDuration(t, payload.IssuedAt, issuedAt, time.Second)
 }
 
 func TestJWTMakerInvalid(t *testing.T) {
-	payload, err := NewPayload(utils.RandomOwner(), time.Minute)
+	payload, err := NewPayload(random.Owner(), time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
 	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
 	require.NoError(t, err)
 
-	JWTMaker, err := NewJWTMaker(utils.RandomString(32))
+	JWTMaker, err := NewJWTMaker(random.String(32))
 	require.NoError(t, err)
 
 	payload, err = JWTMaker.VerifyToken(token)
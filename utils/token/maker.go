@@ -1,6 +1,11 @@
 package token
 
-import "time"
+import (
+	"time"
+
+	"github.com/dhiemaz/bank-api/utils/rbac"
+	"github.com/google/uuid"
+)
 
 const (
 	AccessTokenExpiration  = time.Hour
@@ -8,7 +13,7 @@ const (
 )
 
 type Maker interface {
-	CreateToken(username string) (string, *Payload, error)
+	CreateToken(username string, role rbac.Role, sessionID uuid.UUID) (string, *Payload, error)
 	CreateRefreshToken(username string) (string, *Payload, error)
 	VerifyToken(token string) (*Payload, error)
 }
@@ -3,6 +3,8 @@ package token
 import (
 	"fmt"
 
+	"github.com/dhiemaz/bank-api/utils/rbac"
+	"github.com/google/uuid"
 	"github.com/o1egl/paseto"
 )
 
@@ -19,12 +21,16 @@ func NewPasetoMaker(secretKey string) (Maker, error) {
 	return &PasetoMaker{paseto.NewV2(), []byte(secretKey)}, nil
 }
 
-func (pasetoMaker *PasetoMaker) CreateToken(username string) (string, *Payload, error) {
+func (pasetoMaker *PasetoMaker) CreateToken(username string, role rbac.Role, sessionID uuid.UUID) (string, *Payload, error) {
 	payload, err := NewPayload(username, AccessTokenExpiration)
 	if err != nil {
 		return "", payload, err
 	}
 
+	payload.Type = TypeAccess
+	payload.Role = role
+	payload.SessionID = sessionID
+
 	token, err := pasetoMaker.paseto.Encrypt(pasetoMaker.symmetricKey, payload, nil)
 	return token, payload, err
 }
@@ -35,6 +41,9 @@ func (pasetoMaker *PasetoMaker) CreateRefreshToken(username string) (string, *Pa
 		return "", payload, err
 	}
 
+	payload.Type = TypeRefresh
+	payload.SessionID = payload.ID
+
 	token, err := pasetoMaker.paseto.Encrypt(pasetoMaker.symmetricKey, payload, nil)
 	return token, payload, err
 }
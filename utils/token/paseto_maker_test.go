@@ -4,18 +4,22 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dhiemaz/bank-api/utils"
+	"github.com/dhiemaz/bank-api/utils/random"
+	"github.com/dhiemaz/bank-api/utils/rbac"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
 func TestPasetoMaker(t *testing.T) {
-	pasetoMaker, err := NewPasetoMaker(utils.RandomString(32))
+	pasetoMaker, err := NewPasetoMaker(random.String(32))
 	require.NoError(t, err)
 
-	username := utils.RandomOwner()
+	username := random.Owner()
 	issuedAt := time.Now()
 
-	token, payload, err := pasetoMaker.CreateToken(username)
+	sessionID := uuid.New()
+
+	token, payload, err := pasetoMaker.CreateToken(username, rbac.RoleSupport, sessionID)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -24,6 +28,19 @@ func TestPasetoMaker(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 	require.NotZero(t, payload.ID)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.Equal(t, rbac.RoleSupport, payload.Role)
+	require.NoError(t, payload.CheckType(TypeAccess))
+
+	refreshToken, refreshPayload, err := pasetoMaker.CreateRefreshToken(username)
+	require.NoError(t, err)
+	require.NotEmpty(t, refreshToken)
+	require.Equal(t, refreshPayload.ID, refreshPayload.SessionID)
+
+	refreshPayload, err = pasetoMaker.VerifyToken(refreshToken)
+	require.NoError(t, err)
+	require.ErrorIs(t, refreshPayload.CheckType(TypeAccess), ErrTokenInvalid)
+	require.NoError(t, refreshPayload.CheckType(TypeRefresh))
 
 	require.Equal(t, payload.Username, username)
 	require.WithinDuration(t, payload.IssuedAt, issuedAt, time.Second)
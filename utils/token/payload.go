@@ -2,8 +2,10 @@ package token
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/dhiemaz/bank-api/utils/rbac"
 	"github.com/google/uuid"
 )
 
@@ -12,9 +14,22 @@ var (
 	ErrTokenExpired = errors.New("token is expired")
 )
 
+// Type tells access tokens, which authenticate calls, from refresh tokens, which only renew access tokens
+type Type string
+
+const (
+	TypeAccess  Type = "access"
+	TypeRefresh Type = "refresh"
+)
+
 type Payload struct {
-	ID       uuid.UUID `json:"id"`
-	Username string    `json:"username"`
+	ID   uuid.UUID `json:"id"`
+	Type Type      `json:"type"`
+	// SessionID is the session the token was issued for, a refresh token is the session itself
+	SessionID uuid.UUID `json:"session_id"`
+	Username  string    `json:"username"`
+	// Role is only set on access tokens, it is read from users.role at login and renewal
+	Role     rbac.Role `json:"role,omitempty"`
 	IssuedAt time.Time `json:"issued_at"`
 	ExpireAt time.Time `json:"expire_at"`
 }
@@ -33,6 +48,14 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	}, nil
 }
 
+// CheckType reports ErrTokenInvalid for a token of another type, like a refresh token presented to authenticate a call
+func (payload *Payload) CheckType(want Type) error {
+	if payload.Type != want {
+		return fmt.Errorf("%w, %s token expected", ErrTokenInvalid, want)
+	}
+	return nil
+}
+
 func (payload *Payload) Valid() error {
 	if payload.ExpireAt.Before(time.Now()) {
 		return ErrTokenExpired